@@ -41,4 +41,57 @@ type Config struct {
 		UseSSL          bool
 		ExpireSeconds   int64
 	}
+	Local struct {
+		RootDir         string
+		PublicBaseURL   string
+		SignSecret      string
+		ExpireSeconds   int64
+		MaxUploadBytes  int64
+		TransferTimeout int64
+	}
+	Upload struct {
+		PendingTTLSeconds     int64
+		ExpireIntervalSeconds int64
+		TimeoutSeconds        int64 // 完成上传与管理员直传接口的超时
+		MaxUploadBytes        int64 // 管理员直传的请求体上限
+	}
+	Quota struct {
+		DefaultProjectBytes int64
+		DefaultUserBytes    int64
+	}
+	StorageGC struct {
+		Enabled         bool
+		IntervalSeconds int64
+		GraceSeconds    int64
+		Delete          bool
+	}
+	Export struct {
+		TimeoutSeconds int64
+	}
+	Import struct {
+		MaxUploadBytes int64
+		TimeoutSeconds int64
+	}
+	FileLock struct {
+		DefaultTTLSeconds int64
+		MaxTTLSeconds     int64
+	}
+	Trash struct {
+		RetentionSeconds     int64
+		PurgeIntervalSeconds int64
+	}
+	Retention struct {
+		PruneIntervalSeconds int64
+	}
+	Search struct {
+		MaxIndexBytes int64
+	}
+	Thumbnail struct {
+		MaxSourceBytes int64
+		MaxConcurrent  int64
+	}
+	Invitation struct {
+		Secret        string
+		ExpireSeconds int64
+	}
 }
@@ -0,0 +1,44 @@
+package errorx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// CodeError 带业务错误码的错误，由 Handler 输出为 JSON: {"code":...,"message":...,"details":...}
+type CodeError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+func New(status int, code string, message string) *CodeError {
+	return &CodeError{Status: status, Code: code, Message: message}
+}
+
+func (e *CodeError) Error() string {
+	return e.Message
+}
+
+// WithDetails 返回附带详细信息的副本
+func (e *CodeError) WithDetails(details any) *CodeError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// Handler 供 httpx.SetErrorHandlerCtx 使用；非 CodeError 保持原有的 400 纯文本输出
+func Handler(ctx context.Context, err error) (int, any) {
+	_ = ctx
+	var ce *CodeError
+	if errors.As(err, &ce) {
+		status := ce.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		return status, ce
+	}
+	return http.StatusBadRequest, err
+}
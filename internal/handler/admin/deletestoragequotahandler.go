@@ -0,0 +1,31 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/logic/admin"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func DeleteStorageQuotaHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.StorageQuotaReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := admin.NewDeleteStorageQuotaLogic(r.Context(), svcCtx)
+		resp, err := l.DeleteStorageQuota(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
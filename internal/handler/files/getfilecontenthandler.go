@@ -4,10 +4,10 @@
 package files
 
 import (
-	"io"
 	"net/http"
 
 	"github.com/anil-wu/spark-x/internal/logic/files"
+	"github.com/anil-wu/spark-x/internal/storage"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -22,19 +22,14 @@ func GetFileContentHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		}
 
 		l := files.NewGetFileContentLogic(r.Context(), svcCtx)
-		reader, contentType, err := l.GetFileContent(&req)
+		content, err := l.GetFileContent(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
-		defer reader.Close()
 
-		// 设置响应头
-		w.Header().Set("Content-Type", contentType)
-		w.Header().Set("Cache-Control", "private, max-age=3600")
-
-		// 流式传输文件内容
-		w.WriteHeader(http.StatusOK)
-		io.Copy(w, reader)
+		// 当前版本可能变化，缓存需用 ETag 重新验证
+		w.Header().Set("Cache-Control", "private, no-cache")
+		storage.ServeObject(w, r, svcCtx.ObjectStore, content)
 	}
 }
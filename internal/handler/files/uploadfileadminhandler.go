@@ -1,19 +1,18 @@
 package files
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
-	"fmt"
-	"hash"
 	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/logic/files"
+	"github.com/anil-wu/spark-x/internal/model"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -26,36 +25,98 @@ type uploadFileAdminResp struct {
 	ContentType   string `json:"contentType"`
 }
 
+// uploadFormValueLimit 表单中非文件字段的长度上限
+const uploadFormValueLimit = 64 << 10
+
+// UploadFileAdminHandler 流式读取 multipart 请求体：文件内容边计算 sha256 边写入存储中的临时对象，
+// 不在内存或本地临时文件中缓存；得到 hash 后创建引用该临时对象的版本并完成校验
 func UploadFileAdminHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := r.ParseMultipartForm(128 << 20); err != nil {
+		if svcCtx.ObjectStore == nil {
+			httpx.ErrorCtx(r.Context(), w, errors.New("object store not configured"))
+			return
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 
-		formFile, fileHeader, err := r.FormFile("file")
+		stagingKey, err := model.StagingStorageKey()
 		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		staged := false
+		defer func() {
+			if staged {
+				_ = svcCtx.ObjectStore.DeleteObject(context.WithoutCancel(r.Context()), stagingKey)
+			}
+		}()
+
+		values := make(map[string]string)
+		var fileName, partContentType string
+		var sizeBytes int64
+		hasher := sha256.New()
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
+			}
+			if part.FormName() != "file" {
+				value, err := io.ReadAll(io.LimitReader(part, uploadFormValueLimit+1))
+				_ = part.Close()
+				if err != nil {
+					httpx.ErrorCtx(r.Context(), w, err)
+					return
+				}
+				if len(value) > uploadFormValueLimit {
+					httpx.ErrorCtx(r.Context(), w, errors.New(part.FormName()+" is too long"))
+					return
+				}
+				values[part.FormName()] = string(value)
+				continue
+			}
+			if staged {
+				_ = part.Close()
+				httpx.ErrorCtx(r.Context(), w, errors.New("only one file is allowed"))
+				return
+			}
+			fileName = part.FileName()
+			partContentType = strings.TrimSpace(part.Header.Get("Content-Type"))
+			staged = true
+			sizeBytes, err = svcCtx.ObjectStore.PutObject(r.Context(), stagingKey, io.TeeReader(part, hasher))
+			_ = part.Close()
+			if err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
+			}
+		}
+		if !staged {
 			httpx.ErrorCtx(r.Context(), w, errors.New("file is required"))
 			return
 		}
-		defer func() { _ = formFile.Close() }()
 
-		projectId, err := parseInt64OrDefault(r.FormValue("projectId"), 0)
+		projectId, err := parseInt64OrDefault(values["projectId"], 0)
 		if err != nil || projectId < 0 {
 			httpx.ErrorCtx(r.Context(), w, errors.New("projectId is invalid"))
 			return
 		}
 
-		name := strings.TrimSpace(r.FormValue("name"))
+		name := strings.TrimSpace(values["name"])
 		if name == "" {
-			name = fileHeader.Filename
+			name = fileName
 		}
 		if name == "" {
 			httpx.ErrorCtx(r.Context(), w, errors.New("name is required"))
 			return
 		}
 
-		fileFormat := strings.TrimSpace(r.FormValue("fileFormat"))
+		fileFormat := strings.TrimSpace(values["fileFormat"])
 		if fileFormat == "" {
 			fileFormat = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
 		}
@@ -64,38 +125,14 @@ func UploadFileAdminHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		fileCategory := strings.TrimSpace(r.FormValue("fileCategory"))
+		fileCategory := strings.TrimSpace(values["fileCategory"])
 		if fileCategory == "" {
 			fileCategory = guessFileCategory(fileFormat)
 		}
 
-		contentType := strings.TrimSpace(fileHeader.Header.Get("Content-Type"))
+		contentType := partContentType
 		if contentType == "" {
-			contentType = strings.TrimSpace(r.FormValue("contentType"))
-		}
-
-		tmp, err := os.CreateTemp("", "sparkx-upload-*"+filepath.Ext(name))
-		if err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
-			return
-		}
-		tmpPath := tmp.Name()
-		defer func() {
-			_ = tmp.Close()
-			_ = os.Remove(tmpPath)
-		}()
-
-		hasher := sha256.New()
-		sizeBytes, err := copyAndHash(tmp, formFile, hasher)
-		if err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
-			return
-		}
-
-		hashHex := hex.EncodeToString(hasher.Sum(nil))
-		if _, err := tmp.Seek(0, 0); err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
-			return
+			contentType = strings.TrimSpace(values["contentType"])
 		}
 
 		preUploadReq := &types.PreUploadReq{
@@ -104,46 +141,23 @@ func UploadFileAdminHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			FileCategory: fileCategory,
 			FileFormat:   fileFormat,
 			SizeBytes:    sizeBytes,
-			Hash:         hashHex,
+			Hash:         hex.EncodeToString(hasher.Sum(nil)),
 			ContentType:  contentType,
 		}
 
-		preResp, err := files.NewPreUploadFileAdminLogic(r.Context(), svcCtx).PreUploadFileAdmin(preUploadReq)
-		if err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
-			return
-		}
-
-		putReq, err := http.NewRequestWithContext(r.Context(), http.MethodPut, preResp.UploadUrl, tmp)
+		preResp, err := files.NewPreUploadFileAdminLogic(r.Context(), svcCtx).PreUploadStagedFileAdmin(preUploadReq, stagingKey)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
-		putReq.Header.Set("Content-Type", preResp.ContentType)
-		putReq.ContentLength = sizeBytes
 
-		resp, err := http.DefaultClient.Do(putReq)
-		if err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
-			return
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-			requestId := resp.Header.Get("x-oss-request-id")
-			if requestId == "" {
-				requestId = resp.Header.Get("x-amz-request-id")
-			}
-			msg := fmt.Sprintf("oss upload failed: %s", resp.Status)
-			if requestId != "" {
-				msg = msg + ", requestId=" + requestId
+		// 相同内容已存在时版本已生效，否则版本引用临时对象，校验后归入按 hash 寻址的对象
+		if !preResp.SkipUpload {
+			staged = false
+			if err := completeUploadedVersion(r, svcCtx, preResp); err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
 			}
-			if len(body) > 0 {
-				msg = msg + ", detail=" + strings.TrimSpace(string(body))
-			}
-			httpx.ErrorCtx(r.Context(), w, errors.New(msg))
-			return
 		}
 
 		httpx.OkJsonCtx(r.Context(), w, uploadFileAdminResp{
@@ -155,6 +169,15 @@ func UploadFileAdminHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// completeUploadedVersion 上传完成后校验并设为当前版本
+func completeUploadedVersion(r *http.Request, svcCtx *svc.ServiceContext, preResp *types.PreUploadResp) error {
+	_, err := files.NewCompleteFileVersionLogic(r.Context(), svcCtx).CompleteFileVersion(&types.CompleteFileVersionReq{
+		Id:        preResp.FileId,
+		VersionId: preResp.VersionId,
+	})
+	return err
+}
+
 func parseInt64OrDefault(raw string, defaultValue int64) (int64, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -163,11 +186,6 @@ func parseInt64OrDefault(raw string, defaultValue int64) (int64, error) {
 	return strconv.ParseInt(raw, 10, 64)
 }
 
-func copyAndHash(dst *os.File, src io.Reader, hasher hash.Hash) (int64, error) {
-	w := io.MultiWriter(dst, hasher)
-	return io.Copy(w, src)
-}
-
 func guessFileCategory(ext string) string {
 	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
 	case "zip", "rar", "7z", "tar", "gz", "bz2":
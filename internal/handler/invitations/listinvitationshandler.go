@@ -0,0 +1,24 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package invitations
+
+import (
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/logic/invitations"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ListInvitationsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := invitations.NewListInvitationsLogic(r.Context(), svcCtx)
+		resp, err := l.ListInvitations()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
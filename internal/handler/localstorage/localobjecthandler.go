@@ -0,0 +1,107 @@
+package localstorage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/anil-wu/spark-x/internal/logic/localstorage"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// writeLocalObjectError 签名问题返回 403，对象不存在返回 404，其余沿用默认错误处理
+func writeLocalObjectError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrLocalSignatureInvalid), errors.Is(err, storage.ErrLocalSignatureExpired):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, storage.ErrLocalMultipartNotFound):
+		http.Error(w, "object not found", http.StatusNotFound)
+	default:
+		httpx.ErrorCtx(r.Context(), w, err)
+	}
+}
+
+// parseLocalObjectReq 只从 query 读取签名参数，不触碰请求体（上传内容可能是表单或 JSON）
+func parseLocalObjectReq(r *http.Request) (*types.LocalObjectReq, error) {
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return nil, storage.ErrLocalSignatureInvalid
+	}
+	req := &types.LocalObjectReq{
+		Key:         query.Get("key"),
+		Expires:     expires,
+		ContentType: query.Get("contentType"),
+		Signature:   query.Get("signature"),
+		UploadId:    query.Get("uploadId"),
+	}
+	if rawPart := query.Get("partNumber"); rawPart != "" {
+		partNumber, err := strconv.Atoi(rawPart)
+		if err != nil {
+			return nil, storage.ErrLocalSignatureInvalid
+		}
+		req.PartNumber = partNumber
+	}
+	return req, nil
+}
+
+func LocalObjectGetHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := parseLocalObjectReq(r)
+		if err != nil {
+			writeLocalObjectError(w, r, err)
+			return
+		}
+
+		l := localstorage.NewLocalObjectLogic(r.Context(), svcCtx)
+		reader, contentType, contentLength, err := l.OpenObject(req)
+		if err != nil {
+			writeLocalObjectError(w, r, err)
+			return
+		}
+		defer func() { _ = reader.Close() }()
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(contentLength, 10))
+		w.Header().Set("Cache-Control", "private, max-age=3600")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = io.Copy(w, reader)
+	}
+}
+
+func LocalObjectPutHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 只解析 query，避免 JSON 文件的请求体被当作参数读取
+		req, err := parseLocalObjectReq(r)
+		if err != nil {
+			writeLocalObjectError(w, r, err)
+			return
+		}
+
+		l := localstorage.NewLocalObjectLogic(r.Context(), svcCtx)
+		if req.UploadId != "" {
+			// 分片上传：与 S3 一致，通过 ETag 响应头返回分片标识
+			part, err := l.PutPart(req, r.Body)
+			if err != nil {
+				writeLocalObjectError(w, r, err)
+				return
+			}
+			w.Header().Set("ETag", `"`+part.ETag+`"`)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if _, err := l.PutObject(req, r.Header.Get("Content-Type"), r.Body); err != nil {
+			writeLocalObjectError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -1,12 +1,11 @@
 package previews
 
 import (
-	"io"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/logic/previews"
+	"github.com/anil-wu/spark-x/internal/storage"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -22,20 +21,14 @@ func PreviewBuildAssetQueryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc
 
 		requestedPath := strings.TrimPrefix(strings.TrimSpace(req.Path), "/")
 		l := previews.NewPreviewBuildLogic(r.Context(), svcCtx)
-		reader, contentType, contentLength, err := l.OpenAssetObject(req.BuildVersionId, requestedPath)
+		content, err := l.OpenAssetObject(req.BuildVersionId, requestedPath)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
-		defer func() { _ = reader.Close() }()
 
-		w.Header().Set("Cache-Control", "no-store")
-		if strings.TrimSpace(contentType) != "" {
-			w.Header().Set("Content-Type", contentType)
-		}
-		if contentLength > 0 {
-			w.Header().Set("Content-Length", strconv.FormatInt(contentLength, 10))
-		}
-		_, _ = io.Copy(w, reader)
+		// 预览资源随构建更新，每次用 ETag 重新验证，未变化时返回 304
+		w.Header().Set("Cache-Control", "no-cache")
+		storage.ServeObject(w, r, svcCtx.ObjectStore, content)
 	}
 }
@@ -0,0 +1,54 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/logic/projects"
+	"github.com/anil-wu/spark-x/internal/projectarchive"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ExportProjectHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ExportProjectReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := projects.NewExportProjectLogic(r.Context(), svcCtx)
+		export, err := l.ExportProject(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		// 响应头写出后无法再返回错误，中途失败只记录日志，客户端会得到不完整的压缩包
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+		w.Header().Set("Cache-Control", "no-store")
+		if err := projectarchive.WriteZip(r.Context(), flushWriter{w}, svcCtx.ObjectStore, export); err != nil {
+			logx.WithContext(r.Context()).Errorf("[ExportProject] ProjectId=%d stream failed: %v", req.ProjectId, err)
+		}
+	}
+}
+
+// flushWriter 每次写入后立即 Flush，避免超时中间件把整个压缩包缓存在内存中
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,51 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/logic/projects"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// ImportProjectHandler 支持两种请求：multipart/form-data（file 为导出包，可选 name），或 JSON {storageKey, name}
+func ImportProjectHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ImportProjectReq
+		var archive io.ReaderAt
+		var size int64
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			if err := r.ParseMultipartForm(32 << 20); err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
+			}
+			defer func() { _ = r.MultipartForm.RemoveAll() }()
+			formFile, fileHeader, err := r.FormFile("file")
+			if err != nil {
+				httpx.ErrorCtx(r.Context(), w, errors.New("file is required"))
+				return
+			}
+			defer func() { _ = formFile.Close() }()
+			req.Name = strings.TrimSpace(r.FormValue("name"))
+			archive, size = formFile, fileHeader.Size
+		} else if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := projects.NewImportProjectLogic(r.Context(), svcCtx)
+		resp, err := l.ImportProject(&req, archive, size)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
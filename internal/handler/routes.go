@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	admin "github.com/anil-wu/spark-x/internal/handler/admin"
 	admin_auth "github.com/anil-wu/spark-x/internal/handler/admin_auth"
@@ -16,6 +17,8 @@ import (
 	auth "github.com/anil-wu/spark-x/internal/handler/auth"
 	builds "github.com/anil-wu/spark-x/internal/handler/builds"
 	files "github.com/anil-wu/spark-x/internal/handler/files"
+	invitations "github.com/anil-wu/spark-x/internal/handler/invitations"
+	localstorage "github.com/anil-wu/spark-x/internal/handler/localstorage"
 	opencode "github.com/anil-wu/spark-x/internal/handler/opencode"
 	previews "github.com/anil-wu/spark-x/internal/handler/previews"
 	projects "github.com/anil-wu/spark-x/internal/handler/projects"
@@ -269,6 +272,11 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/projects/:id",
 				Handler: withSuperUser(serverCtx, admin.AdminUpdateProjectHandler(serverCtx)),
 			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:id/transfer-ownership",
+				Handler: withSuperUser(serverCtx, admin.AdminTransferOwnershipHandler(serverCtx)),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/software-templates",
@@ -314,6 +322,26 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/users/:id",
 				Handler: withSuperUser(serverCtx, admin.AdminUpdateUserHandler(serverCtx)),
 			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/quotas",
+				Handler: withSuperUser(serverCtx, admin.ListStorageQuotasHandler(serverCtx)),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/quotas/:scope/:scopeId",
+				Handler: withSuperUser(serverCtx, admin.GetStorageQuotaHandler(serverCtx)),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/quotas/:scope/:scopeId",
+				Handler: withSuperUser(serverCtx, admin.UpdateStorageQuotaHandler(serverCtx)),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/quotas/:scope/:scopeId",
+				Handler: withSuperUser(serverCtx, admin.DeleteStorageQuotaHandler(serverCtx)),
+			},
 		},
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
 		rest.WithPrefix("/api/v1/admin"),
@@ -501,6 +529,26 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/files/:id/content",
 				Handler: files.GetFileContentHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/files/:id/thumbnail",
+				Handler: files.GetFileThumbnailHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/:id/copy",
+				Handler: files.CopyFileHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/:id/move",
+				Handler: files.MoveFileHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/files/:id/diff",
+				Handler: files.GetFileDiffHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodGet,
 				Path:    "/files/:id/download",
@@ -516,6 +564,121 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/files/:id/versions",
 				Handler: files.ListFileVersionsHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/files/:id/versions/:versionId/labels",
+				Handler: files.SetVersionLabelsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/retention-policies",
+				Handler: files.ListRetentionPoliciesHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/retention-policies",
+				Handler: files.CreateRetentionPolicyHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/projects/:projectId/retention-policies/:policyId",
+				Handler: files.UpdateRetentionPolicyHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/projects/:projectId/retention-policies/:policyId",
+				Handler: files.DeleteRetentionPolicyHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/retention-policies/prune",
+				Handler: files.PruneVersionsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/trash",
+				Handler: files.ListTrashHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/trash/:fileId/restore",
+				Handler: files.RestoreTrashFileHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/projects/:projectId/trash/:fileId",
+				Handler: files.PurgeTrashFileHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/:id/lock",
+				Handler: files.AcquireFileLockHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/files/:id/lock",
+				Handler: files.RenewFileLockHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/files/:id/lock",
+				Handler: files.ReleaseFileLockHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/locks",
+				Handler: files.ListFileLocksHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/changesets",
+				Handler: files.CreateChangesetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/changesets",
+				Handler: files.ListChangesetsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/changesets/:id",
+				Handler: files.GetChangesetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/changesets/:id/commit",
+				Handler: files.CommitChangesetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/changesets/:id/abort",
+				Handler: files.AbortChangesetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/changesets/:id/rollback",
+				Handler: files.RollbackChangesetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/files/multipart/:id",
+				Handler: files.AbortMultipartUploadHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/files/multipart/:id/parts",
+				Handler: files.ListUploadedPartsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/multipart/:id/parts/:partNumber/presign",
+				Handler: files.PresignUploadPartHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/multipart/initiate",
+				Handler: files.InitiateMultipartUploadHandler(serverCtx),
+			},
 			{
 				Method:  http.MethodPost,
 				Path:    "/files/preupload",
@@ -526,6 +689,41 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/projects/:projectId/files",
 				Handler: files.ListProjectFilesHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/files/by-path",
+				Handler: files.GetFileByPathHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/files/search",
+				Handler: files.SearchProjectFilesHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/folders",
+				Handler: files.CreateFolderHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/projects/:projectId/folders/:folderId",
+				Handler: files.RenameFolderHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:projectId/folders/:folderId/move",
+				Handler: files.MoveFolderHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/projects/:projectId/folders/:folderId",
+				Handler: files.DeleteFolderHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/folders/:folderId/children",
+				Handler: files.ListFolderChildrenHandler(serverCtx),
+			},
 		},
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
 		rest.WithPrefix("/api/v1"),
@@ -550,11 +748,6 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/files/preupload",
 				Handler: withSuperUser(serverCtx, files.PreUploadFileAdminHandler(serverCtx)),
 			},
-			{
-				Method:  http.MethodPost,
-				Path:    "/files/upload",
-				Handler: withSuperUser(serverCtx, files.UploadFileAdminHandler(serverCtx)),
-			},
 			{
 				Method:  http.MethodGet,
 				Path:    "/files/:id/versions",
@@ -570,6 +763,124 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/admin"),
 	)
 
+	// 完成上传要读取整个对象校验 sha256，管理员直传还要接收完整文件，耗时与文件大小相关，不受全局超时限制
+	uploadRouteOpts := []rest.RouteOption{
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1"),
+	}
+	adminUploadRouteOpts := []rest.RouteOption{
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/admin"),
+	}
+	if serverCtx.Config.Upload.TimeoutSeconds > 0 {
+		timeout := rest.WithTimeout(time.Duration(serverCtx.Config.Upload.TimeoutSeconds) * time.Second)
+		uploadRouteOpts = append(uploadRouteOpts, timeout)
+		adminUploadRouteOpts = append(adminUploadRouteOpts, timeout)
+	}
+	if serverCtx.Config.Upload.MaxUploadBytes > 0 {
+		adminUploadRouteOpts = append(adminUploadRouteOpts, rest.WithMaxBytes(serverCtx.Config.Upload.MaxUploadBytes))
+	}
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/:id/versions/:versionId/complete",
+				Handler: files.CompleteFileVersionHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/multipart/:id/complete",
+				Handler: files.CompleteMultipartUploadHandler(serverCtx),
+			},
+		},
+		uploadRouteOpts...,
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/upload",
+				Handler: withSuperUser(serverCtx, files.UploadFileAdminHandler(serverCtx)),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/files/:id/versions/:versionId/complete",
+				Handler: withSuperUser(serverCtx, files.CompleteFileVersionHandler(serverCtx)),
+			},
+		},
+		adminUploadRouteOpts...,
+	)
+
+	// 导出按流式输出，耗时与项目大小相关，不受全局超时限制
+	exportRouteOpts := []rest.RouteOption{
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1"),
+	}
+	if serverCtx.Config.Export.TimeoutSeconds > 0 {
+		exportRouteOpts = append(exportRouteOpts, rest.WithTimeout(time.Duration(serverCtx.Config.Export.TimeoutSeconds)*time.Second))
+	}
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:projectId/export",
+				Handler: projects.ExportProjectHandler(serverCtx),
+			},
+		},
+		exportRouteOpts...,
+	)
+
+	// 导入需要接收完整的导出包
+	importRouteOpts := []rest.RouteOption{
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1"),
+	}
+	if serverCtx.Config.Import.MaxUploadBytes > 0 {
+		importRouteOpts = append(importRouteOpts, rest.WithMaxBytes(serverCtx.Config.Import.MaxUploadBytes))
+	}
+	if serverCtx.Config.Import.TimeoutSeconds > 0 {
+		importRouteOpts = append(importRouteOpts, rest.WithTimeout(time.Duration(serverCtx.Config.Import.TimeoutSeconds)*time.Second))
+	}
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/import",
+				Handler: projects.ImportProjectHandler(serverCtx),
+			},
+		},
+		importRouteOpts...,
+	)
+
+	localStorageRouteOpts := []rest.RouteOption{rest.WithPrefix("/api/v1")}
+	if serverCtx.Config.Local.MaxUploadBytes > 0 {
+		localStorageRouteOpts = append(localStorageRouteOpts, rest.WithMaxBytes(serverCtx.Config.Local.MaxUploadBytes))
+	}
+	if serverCtx.Config.Local.TransferTimeout > 0 {
+		localStorageRouteOpts = append(localStorageRouteOpts, rest.WithTimeout(time.Duration(serverCtx.Config.Local.TransferTimeout)*time.Second))
+	}
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodGet,
+				Path:    "/storage/local/object",
+				Handler: localstorage.LocalObjectGetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodHead,
+				Path:    "/storage/local/object",
+				Handler: localstorage.LocalObjectGetHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/storage/local/object",
+				Handler: localstorage.LocalObjectPutHandler(serverCtx),
+			},
+		},
+		localStorageRouteOpts...,
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -602,6 +913,68 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/projects/:id/invite",
 				Handler: projects.InviteMemberHandler(serverCtx),
 			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:id/members",
+				Handler: projects.ListProjectMembersHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPut,
+				Path:    "/projects/:id/members/:userId",
+				Handler: projects.UpdateProjectMemberHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/projects/:id/members/:userId",
+				Handler: projects.RemoveProjectMemberHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:id/leave",
+				Handler: projects.LeaveProjectHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:id/transfer-ownership",
+				Handler: projects.TransferOwnershipHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/projects/:id/invitations",
+				Handler: projects.CreateInvitationHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/projects/:id/invitations",
+				Handler: projects.ListProjectInvitationsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodDelete,
+				Path:    "/projects/:id/invitations/:invitationId",
+				Handler: projects.RevokeInvitationHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodGet,
+				Path:    "/invitations",
+				Handler: invitations.ListInvitationsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/invitations/:token/accept",
+				Handler: invitations.AcceptInvitationHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/invitations/:token/decline",
+				Handler: invitations.DeclineInvitationHandler(serverCtx),
+			},
 		},
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
 		rest.WithPrefix("/api/v1"),
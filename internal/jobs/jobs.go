@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// Start 启动服务内的后台定时任务，随进程退出
+func Start(svcCtx *svc.ServiceContext) {
+	if svcCtx == nil || svcCtx.DB == nil {
+		return
+	}
+
+	interval := 10 * time.Minute
+	if svcCtx.Config.Upload.ExpireIntervalSeconds > 0 {
+		interval = time.Duration(svcCtx.Config.Upload.ExpireIntervalSeconds) * time.Second
+	}
+	runEvery("expire-pending-versions", interval, func(ctx context.Context) error {
+		_, err := ExpirePendingVersions(ctx, svcCtx)
+		return err
+	})
+
+	trashInterval := time.Hour
+	if svcCtx.Config.Trash.PurgeIntervalSeconds > 0 {
+		trashInterval = time.Duration(svcCtx.Config.Trash.PurgeIntervalSeconds) * time.Second
+	}
+	runEvery("purge-trash", trashInterval, func(ctx context.Context) error {
+		_, err := PurgeTrash(ctx, svcCtx)
+		return err
+	})
+
+	pruneInterval := 24 * time.Hour
+	if svcCtx.Config.Retention.PruneIntervalSeconds > 0 {
+		pruneInterval = time.Duration(svcCtx.Config.Retention.PruneIntervalSeconds) * time.Second
+	}
+	runEvery("prune-versions", pruneInterval, func(ctx context.Context) error {
+		_, err := PruneVersions(ctx, svcCtx)
+		return err
+	})
+
+	gc := svcCtx.Config.StorageGC
+	if gc.Enabled && svcCtx.ObjectStore != nil {
+		gcInterval := 24 * time.Hour
+		if gc.IntervalSeconds > 0 {
+			gcInterval = time.Duration(gc.IntervalSeconds) * time.Second
+		}
+		runEvery("storage-gc", gcInterval, func(ctx context.Context) error {
+			_, err := RunStorageGC(ctx, svcCtx, StorageGCOptions{
+				Grace:  svcCtx.StorageGCGrace(),
+				Delete: gc.Delete,
+			})
+			return err
+		})
+	}
+}
+
+// runEvery 按固定间隔执行任务，单次执行出错只记录日志
+func runEvery(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := fn(ctx); err != nil {
+				logx.Errorf("[Jobs] %s failed: %v", name, err)
+			}
+			cancel()
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+const expirePendingBatchSize = 500
+
+// ExpirePendingVersions 将超过有效期仍未 complete 的版本置为 failed，取消关联的分片上传，
+// 释放版本的内容引用并删除已无引用的对象；仍保留 storage_key 的旧 failed 版本一并释放。
+func ExpirePendingVersions(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	cutoff := time.Now().Add(-svcCtx.PendingVersionTTL())
+
+	var versions []model.FileVersions
+	if err := svcCtx.DB.WithContext(ctx).
+		Where("(status = ? AND created_at < ?) OR (status = ? AND storage_key <> '')",
+			model.FileVersionStatusPending, cutoff, model.FileVersionStatusFailed).
+		Order("id").
+		Limit(expirePendingBatchSize).
+		Find(&versions).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, v := range versions {
+		var uploads []model.MultipartUploads
+		if err := svcCtx.DB.WithContext(ctx).
+			Where("file_version_id = ? AND status = ?", v.Id, model.MultipartStatusUploading).
+			Find(&uploads).Error; err != nil {
+			return expired, err
+		}
+		for _, u := range uploads {
+			if svcCtx.ObjectStore != nil {
+				if err := svcCtx.ObjectStore.AbortMultipartUpload(ctx, u.StorageKey, u.UploadId); err != nil {
+					logx.WithContext(ctx).Errorf("[Jobs] abort multipart upload %d failed: %v", u.Id, err)
+					continue
+				}
+			}
+			if err := svcCtx.DB.WithContext(ctx).Model(&model.MultipartUploads{}).
+				Where("id = ?", u.Id).
+				Update("status", model.MultipartStatusAborted).Error; err != nil {
+				return expired, err
+			}
+		}
+
+		var releasedKeys []string
+		if err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var err error
+			releasedKeys, err = model.FailFileVersion(tx, v.Id)
+			return err
+		}); err != nil {
+			return expired, err
+		}
+		if svcCtx.ObjectStore != nil {
+			for _, key := range releasedKeys {
+				if err := svcCtx.ObjectStore.DeleteObject(ctx, key); err != nil {
+					logx.WithContext(ctx).Errorf("[Jobs] delete object %s failed: %v", key, err)
+				}
+			}
+		}
+		if v.Status == model.FileVersionStatusPending {
+			expired++
+		}
+	}
+	if expired > 0 {
+		logx.WithContext(ctx).Infof("[Jobs] expired %d pending file versions", expired)
+	}
+	return expired, nil
+}
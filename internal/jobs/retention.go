@@ -0,0 +1,175 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrunedFile struct {
+	FileId         uint64   `json:"fileId"`
+	Path           string   `json:"path"`
+	PolicyId       uint64   `json:"policyId"`
+	VersionNumbers []uint64 `json:"versionNumbers"`
+	SizeBytes      int64    `json:"sizeBytes"`
+}
+
+// PruneReport 版本清理结果：PrunedBytes 为删除版本的大小之和（即释放的配额），
+// 内容按 sha256 去重，实际删除的存储对象见 DeletedObjects
+type PruneReport struct {
+	ProjectId      uint64       `json:"projectId"`
+	DryRun         bool         `json:"dryRun"`
+	ScannedFiles   int          `json:"scannedFiles"`
+	PrunedVersions int          `json:"prunedVersions"`
+	PrunedBytes    int64        `json:"prunedBytes"`
+	DeletedObjects int          `json:"deletedObjects"`
+	Files          []PrunedFile `json:"files"`
+}
+
+// PruneProjectVersions 按项目的保留策略清理多余版本。当前版本、带标签的版本以及被软件清单、构建版本、
+// 发布或变更集引用的版本始终保留；回收站中的文件不处理。dryRun 时只生成报告
+func PruneProjectVersions(ctx context.Context, svcCtx *svc.ServiceContext, projectId uint64, dryRun bool) (*PruneReport, error) {
+	db := svcCtx.DB.WithContext(ctx)
+	report := &PruneReport{ProjectId: projectId, DryRun: dryRun, Files: make([]PrunedFile, 0)}
+
+	var policies []model.VersionRetentionPolicies
+	if err := db.Where("project_id = ?", projectId).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return report, nil
+	}
+	paths, err := model.FolderPaths(db, projectId)
+	if err != nil {
+		return nil, err
+	}
+	var files []model.Files
+	if err := db.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ?", projectId).
+		Select("files.*").
+		Order("files.id").
+		Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range files {
+		file := &files[i]
+		filePath := model.JoinFolderPath(paths[file.FolderId], file.Name)
+		policy := model.MatchRetentionPolicy(policies, filePath)
+		if policy == nil {
+			continue
+		}
+		report.ScannedFiles++
+
+		var pruned []model.FileVersions
+		var releasedKeys []string
+		prune := func(tx *gorm.DB) error {
+			// 锁住文件行，与预上传、回滚等切换当前版本的操作串行
+			var locked model.Files
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", file.Id).First(&locked).Error; err != nil {
+				return err
+			}
+			var versions []model.FileVersions
+			if err := tx.Where("file_id = ? AND status = ?", file.Id, model.FileVersionStatusReady).Find(&versions).Error; err != nil {
+				return err
+			}
+			candidates := policy.PrunableVersions(versions, map[uint64]bool{locked.CurrentVersionId: true}, now)
+			if len(candidates) == 0 {
+				return nil
+			}
+			candidateIds := make([]uint64, 0, len(candidates))
+			for _, v := range candidates {
+				candidateIds = append(candidateIds, v.Id)
+			}
+			referenced, err := model.ReferencedVersionIds(tx, candidateIds)
+			if err != nil {
+				return err
+			}
+			for _, v := range candidates {
+				if !referenced[v.Id] {
+					pruned = append(pruned, v)
+				}
+			}
+			if dryRun || len(pruned) == 0 {
+				return nil
+			}
+			prunedIds := make([]uint64, 0, len(pruned))
+			for _, v := range pruned {
+				prunedIds = append(prunedIds, v.Id)
+			}
+			if releasedKeys, err = model.ReleaseFileBlobs(tx, pruned); err != nil {
+				return err
+			}
+			if err := tx.Where("version_id IN ?", prunedIds).Delete(&model.FileVersionContents{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", prunedIds).Delete(&model.FileVersions{}).Error
+		}
+		if dryRun {
+			err = prune(db)
+		} else {
+			err = db.Transaction(prune)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(pruned) == 0 {
+			continue
+		}
+
+		entry := PrunedFile{FileId: file.Id, Path: filePath, PolicyId: policy.Id, VersionNumbers: make([]uint64, 0, len(pruned))}
+		for _, v := range pruned {
+			entry.VersionNumbers = append(entry.VersionNumbers, v.VersionNumber)
+			entry.SizeBytes += int64(v.SizeBytes)
+		}
+		report.Files = append(report.Files, entry)
+		report.PrunedVersions += len(pruned)
+		report.PrunedBytes += entry.SizeBytes
+
+		// 存储未配置时对象留给存储清理任务
+		if svcCtx.ObjectStore != nil {
+			for _, key := range releasedKeys {
+				if err := svcCtx.ObjectStore.DeleteObject(ctx, key); err != nil {
+					logx.WithContext(ctx).Errorf("[Jobs] delete object %s failed: %v", key, err)
+					continue
+				}
+				report.DeletedObjects++
+			}
+		}
+	}
+
+	if !dryRun && report.PrunedVersions > 0 {
+		logx.WithContext(ctx).Infof("[Jobs] pruned project %d: files=%d versions=%d bytes=%d objects=%d",
+			projectId, len(report.Files), report.PrunedVersions, report.PrunedBytes, report.DeletedObjects)
+	}
+	return report, nil
+}
+
+// PruneVersions 对所有配置了保留策略的项目执行版本清理，归档项目只读，跳过
+func PruneVersions(ctx context.Context, svcCtx *svc.ServiceContext) ([]*PruneReport, error) {
+	var projectIds []uint64
+	if err := svcCtx.DB.WithContext(ctx).Model(&model.VersionRetentionPolicies{}).
+		Joins("JOIN projects ON projects.id = version_retention_policies.project_id").
+		Where("projects.status <> ?", model.ProjectStatusArchived).
+		Distinct().Order("version_retention_policies.project_id").
+		Pluck("version_retention_policies.project_id", &projectIds).Error; err != nil {
+		return nil, err
+	}
+	reports := make([]*PruneReport, 0, len(projectIds))
+	for _, projectId := range projectIds {
+		report, err := PruneProjectVersions(ctx, svcCtx, projectId, false)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,203 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/thumbnail"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// legacyAssetKey 内容寻址之前的文件存储路径: projectId/userId/assets/...
+var legacyAssetKey = regexp.MustCompile(`^\d+/\d+/assets/`)
+
+// StorageGCOptions 存储清理参数；Delete 为 false 时只生成报告
+type StorageGCOptions struct {
+	Grace  time.Duration
+	Delete bool
+}
+
+type OrphanObject struct {
+	Key          string    `json:"key"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	LastModified time.Time `json:"lastModified"`
+	Deleted      bool      `json:"deleted"`
+}
+
+type MissingObject struct {
+	Kind       string `json:"kind"` // file_version / build_preview
+	Id         uint64 `json:"id"`
+	StorageKey string `json:"storageKey"`
+}
+
+// StorageGCReport 对账结果：OrphanObjects 为存储中无记录引用的对象，MissingObjects 为记录引用但存储中不存在的对象
+type StorageGCReport struct {
+	ScannedObjects   int             `json:"scannedObjects"`
+	UnmanagedObjects int             `json:"unmanagedObjects"`
+	OrphanObjects    []OrphanObject  `json:"orphanObjects"`
+	MissingObjects   []MissingObject `json:"missingObjects"`
+	DeletedObjects   int             `json:"deletedObjects"`
+	ReclaimedBytes   int64           `json:"reclaimedBytes"`
+}
+
+// isManagedKey 只有服务自身生成的路径才参与清理，其它对象只计数不处理
+func isManagedKey(key string) bool {
+	return strings.HasPrefix(key, "blobs/") ||
+		strings.HasPrefix(key, model.StagingKeyPrefix) ||
+		strings.HasPrefix(key, "previews/") ||
+		legacyAssetKey.MatchString(key)
+}
+
+// keyDirs 返回对象 key 所有上级目录（带结尾 /），用于匹配预览前缀
+func keyDirs(key string) []string {
+	dirs := make([]string, 0, 4)
+	for i := 0; i < len(key); i++ {
+		if key[i] == '/' {
+			dirs = append(dirs, key[:i+1])
+		}
+	}
+	return dirs
+}
+
+// RunStorageGC 将存储中的对象与 file_versions.storage_key、build_versions.preview_storage_prefix 双向对账，
+// 超过宽限期的孤儿对象在 opts.Delete 时删除；缺失对象只报告
+func RunStorageGC(ctx context.Context, svcCtx *svc.ServiceContext, opts StorageGCOptions) (*StorageGCReport, error) {
+	if svcCtx == nil || svcCtx.DB == nil {
+		return nil, errors.New("database not configured")
+	}
+	if svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+	db := svcCtx.DB.WithContext(ctx)
+
+	objects, err := svcCtx.ObjectStore.ListObjects(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// 先列对象再读记录：列举期间新写入的记录只会让对象被视为已引用
+	referenced := make(map[string]struct{})
+	var versionKeys []string
+	// failed 版本不再引用对象，旧记录残留的 storage_key 不计入
+	if err := db.Model(&model.FileVersions{}).Where("status <> ?", model.FileVersionStatusFailed).Distinct().Pluck("storage_key", &versionKeys).Error; err != nil {
+		return nil, err
+	}
+	for _, k := range versionKeys {
+		referenced[k] = struct{}{}
+	}
+	var blobKeys []string
+	if err := db.Model(&model.FileBlobs{}).Where("ref_count > 0").Pluck("storage_key", &blobKeys).Error; err != nil {
+		return nil, err
+	}
+	for _, k := range blobKeys {
+		referenced[k] = struct{}{}
+	}
+	var uploadingKeys []string
+	if err := db.Model(&model.MultipartUploads{}).Where("status = ?", model.MultipartStatusUploading).Pluck("storage_key", &uploadingKeys).Error; err != nil {
+		return nil, err
+	}
+	for _, k := range uploadingKeys {
+		referenced[k] = struct{}{}
+	}
+
+	var builds []model.BuildVersions
+	if err := db.Select("id", "preview_storage_prefix").Where("preview_storage_prefix <> ''").Find(&builds).Error; err != nil {
+		return nil, err
+	}
+	previewPrefixes := make(map[string]struct{}, len(builds))
+	for _, b := range builds {
+		previewPrefixes[b.PreviewStoragePrefix] = struct{}{}
+	}
+
+	report := &StorageGCReport{
+		OrphanObjects:  make([]OrphanObject, 0),
+		MissingObjects: make([]MissingObject, 0),
+	}
+	present := make(map[string]struct{}, len(objects))
+	presentDirs := make(map[string]struct{})
+	cutoff := time.Now().Add(-opts.Grace)
+	for _, obj := range objects {
+		report.ScannedObjects++
+		present[obj.Key] = struct{}{}
+		dirs := keyDirs(obj.Key)
+		for _, d := range dirs {
+			presentDirs[d] = struct{}{}
+		}
+		if !isManagedKey(obj.Key) {
+			report.UnmanagedObjects++
+			continue
+		}
+		if _, ok := referenced[obj.Key]; ok {
+			continue
+		}
+		// 缩略图跟随原对象的引用状态
+		if source, ok := thumbnail.SourceKey(obj.Key); ok {
+			if _, ok := referenced[source]; ok {
+				continue
+			}
+		}
+		if hasPreviewPrefix(dirs, previewPrefixes) {
+			continue
+		}
+		report.OrphanObjects = append(report.OrphanObjects, orphanFrom(obj))
+	}
+
+	if opts.Delete {
+		for i := range report.OrphanObjects {
+			o := &report.OrphanObjects[i]
+			if o.LastModified.After(cutoff) {
+				continue
+			}
+			if err := svcCtx.ObjectStore.DeleteObject(ctx, o.Key); err != nil {
+				logx.WithContext(ctx).Errorf("[StorageGC] delete %s failed: %v", o.Key, err)
+				continue
+			}
+			o.Deleted = true
+			report.DeletedObjects++
+			report.ReclaimedBytes += o.SizeBytes
+		}
+	}
+
+	var readyVersions []model.FileVersions
+	if err := db.Select("id", "storage_key").Where("status = ?", model.FileVersionStatusReady).Find(&readyVersions).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range readyVersions {
+		if _, ok := present[v.StorageKey]; !ok {
+			report.MissingObjects = append(report.MissingObjects, MissingObject{Kind: "file_version", Id: v.Id, StorageKey: v.StorageKey})
+		}
+	}
+	for _, b := range builds {
+		if _, ok := presentDirs[b.PreviewStoragePrefix]; !ok {
+			report.MissingObjects = append(report.MissingObjects, MissingObject{Kind: "build_preview", Id: b.Id, StorageKey: b.PreviewStoragePrefix})
+		}
+	}
+
+	logx.WithContext(ctx).Infof("[StorageGC] scanned=%d orphans=%d deleted=%d reclaimed=%d missing=%d",
+		report.ScannedObjects, len(report.OrphanObjects), report.DeletedObjects, report.ReclaimedBytes, len(report.MissingObjects))
+	return report, nil
+}
+
+func hasPreviewPrefix(dirs []string, prefixes map[string]struct{}) bool {
+	for _, d := range dirs {
+		if _, ok := prefixes[d]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func orphanFrom(obj storage.ObjectInfo) OrphanObject {
+	return OrphanObject{
+		Key:          obj.Key,
+		SizeBytes:    obj.SizeBytes,
+		LastModified: obj.LastModified,
+	}
+}
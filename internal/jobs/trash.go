@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+const purgeTrashBatchSize = 200
+
+// PurgeTrash 彻底删除在回收站中超过保留期的文件，释放版本引用并删除已无引用的对象，返回删除的文件数
+func PurgeTrash(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	cutoff := time.Now().Add(-svcCtx.TrashRetention())
+	purged := 0
+	for {
+		var fileIds []uint64
+		if err := svcCtx.DB.WithContext(ctx).Unscoped().Model(&model.Files{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("id").
+			Limit(purgeTrashBatchSize).
+			Pluck("id", &fileIds).Error; err != nil {
+			return purged, err
+		}
+		if len(fileIds) == 0 {
+			break
+		}
+
+		var releasedKeys []string
+		err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			keys, err := model.PurgeFiles(tx, fileIds)
+			releasedKeys = keys
+			return err
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged += len(fileIds)
+
+		// 存储未配置时对象留给存储清理任务
+		if svcCtx.ObjectStore != nil {
+			for _, key := range releasedKeys {
+				if err := svcCtx.ObjectStore.DeleteObject(ctx, key); err != nil {
+					logx.WithContext(ctx).Errorf("[Jobs] delete object %s failed: %v", key, err)
+				}
+			}
+		}
+		if len(fileIds) < purgeTrashBatchSize {
+			break
+		}
+	}
+	if purged > 0 {
+		logx.WithContext(ctx).Infof("[Jobs] purged %d files from trash", purged)
+	}
+	return purged, nil
+}
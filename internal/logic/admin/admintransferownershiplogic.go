@@ -0,0 +1,74 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type AdminTransferOwnershipLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAdminTransferOwnershipLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AdminTransferOwnershipLogic {
+	return &AdminTransferOwnershipLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AdminTransferOwnership 管理员转让项目，用于原 owner 已离职等情况；新 owner 不是成员时直接加入
+func (l *AdminTransferOwnershipLogic) AdminTransferOwnership(req *types.TransferOwnershipReq) (resp *types.TransferOwnershipResp, err error) {
+	adminIdNumber, ok := l.ctx.Value("adminId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	adminId, _ := adminIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.NewOwnerId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	previousRole := strings.TrimSpace(req.PreviousOwnerRole)
+	if previousRole == "" {
+		previousRole = permission.RoleAdmin
+	}
+	if !permission.ValidPreviousOwnerRole(previousRole) {
+		return nil, permission.ErrInvalidOwnerRole
+	}
+	if _, err := l.svcCtx.UsersModel.FindOne(l.ctx, uint64(req.NewOwnerId)); err != nil {
+		return nil, err
+	}
+
+	var previousOwnerId uint64
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		previousOwnerId, err = permission.TransferOwnership(tx, uint64(req.ProjectId), uint64(req.NewOwnerId), previousRole, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[AdminTransferOwnership] AdminId=%d transferred project %d from user %d to user %d, previous owner role %s",
+		adminId, req.ProjectId, previousOwnerId, req.NewOwnerId, previousRole)
+	return &types.TransferOwnershipResp{
+		ProjectId:         req.ProjectId,
+		OwnerId:           req.NewOwnerId,
+		PreviousOwnerId:   int64(previousOwnerId),
+		PreviousOwnerRole: previousRole,
+	}, nil
+}
@@ -69,6 +69,7 @@ func (l *LoginLogic) loginByEmail(req *types.LoginReq) (*types.LoginResp, error)
 		if err != nil {
 			return nil, err
 		}
+		l.attachInvitations(newUser)
 
 		// generate token
 		token, err := l.generateToken(int64(newUser.Id), newUser.IsSuper)
@@ -88,6 +89,7 @@ func (l *LoginLogic) loginByEmail(req *types.LoginReq) (*types.LoginResp, error)
 	if user.PasswordHash != passHash {
 		return nil, model.InputParamInvalid
 	}
+	l.attachInvitations(user)
 
 	// generate token
 	token, err := l.generateToken(int64(user.Id), user.IsSuper)
@@ -206,6 +208,8 @@ func (l *LoginLogic) loginByGoogle(req *types.LoginReq) (*types.LoginResp, error
 		}
 	}
 
+	l.attachInvitations(user)
+
 	// 3. Generate JWT
 	token, err := l.generateToken(int64(user.Id), user.IsSuper)
 	if err != nil {
@@ -220,6 +224,21 @@ func (l *LoginLogic) loginByGoogle(req *types.LoginReq) (*types.LoginResp, error
 	}, nil
 }
 
+// attachInvitations 把发给该邮箱的待处理邀请关联到用户，失败不影响登录
+func (l *LoginLogic) attachInvitations(user *model.Users) {
+	if l.svcCtx.DB == nil {
+		return
+	}
+	n, err := model.AttachInvitations(l.svcCtx.DB.WithContext(l.ctx), user.Id, user.Email)
+	if err != nil {
+		l.Errorf("[Login] Failed to attach invitations for user %d: %v", user.Id, err)
+		return
+	}
+	if n > 0 {
+		l.Infof("[Login] Attached %d pending invitations to user %d", n, user.Id)
+	}
+}
+
 func (l *LoginLogic) generateToken(userId int64, isSuper bool) (string, error) {
 	now := time.Now().Unix()
 	accessExpire := l.svcCtx.Config.Auth.AccessExpire
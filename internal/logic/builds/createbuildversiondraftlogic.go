@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -54,15 +55,9 @@ func (l *CreateBuildVersionDraftLogic) CreateBuildVersionDraft(req *types.Create
 		return nil, errors.New("db not configured")
 	}
 
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-		Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.BuildWrite); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	var sm model.SoftwareManifests
 	if err := l.svcCtx.DB.WithContext(l.ctx).Where("`id` = ?", req.SoftwareManifestId).First(&sm).Error; err != nil {
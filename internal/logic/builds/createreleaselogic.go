@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -77,14 +78,13 @@ func (l *CreateReleaseLogic) CreateRelease(req *types.CreateReleaseReq) (resp *t
 		publishedAt = sql.NullTime{Time: t, Valid: true}
 	}
 
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-		Count(&count).Error; err != nil {
-		return nil, err
+	// prod 发布需要 owner 或 admin
+	action := permission.ReleaseCreate
+	if channel == "prod" {
+		action = permission.ReleaseCreateProd
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, action); err != nil {
+		return nil, err
 	}
 
 	var bv model.BuildVersions
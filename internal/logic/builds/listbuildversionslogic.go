@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -43,15 +44,9 @@ func (l *ListBuildVersionsLogic) ListBuildVersions(req *types.ListBuildVersionsR
 		return nil, errors.New("db not configured")
 	}
 
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-		Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	page := req.Page
 	if page <= 0 {
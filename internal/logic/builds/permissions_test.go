@@ -0,0 +1,93 @@
+package builds
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestCreateReleaseRoles(t *testing.T) {
+	cases := []struct {
+		channel string
+		allowed map[string]bool
+	}{
+		{"beta", map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true, permission.RoleDeveloper: true}},
+		{"prod", map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true}},
+	}
+	for _, c := range cases {
+		t.Run(c.channel, func(t *testing.T) {
+			permissiontest.CheckRoles(t, c.allowed, func(role string) error {
+				db, err := permissiontest.OpenDB(role)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+				_, err = NewCreateReleaseLogic(ctx, &svc.ServiceContext{DB: db}).CreateRelease(&types.CreateReleaseReq{
+					ProjectId:                    1,
+					BuildVersionId:               1,
+					ReleaseManifestFileId:        1,
+					ReleaseManifestFileVersionId: 1,
+					Name:                         "r1",
+					Channel:                      c.channel,
+					Platform:                     "web",
+				})
+				return err
+			})
+		})
+	}
+}
+
+func TestBuildVersionRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: true,
+		permission.RoleViewer:    false,
+	}
+	calls := map[string]func(ctx context.Context, svcCtx *svc.ServiceContext) error{
+		"CreateBuildVersion": func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCreateBuildVersionLogic(ctx, svcCtx).CreateBuildVersion(&types.CreateBuildVersionReq{
+				ProjectId:                 1,
+				SoftwareManifestId:        1,
+				BuildVersionFileId:        1,
+				BuildVersionFileVersionId: 1,
+			})
+			return err
+		},
+		"CreateBuildVersionDraft": func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCreateBuildVersionDraftLogic(ctx, svcCtx).CreateBuildVersionDraft(&types.CreateBuildVersionDraftReq{
+				ProjectId:          1,
+				SoftwareManifestId: 1,
+			})
+			return err
+		},
+		"UpdateBuildVersion": func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewUpdateBuildVersionLogic(ctx, svcCtx).UpdateBuildVersion(&types.UpdateBuildVersionReq{Id: 3})
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			permissiontest.CheckRoles(t, allowed, func(role string) error {
+				// 构建版本 3 属于项目 1
+				db, err := permissiontest.Open(permissiontest.Options{
+					Role: role,
+					Tables: map[string]permissiontest.Table{
+						"build_versions": {Columns: []string{"id", "project_id"}, Rows: [][]driver.Value{{int64(3), int64(1)}}},
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+				return call(ctx, &svc.ServiceContext{DB: db})
+			})
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -59,15 +60,9 @@ func (l *UpdateBuildVersionLogic) UpdateBuildVersion(req *types.UpdateBuildVersi
 		return nil, err
 	}
 
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", bv.ProjectId, userId).
-		Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, bv.ProjectId, userId, permission.BuildWrite); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	updates := map[string]any{}
 	if strings.TrimSpace(req.PreviewStoragePrefix) != "" {
@@ -0,0 +1,152 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type AbortChangesetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAbortChangesetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AbortChangesetLogic {
+	return &AbortChangesetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AbortChangeset 放弃打开的变更集：删除其中的版本并释放内容引用，为变更集新建且没有其他版本的文件一并删除。
+// 作者、项目 owner 或 admin 可以放弃
+func (l *AbortChangesetLogic) AbortChangeset(req *types.AbortChangesetReq) (resp *types.ChangesetItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 {
+		return nil, errChangesetNotFound
+	}
+	changeset, role, err := loadChangeset(l.ctx, l.svcCtx, req.Id, userId, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	// 作者可以放弃自己的变更集，放弃他人的需要 owner 或 admin
+	if changeset.CreatedBy != uint64(userId) {
+		if err := permission.Check(role, permission.ChangesetOverride); err != nil {
+			return nil, err
+		}
+	}
+	if l.svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+
+	var releasedKeys []string
+	var uploads []model.MultipartUploads
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockChangeset(tx, changeset.Id)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.ChangesetStatusOpen {
+			return ErrChangesetNotOpen
+		}
+
+		var versions []model.FileVersions
+		if err := tx.Where("changeset_id = ?", changeset.Id).Find(&versions).Error; err != nil {
+			return err
+		}
+		versionIds := make([]uint64, 0, len(versions))
+		fileIds := make([]uint64, 0, len(versions))
+		for _, v := range versions {
+			versionIds = append(versionIds, v.Id)
+			fileIds = append(fileIds, v.FileId)
+		}
+		if len(versionIds) > 0 {
+			if err := tx.Where("file_version_id IN ? AND status = ?", versionIds, model.MultipartStatusUploading).Find(&uploads).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.MultipartUploads{}).
+				Where("file_version_id IN ? AND status = ?", versionIds, model.MultipartStatusUploading).
+				Update("status", model.MultipartStatusAborted).Error; err != nil {
+				return err
+			}
+			keys, err := model.ReleaseFileBlobs(tx, versions)
+			if err != nil {
+				return err
+			}
+			releasedKeys = keys
+			if err := tx.Where("version_id IN ?", versionIds).Delete(&model.FileVersionLabels{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("version_id IN ?", versionIds).Delete(&model.FileVersionContents{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", versionIds).Delete(&model.FileVersions{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("changeset_id = ?", changeset.Id).Delete(&model.ChangesetFiles{}).Error; err != nil {
+			return err
+		}
+
+		// 为变更集新建的文件已没有任何版本，一并删除
+		if len(fileIds) > 0 {
+			var keep []uint64
+			if err := tx.Model(&model.FileVersions{}).Where("file_id IN ?", fileIds).Distinct().Pluck("file_id", &keep).Error; err != nil {
+				return err
+			}
+			kept := make(map[uint64]struct{}, len(keep))
+			for _, id := range keep {
+				kept[id] = struct{}{}
+			}
+			empty := make([]uint64, 0)
+			for _, id := range fileIds {
+				if _, ok := kept[id]; !ok {
+					empty = append(empty, id)
+					kept[id] = struct{}{}
+				}
+			}
+			if len(empty) > 0 {
+				if err := tx.Where("file_id IN ?", empty).Delete(&model.ProjectFiles{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("id IN ?", empty).Delete(&model.Files{}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		changeset.Status = model.ChangesetStatusAborted
+		return tx.Model(&model.Changesets{}).Where("id = ?", changeset.Id).Update("status", changeset.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, upload := range uploads {
+		if err := l.svcCtx.ObjectStore.AbortMultipartUpload(l.ctx, upload.StorageKey, upload.UploadId); err != nil {
+			l.Errorf("[Changeset] Failed to abort multipart upload id=%d: %v", upload.Id, err)
+		}
+	}
+	for _, key := range releasedKeys {
+		if err := l.svcCtx.ObjectStore.DeleteObject(l.ctx, key); err != nil {
+			l.Errorf("[Changeset] Failed to delete object %s: %v", key, err)
+		}
+	}
+	l.Infof("[Changeset] UserId=%d aborted changeset %d", userId, changeset.Id)
+	return changesetItem(l.svcCtx.DB.WithContext(l.ctx), changeset)
+}
@@ -0,0 +1,99 @@
+package files
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type AbortMultipartUploadLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAbortMultipartUploadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AbortMultipartUploadLogic {
+	return &AbortMultipartUploadLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AbortMultipartUpload 取消上传并删除尚未完成的版本记录
+func (l *AbortMultipartUploadLogic) AbortMultipartUpload(req *types.AbortMultipartUploadReq) (resp *types.BaseResp, err error) {
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	upload, err := loadMultipartUpload(l.ctx, l.svcCtx, req.Id, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != model.MultipartStatusUploading {
+		return nil, errors.New("multipart upload is " + upload.Status)
+	}
+
+	if err := l.svcCtx.ObjectStore.AbortMultipartUpload(l.ctx, upload.StorageKey, upload.UploadId); err != nil {
+		l.Errorf("[MultipartUpload] Failed to abort id=%d: %v", upload.Id, err)
+		return nil, err
+	}
+
+	var releasedKeys []string
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.MultipartUploads{}).
+			Where("id = ?", upload.Id).
+			Update("status", model.MultipartStatusAborted).Error; err != nil {
+			return err
+		}
+		var versions []model.FileVersions
+		if err := tx.Where("id = ?", upload.FileVersionId).Find(&versions).Error; err != nil {
+			return err
+		}
+		keys, err := model.ReleaseFileBlobs(tx, versions)
+		if err != nil {
+			return err
+		}
+		releasedKeys = keys
+		if err := tx.Where("version_id = ?", upload.FileVersionId).Delete(&model.FileVersionLabels{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", upload.FileVersionId).Delete(&model.FileVersions{}).Error; err != nil {
+			return err
+		}
+
+		// 文件是为本次上传新建的且没有其他版本时一并删除
+		var remaining int64
+		if err := tx.Model(&model.FileVersions{}).Where("file_id = ?", upload.FileId).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
+		}
+		if err := tx.Where("file_id = ?", upload.FileId).Delete(&model.ProjectFiles{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", upload.FileId).Delete(&model.Files{}).Error
+	})
+	if err != nil {
+		l.Errorf("[MultipartUpload] Failed to clean up id=%d: %v", upload.Id, err)
+		return nil, err
+	}
+	for _, key := range releasedKeys {
+		if err := l.svcCtx.ObjectStore.DeleteObject(l.ctx, key); err != nil {
+			l.Errorf("[MultipartUpload] Failed to delete object %s: %v", key, err)
+		}
+	}
+	l.Infof("[MultipartUpload] Aborted id=%d, fileId=%d, versionId=%d", upload.Id, upload.FileId, upload.FileVersionId)
+
+	return &types.BaseResp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
@@ -0,0 +1,148 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrChangesetNotOpen  = errorx.New(http.StatusConflict, "changeset_not_open", "changeset is not open")
+	ErrChangesetNotReady = errorx.New(http.StatusConflict, "changeset_not_ready", "changeset has versions that are not uploaded and verified")
+	ErrChangesetConflict = errorx.New(http.StatusConflict, "changeset_conflict", "files have changed since the changeset was committed")
+	errChangesetNotFound = errors.New("changeset not found")
+)
+
+// loadChangeset 查找变更集并校验当前用户在其所属项目中可以执行 action，返回用户角色
+func loadChangeset(ctx context.Context, svcCtx *svc.ServiceContext, id int64, userId int64, action permission.Action) (*model.Changesets, string, error) {
+	var changeset model.Changesets
+	if err := svcCtx.DB.WithContext(ctx).Where("id = ?", id).First(&changeset).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errChangesetNotFound
+		}
+		return nil, "", err
+	}
+	role, err := permission.Authorize(ctx, svcCtx.DB, changeset.ProjectId, userId, action)
+	if err != nil {
+		return nil, "", err
+	}
+	return &changeset, role, nil
+}
+
+// lockChangeset 在事务中锁定变更集行，PreUpload、提交、放弃与回滚之间按此串行
+func lockChangeset(tx *gorm.DB, id uint64) (*model.Changesets, error) {
+	var changeset model.Changesets
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&changeset).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errChangesetNotFound
+		}
+		return nil, err
+	}
+	return &changeset, nil
+}
+
+// addChangesetFile 记录变更集中文件的新版本，同一文件重复上传时以最后一次为准
+func addChangesetFile(tx *gorm.DB, changesetId uint64, fileId uint64, versionId uint64) error {
+	var entry model.ChangesetFiles
+	err := tx.Where("changeset_id = ? AND file_id = ?", changesetId, fileId).First(&entry).Error
+	if err == nil {
+		return tx.Model(&model.ChangesetFiles{}).Where("id = ?", entry.Id).Update("version_id", versionId).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return tx.Create(&model.ChangesetFiles{ChangesetId: changesetId, FileId: fileId, VersionId: versionId}).Error
+}
+
+func changesetItem(db *gorm.DB, changeset *model.Changesets) (*types.ChangesetItem, error) {
+	var fileCount int64
+	if err := db.Model(&model.ChangesetFiles{}).Where("changeset_id = ?", changeset.Id).Count(&fileCount).Error; err != nil {
+		return nil, err
+	}
+	var user model.Users
+	if err := db.Select("id", "username").Where("id = ?", changeset.CreatedBy).First(&user).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	item := &types.ChangesetItem{
+		Id:            int64(changeset.Id),
+		ProjectId:     int64(changeset.ProjectId),
+		Message:       changeset.Message,
+		Status:        changeset.Status,
+		CreatedBy:     int64(changeset.CreatedBy),
+		CreatedByName: user.Username,
+		FileCount:     fileCount,
+		CreatedAt:     changeset.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     changeset.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if changeset.CommittedAt.Valid {
+		item.CommittedAt = changeset.CommittedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return item, nil
+}
+
+// changesetDetail 返回变更集及其中每个文件的版本；已删除的文件仍按原路径列出
+func changesetDetail(db *gorm.DB, changeset *model.Changesets) (*types.ChangesetDetailResp, error) {
+	item, err := changesetItem(db, changeset)
+	if err != nil {
+		return nil, err
+	}
+	var entries []model.ChangesetFiles
+	if err := db.Where("changeset_id = ?", changeset.Id).Order("id ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	fileIds := make([]uint64, 0, len(entries))
+	versionIds := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		fileIds = append(fileIds, e.FileId)
+		versionIds = append(versionIds, e.VersionId)
+	}
+	filesById := make(map[uint64]model.Files, len(entries))
+	versionsById := make(map[uint64]model.FileVersions, len(entries))
+	if len(entries) > 0 {
+		var files []model.Files
+		if err := db.Unscoped().Where("id IN ?", fileIds).Find(&files).Error; err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			filesById[f.Id] = f
+		}
+		var versions []model.FileVersions
+		if err := db.Where("id IN ?", versionIds).Find(&versions).Error; err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			versionsById[v.Id] = v
+		}
+	}
+	paths, err := model.FolderPaths(db, changeset.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ChangesetDetailResp{
+		Changeset: *item,
+		Files:     make([]types.ChangesetFileItem, 0, len(entries)),
+	}
+	for _, e := range entries {
+		file := filesById[e.FileId]
+		version := versionsById[e.VersionId]
+		resp.Files = append(resp.Files, types.ChangesetFileItem{
+			FileId:            int64(e.FileId),
+			Path:              model.JoinFolderPath(paths[file.FolderId], file.Name),
+			VersionId:         int64(e.VersionId),
+			VersionNumber:     int64(version.VersionNumber),
+			VersionStatus:     version.Status,
+			PreviousVersionId: int64(e.PreviousVersionId),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+package files
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommitChangesetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCommitChangesetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CommitChangesetLogic {
+	return &CommitChangesetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CommitChangeset 在同一事务中将变更集内所有文件的当前版本切换为新版本，并记录被替换的版本供整体回滚；
+// 变更集中的版本必须全部已上传并校验（complete），否则返回未就绪的文件列表
+func (l *CommitChangesetLogic) CommitChangeset(req *types.CommitChangesetReq) (resp *types.ChangesetDetailResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 {
+		return nil, errChangesetNotFound
+	}
+	changeset, _, err := loadChangeset(l.ctx, l.svcCtx, req.Id, userId, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	if changeset.CreatedBy != uint64(userId) {
+		return nil, errors.New("permission denied: only the changeset author can commit it")
+	}
+
+	var entries []model.ChangesetFiles
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockChangeset(tx, changeset.Id)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.ChangesetStatusOpen {
+			return ErrChangesetNotOpen
+		}
+		if err := tx.Where("changeset_id = ?", changeset.Id).Order("file_id ASC").Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return errors.New("changeset has no files")
+		}
+
+		versionIds := make([]uint64, len(entries))
+		for i, e := range entries {
+			versionIds[i] = e.VersionId
+		}
+		var versions []model.FileVersions
+		if err := tx.Where("id IN ?", versionIds).Find(&versions).Error; err != nil {
+			return err
+		}
+		status := make(map[uint64]string, len(versions))
+		for _, v := range versions {
+			status[v.Id] = v.Status
+		}
+		notReady := make([]uint64, 0)
+		for _, e := range entries {
+			if status[e.VersionId] != model.FileVersionStatusReady {
+				notReady = append(notReady, e.FileId)
+			}
+		}
+		if len(notReady) > 0 {
+			return ErrChangesetNotReady.WithDetails(map[string]any{"fileIds": notReady})
+		}
+
+		// 按文件 ID 顺序加锁，避免与其它变更集互相等待
+		for i := range entries {
+			e := &entries[i]
+			var file model.Files
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", e.FileId).First(&file).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("file %d has been deleted", e.FileId)
+				}
+				return err
+			}
+			if err := checkFileLock(tx, e.FileId, userId); err != nil {
+				return err
+			}
+			e.PreviousVersionId = file.CurrentVersionId
+			if err := tx.Model(&model.ChangesetFiles{}).Where("id = ?", e.Id).
+				Update("previous_version_id", e.PreviousVersionId).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Files{}).Where("id = ?", e.FileId).
+				Update("current_version_id", e.VersionId).Error; err != nil {
+				return err
+			}
+		}
+		changeset.Status = model.ChangesetStatusCommitted
+		changeset.CommittedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		return tx.Model(&model.Changesets{}).Where("id = ?", changeset.Id).Updates(map[string]any{
+			"status":       changeset.Status,
+			"committed_at": changeset.CommittedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[Changeset] UserId=%d committed changeset %d, files=%d", userId, changeset.Id, len(entries))
+	return changesetDetail(l.svcCtx.DB.WithContext(l.ctx), changeset)
+}
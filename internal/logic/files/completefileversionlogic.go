@@ -0,0 +1,288 @@
+package files
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CompleteFileVersionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCompleteFileVersionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CompleteFileVersionLogic {
+	return &CompleteFileVersionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CompleteFileVersion 客户端上传完成后调用，校验通过后版本才成为文件当前版本
+func (l *CompleteFileVersionLogic) CompleteFileVersion(req *types.CompleteFileVersionReq) (resp *types.FileVersionItem, err error) {
+	adminIdNumber, ok := l.ctx.Value("adminId").(json.Number)
+	isAdmin := ok
+	userIdNumber := adminIdNumber
+	if !ok {
+		userIdNumber, ok = l.ctx.Value("userId").(json.Number)
+		if !ok {
+			return nil, errors.New("unauthorized")
+		}
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.VersionId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+
+	version, err := l.svcCtx.FileVersionsModel.FindOne(l.ctx, uint64(req.VersionId))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, errors.New("version not found")
+		}
+		return nil, err
+	}
+	if version.FileId != uint64(req.Id) {
+		return nil, errors.New("version not found")
+	}
+
+	if !isAdmin {
+		var projectFile model.ProjectFiles
+		if err := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ?", version.FileId).First(&projectFile).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("file not found")
+			}
+			return nil, err
+		}
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectFile.ProjectId, userId, permission.FileWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := finalizeFileVersion(l.ctx, l.svcCtx, version); err != nil {
+		l.Errorf("[CompleteVersion] fileId=%d, versionId=%d: %v", version.FileId, version.Id, err)
+		return nil, err
+	}
+	l.Infof("[CompleteVersion] fileId=%d, versionId=%d is %s", version.FileId, version.Id, version.Status)
+
+	return fileVersionItem(l.svcCtx.DB.WithContext(l.ctx), version)
+}
+
+// errVersionNotPending 加锁后发现版本已被其它请求完成或置为 failed
+var errVersionNotPending = errors.New("version is no longer pending")
+
+// finalizeFileVersion 校验对象大小与 sha256，通过后置为 ready 并设为文件当前版本（变更集中的版本在提交时切换，
+// 当前版本比它新时只置为 ready）；预上传时带了前置条件而文件已不在基于的版本上时返回 ErrVersionConflict 并置为 failed；
+// 内容不符或已超时则置为 failed。对象尚未上传时保持 pending，客户端可重传后再次调用。
+func finalizeFileVersion(ctx context.Context, svcCtx *svc.ServiceContext, version *model.FileVersions) error {
+	switch version.Status {
+	case model.FileVersionStatusReady:
+		return nil
+	case model.FileVersionStatusFailed:
+		return errors.New("version upload failed, please upload again")
+	}
+	if svcCtx.ObjectStore == nil {
+		return errors.New("object store not configured")
+	}
+
+	if time.Since(version.CreatedAt) > svcCtx.PendingVersionTTL() {
+		_ = markFileVersionFailed(ctx, svcCtx, version)
+		return errors.New("upload expired, please upload again")
+	}
+
+	stat, err := svcCtx.ObjectStore.StatObject(ctx, version.StorageKey)
+	if err != nil {
+		return fmt.Errorf("uploaded object not found: %w", err)
+	}
+	if stat.SizeBytes != int64(version.SizeBytes) {
+		_ = markFileVersionFailed(ctx, svcCtx, version)
+		return fmt.Errorf("size mismatch: expected %d, got %d", version.SizeBytes, stat.SizeBytes)
+	}
+
+	reader, err := svcCtx.ObjectStore.GetObject(ctx, version.StorageKey)
+	if err != nil {
+		return err
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, reader)
+	_ = reader.Close()
+	if err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, strings.TrimSpace(version.Hash)) {
+		_ = markFileVersionFailed(ctx, svcCtx, version)
+		return fmt.Errorf("hash mismatch: expected %s, got %s", version.Hash, actual)
+	}
+
+	// 临时对象的内容已校验：共用对象尚未存在时先在事务外复制过去，避免持有行锁期间复制大对象
+	staged := strings.HasPrefix(version.StorageKey, model.StagingKeyPrefix)
+	copied := false
+	if staged {
+		var blob model.FileBlobs
+		err := svcCtx.DB.WithContext(ctx).Where("hash = ?", version.Hash).First(&blob).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err != nil || !blob.Verified {
+			if err := svcCtx.ObjectStore.CopyObject(ctx, version.StorageKey, model.BlobStorageKey(version.Hash)); err != nil {
+				return err
+			}
+			copied = true
+		}
+	}
+
+	var storageKey string
+	err = svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先锁文件行再更新内容引用，与 PreUpload 的加锁顺序一致
+		file, err := lockFileRow(tx, version.FileId)
+		if err != nil {
+			return err
+		}
+		// 重复的 complete 与过期任务可能已处理过该版本
+		var current model.FileVersions
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").Where("id = ?", version.Id).First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status != model.FileVersionStatusPending {
+			return errVersionNotPending
+		}
+		if version.ChangesetId == 0 {
+			if err := checkBaseVersion(file, version); err != nil {
+				return err
+			}
+		}
+		storageKey = version.StorageKey
+		if staged {
+			if storageKey, err = adoptStagedObject(ctx, svcCtx, tx, version, copied); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.FileVersions{}).
+			Where("id = ?", version.Id).
+			Updates(map[string]any{"status": model.FileVersionStatusReady, "storage_key": storageKey}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.FileBlobs{}).
+			Where("storage_key = ?", storageKey).
+			Update("verified", true).Error; err != nil {
+			return err
+		}
+		// 变更集中的版本在提交时才成为当前版本
+		if version.ChangesetId > 0 {
+			return nil
+		}
+		// 带前置条件的版本已在上面校验过基于的版本，否则只在没有更新的当前版本时切换
+		if version.BaseVersionId == 0 {
+			newer, err := hasNewerCurrentVersion(tx, file, version)
+			if err != nil || newer {
+				return err
+			}
+		}
+		return tx.Model(&model.Files{}).
+			Where("id = ?", version.FileId).
+			Update("current_version_id", version.Id).Error
+	})
+	if errors.Is(err, errVersionNotPending) {
+		if err := svcCtx.DB.WithContext(ctx).Where("id = ?", version.Id).First(version).Error; err != nil {
+			return err
+		}
+		return finalizeFileVersion(ctx, svcCtx, version)
+	}
+	if isVersionConflict(err) {
+		// 基于的版本已被替换，本次上传作废，客户端需重新读取后再提交
+		_ = markFileVersionFailed(ctx, svcCtx, version)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if storageKey != version.StorageKey {
+		_ = svcCtx.ObjectStore.DeleteObject(ctx, version.StorageKey)
+		version.StorageKey = storageKey
+	}
+	version.Status = model.FileVersionStatusReady
+	generateThumbnailsAsync(svcCtx, version)
+	indexFileContentAsync(svcCtx, version)
+	return nil
+}
+
+// adoptStagedObject 上传到临时对象的内容校验通过后改为引用按 hash 寻址的共用对象，返回共用对象的 key；
+// copied 表示已在事务外复制过，否则（或共用对象在此期间已被回收）由临时对象复制一份
+func adoptStagedObject(ctx context.Context, svcCtx *svc.ServiceContext, tx *gorm.DB, version *model.FileVersions, copied bool) (string, error) {
+	blobKey := model.BlobStorageKey(version.Hash)
+	blob, err := model.AcquireFileBlob(tx, version.Hash, blobKey, version.SizeBytes)
+	if err != nil {
+		return "", err
+	}
+	if !blob.Verified && !(copied && blob.StorageKey == blobKey) {
+		if err := svcCtx.ObjectStore.CopyObject(ctx, version.StorageKey, blob.StorageKey); err != nil {
+			return "", err
+		}
+	}
+	return blob.StorageKey, nil
+}
+
+// lockFileRow 在事务中锁定文件行，同一文件的预上传与完成上传在此串行
+func lockFileRow(tx *gorm.DB, fileId uint64) (*model.Files, error) {
+	var file model.Files
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", fileId).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("file not found")
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// hasNewerCurrentVersion 文件当前版本的版本号大于 version 时返回 true：迟到完成的旧上传只置为 ready，
+// 不替换更新的当前版本，避免历史倒退
+func hasNewerCurrentVersion(tx *gorm.DB, file *model.Files, version *model.FileVersions) (bool, error) {
+	if file.CurrentVersionId == 0 || file.CurrentVersionId == version.Id {
+		return false, nil
+	}
+	var current model.FileVersions
+	if err := tx.Select("id", "version_number").Where("id = ?", file.CurrentVersionId).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return current.VersionNumber > version.VersionNumber, nil
+}
+
+// markFileVersionFailed 将未完成的版本置为 failed：释放内容引用，并删除已无引用的对象（通常是本版本的临时对象）
+func markFileVersionFailed(ctx context.Context, svcCtx *svc.ServiceContext, version *model.FileVersions) error {
+	var releasedKeys []string
+	if err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		releasedKeys, err = model.FailFileVersion(tx, version.Id)
+		return err
+	}); err != nil {
+		return err
+	}
+	for _, key := range releasedKeys {
+		if err := svcCtx.ObjectStore.DeleteObject(ctx, key); err != nil {
+			logx.WithContext(ctx).Errorf("[CompleteVersion] delete object %s failed: %v", key, err)
+		}
+	}
+	version.Status = model.FileVersionStatusFailed
+	version.StorageKey = ""
+	return nil
+}
@@ -0,0 +1,127 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CompleteMultipartUploadLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCompleteMultipartUploadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CompleteMultipartUploadLogic {
+	return &CompleteMultipartUploadLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CompleteMultipartUpload 合并分片，校验通过后将该版本设为文件当前版本
+func (l *CompleteMultipartUploadLogic) CompleteMultipartUpload(req *types.CompleteMultipartUploadReq) (resp *types.BaseResp, err error) {
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	upload, err := loadMultipartUpload(l.ctx, l.svcCtx, req.Id, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != model.MultipartStatusUploading {
+		return nil, errors.New("multipart upload is " + upload.Status)
+	}
+
+	// 上次调用已合并分片但校验未完成（如超时）时，对象已在存储中，直接重新校验
+	merged := false
+	if stat, err := l.svcCtx.ObjectStore.StatObject(l.ctx, upload.StorageKey); err == nil && stat.SizeBytes == int64(upload.SizeBytes) {
+		merged = true
+	}
+	partCount := 0
+	if !merged {
+		if partCount, err = l.mergeParts(upload, req.Parts); err != nil {
+			return nil, err
+		}
+	}
+
+	// 分片已合并，按普通上传同样校验大小与 hash 后设为当前版本；会话在校验通过后才置为 completed，
+	// 校验中途失败时客户端可以再次调用 complete
+	version, err := l.svcCtx.FileVersionsModel.FindOne(l.ctx, upload.FileVersionId)
+	if err != nil {
+		return nil, err
+	}
+	if err := finalizeFileVersion(l.ctx, l.svcCtx, version); err != nil {
+		l.Errorf("[MultipartUpload] Failed to finalize version of id=%d: %v", upload.Id, err)
+		if version.Status == model.FileVersionStatusFailed {
+			_ = l.svcCtx.DB.WithContext(l.ctx).Model(&model.MultipartUploads{}).
+				Where("id = ? AND status = ?", upload.Id, model.MultipartStatusUploading).
+				Update("status", model.MultipartStatusAborted).Error
+		}
+		return nil, err
+	}
+	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.MultipartUploads{}).
+		Where("id = ?", upload.Id).
+		Update("status", model.MultipartStatusCompleted).Error; err != nil {
+		return nil, err
+	}
+	l.Infof("[MultipartUpload] Completed id=%d, fileId=%d, versionId=%d, parts=%d", upload.Id, upload.FileId, upload.FileVersionId, partCount)
+
+	return &types.BaseResp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
+
+// mergeParts 校验分片完整后在存储中合并，返回分片数；reqParts 为空时以存储中已上传的分片为准
+func (l *CompleteMultipartUploadLogic) mergeParts(upload *model.MultipartUploads, reqParts []types.UploadedPartItem) (int, error) {
+	var parts []storage.UploadedPart
+	var err error
+	if len(reqParts) > 0 {
+		parts = make([]storage.UploadedPart, 0, len(reqParts))
+		for _, p := range reqParts {
+			if p.PartNumber <= 0 || strings.TrimSpace(p.ETag) == "" {
+				return 0, model.InputParamInvalid
+			}
+			parts = append(parts, storage.UploadedPart{
+				PartNumber: int(p.PartNumber),
+				ETag:       strings.TrimSpace(p.ETag),
+				SizeBytes:  p.SizeBytes,
+			})
+		}
+	} else {
+		parts, err = l.svcCtx.ObjectStore.ListUploadedParts(l.ctx, upload.StorageKey, upload.UploadId)
+		if err != nil {
+			l.Errorf("[MultipartUpload] Failed to list parts of id=%d: %v", upload.Id, err)
+			return 0, err
+		}
+	}
+
+	// 分片号必须从 1 开始连续，且数量与初始化时计算的一致
+	partCount := multipartPartCount(int64(upload.SizeBytes), int64(upload.PartSize))
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	if int64(len(parts)) != partCount {
+		return 0, fmt.Errorf("expected %d parts, got %d", partCount, len(parts))
+	}
+	for i, p := range parts {
+		if p.PartNumber != i+1 {
+			return 0, fmt.Errorf("missing part %d", i+1)
+		}
+	}
+
+	if err := l.svcCtx.ObjectStore.CompleteMultipartUpload(l.ctx, upload.StorageKey, upload.UploadId, parts); err != nil {
+		l.Errorf("[MultipartUpload] Failed to complete id=%d: %v", upload.Id, err)
+		return 0, err
+	}
+	return len(parts), nil
+}
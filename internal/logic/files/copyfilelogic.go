@@ -0,0 +1,213 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CopyFileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCopyFileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CopyFileLogic {
+	return &CopyFileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CopyFile 将文件复制到目标项目，生成新的 files / project_files / file_versions 记录。
+// 内容按 sha256 存储，新版本直接引用同一对象；旧路径下的对象通过 ObjectStore.CopyObject 在存储内部复制到内容寻址路径。
+func (l *CopyFileLogic) CopyFile(req *types.CopyFileReq) (resp *types.ProjectFileItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.TargetProjectId <= 0 || req.TargetFolderId < 0 {
+		return nil, model.InputParamInvalid
+	}
+	if l.svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+
+	file, sourceProjectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, sourceProjectId, userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+	targetProjectId := uint64(req.TargetProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, targetProjectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = file.Name
+	} else if err := validateEntryName(name); err != nil {
+		return nil, err
+	}
+	targetFolderId := uint64(req.TargetFolderId)
+
+	// 选择要复制的版本：全部已完成版本 / 指定版本 / 当前版本
+	var versions []model.FileVersions
+	query := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ? AND status = ?", file.Id, model.FileVersionStatusReady)
+	switch {
+	case req.AllVersions:
+		query = query.Order("version_number ASC")
+	case req.VersionId > 0:
+		query = query.Where("id = ?", req.VersionId)
+	default:
+		query = query.Where("id = ?", file.CurrentVersionId)
+	}
+	if err := query.Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, errors.New("file version not found")
+	}
+
+	var totalBytes int64
+	for _, v := range versions {
+		totalBytes += int64(v.SizeBytes)
+	}
+	// 复制对象前先校验一次，避免超出配额时仍在存储内复制；事务内加锁后再以最终结果为准
+	if err := l.svcCtx.CheckStorageQuota(l.svcCtx.DB.WithContext(l.ctx), targetProjectId, uint64(userId), totalBytes); err != nil {
+		return nil, err
+	}
+
+	// 在事务外完成存储内复制，事务内只写记录
+	storageKeys := make([]string, len(versions))
+	for i, v := range versions {
+		key, err := l.ensureBlobObject(&v)
+		if err != nil {
+			l.Errorf("[CopyFile] Failed to copy object for versionId=%d: %v", v.Id, err)
+			return nil, err
+		}
+		storageKeys[i] = key
+	}
+
+	newFile := &model.Files{
+		Name:         name,
+		FileCategory: file.FileCategory,
+		FileFormat:   file.FileFormat,
+		FolderId:     targetFolderId,
+	}
+	var current *model.FileVersions
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := l.svcCtx.CheckStorageQuota(tx, targetProjectId, uint64(userId), totalBytes); err != nil {
+			return err
+		}
+		if err := lockFolderEntries(tx, targetProjectId, targetFolderId); err != nil {
+			return err
+		}
+		taken, err := entryNameTaken(tx, targetProjectId, targetFolderId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a file or folder with the same name already exists in target folder")
+		}
+		if err := tx.Create(newFile).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.ProjectFiles{ProjectId: targetProjectId, FileId: newFile.Id}).Error; err != nil {
+			return err
+		}
+		versionIds := make([]uint64, len(versions))
+		for i, v := range versions {
+			versionIds[i] = v.Id
+		}
+		labels, err := model.VersionLabels(tx, versionIds)
+		if err != nil {
+			return err
+		}
+		for i, v := range versions {
+			if model.IsSha256Hex(v.Hash) {
+				blob, err := model.AcquireFileBlob(tx, v.Hash, storageKeys[i], v.SizeBytes)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&model.FileBlobs{}).Where("id = ?", blob.Id).Update("verified", true).Error; err != nil {
+					return err
+				}
+				storageKeys[i] = blob.StorageKey
+			}
+			copied := &model.FileVersions{
+				FileId:        newFile.Id,
+				VersionNumber: uint64(i + 1),
+				SizeBytes:     v.SizeBytes,
+				Hash:          v.Hash,
+				StorageKey:    storageKeys[i],
+				CreatedBy:     uint64(userId),
+				Status:        model.FileVersionStatusReady,
+				Message:       v.Message,
+			}
+			if err := tx.Create(copied).Error; err != nil {
+				return err
+			}
+			if err := model.SetVersionLabels(tx, newFile.Id, copied.Id, labels[v.Id], uint64(userId)); err != nil {
+				return err
+			}
+			if current == nil || v.Id == file.CurrentVersionId {
+				current = copied
+			}
+		}
+		newFile.CurrentVersionId = current.Id
+		return tx.Model(&model.Files{}).Where("id = ?", newFile.Id).Update("current_version_id", current.Id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexFileContentAsync(l.svcCtx, current)
+
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), targetProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[CopyFile] UserId=%d copied fileId=%d (project %d) to fileId=%d (project %d), versions=%d",
+		userId, file.Id, sourceProjectId, newFile.Id, targetProjectId, len(versions))
+	return toProjectFileItem(targetProjectId, paths[targetFolderId], newFile, current), nil
+}
+
+// ensureBlobObject 返回新版本应引用的存储 key；旧路径对象在内容寻址路径尚无已校验对象时先在存储内复制过去
+func (l *CopyFileLogic) ensureBlobObject(version *model.FileVersions) (string, error) {
+	if !model.IsSha256Hex(version.Hash) {
+		// 无法内容寻址的历史版本直接共用原对象，删除时按 storage_key 引用计数
+		return version.StorageKey, nil
+	}
+	blobKey := model.BlobStorageKey(version.Hash)
+	if version.StorageKey == blobKey {
+		return blobKey, nil
+	}
+	var blob model.FileBlobs
+	err := l.svcCtx.DB.WithContext(l.ctx).Where("hash = ?", version.Hash).First(&blob).Error
+	if err == nil && blob.Verified {
+		return blob.StorageKey, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if err := l.svcCtx.ObjectStore.CopyObject(l.ctx, version.StorageKey, blobKey); err != nil {
+		return "", err
+	}
+	return blobKey, nil
+}
@@ -0,0 +1,60 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateChangesetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateChangesetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateChangesetLogic {
+	return &CreateChangesetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateChangeset 打开变更集；之后带 changesetId 的 PreUpload 在提交前不会改变文件的当前版本
+func (l *CreateChangesetLogic) CreateChangeset(req *types.CreateChangesetReq) (resp *types.ChangesetItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	message, err := model.NormalizeVersionMessage(req.Message)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	changeset := &model.Changesets{
+		ProjectId: uint64(req.ProjectId),
+		Message:   message,
+		Status:    model.ChangesetStatusOpen,
+		CreatedBy: uint64(userId),
+	}
+	if err := l.svcCtx.DB.WithContext(l.ctx).Create(changeset).Error; err != nil {
+		return nil, err
+	}
+	l.Infof("[Changeset] UserId=%d opened changeset %d in project %d", userId, changeset.Id, changeset.ProjectId)
+	return changesetItem(l.svcCtx.DB.WithContext(l.ctx), changeset)
+}
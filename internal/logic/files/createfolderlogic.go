@@ -0,0 +1,97 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CreateFolderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateFolderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateFolderLogic {
+	return &CreateFolderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateFolder 创建目录；给出 path 时逐级创建缺失的目录（已存在则直接返回），给出 name 时同名目录或文件已存在返回错误
+func (l *CreateFolderLogic) CreateFolder(req *types.CreateFolderReq) (resp *types.FolderItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.ParentId < 0 {
+		return nil, model.InputParamInvalid
+	}
+	var segs []string
+	byName := strings.TrimSpace(req.Path) == ""
+	if !byName {
+		if segs, err = splitPath(req.Path); err != nil {
+			return nil, err
+		}
+	} else {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			return nil, errors.New("name or path is required")
+		}
+		if err := validateEntryName(name); err != nil {
+			return nil, err
+		}
+		segs = []string{name}
+	}
+
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	var folderId uint64
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadFolder(tx, projectId, uint64(req.ParentId)); err != nil {
+			return err
+		}
+		if byName {
+			taken, err := entryNameTaken(tx, projectId, uint64(req.ParentId), segs[0])
+			if err != nil {
+				return err
+			}
+			if taken {
+				return errors.New("a file or folder with the same name already exists")
+			}
+		}
+		folderId, err = ensureFolderPath(tx, projectId, uint64(req.ParentId), segs, uint64(userId))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	folder, err := loadFolder(db, projectId, folderId)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := model.FolderPaths(db, projectId)
+	if err != nil {
+		return nil, err
+	}
+	l.Infof("[CreateFolder] UserId=%d, ProjectId=%d, FolderId=%d, Path=%s", userId, projectId, folderId, paths[folderId])
+	return toFolderItem(folder, paths[folderId]), nil
+}
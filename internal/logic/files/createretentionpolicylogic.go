@@ -0,0 +1,75 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateRetentionPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateRetentionPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateRetentionPolicyLogic {
+	return &CreateRetentionPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateRetentionPolicy 为项目或匹配路径的文件添加版本保留策略，同一模式只能有一条，仅 owner 或 admin 可操作
+func (l *CreateRetentionPolicyLogic) CreateRetentionPolicy(req *types.CreateRetentionPolicyReq) (resp *types.RetentionPolicyItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if err := validateRetention(req.KeepLast, req.KeepDays); err != nil {
+		return nil, err
+	}
+	pattern, err := model.NormalizeRetentionPattern(req.PathPattern)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.RetentionManage); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	var count int64
+	if err := db.Model(&model.VersionRetentionPolicies{}).
+		Where("project_id = ? AND path_pattern = ?", req.ProjectId, pattern).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("a retention policy for this path pattern already exists")
+	}
+	policy := &model.VersionRetentionPolicies{
+		ProjectId:   uint64(req.ProjectId),
+		PathPattern: pattern,
+		KeepLast:    uint64(req.KeepLast),
+		KeepDays:    uint64(req.KeepDays),
+		CreatedBy:   uint64(userId),
+	}
+	if err := db.Create(policy).Error; err != nil {
+		return nil, err
+	}
+
+	l.Infof("[CreateRetentionPolicy] UserId=%d added policy %d (%q) to project %d", userId, policy.Id, pattern, req.ProjectId)
+	return retentionPolicyItem(policy), nil
+}
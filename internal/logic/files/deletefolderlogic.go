@@ -0,0 +1,82 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type DeleteFolderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteFolderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteFolderLogic {
+	return &DeleteFolderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteFolder 删除目录；非空目录需要 recursive，子目录一并删除，其中的文件移入回收站（与 DeleteFile 相同，需要 owner 或 admin）
+func (l *DeleteFolderLogic) DeleteFolder(req *types.DeleteFolderReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FolderId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileDelete); err != nil {
+		return nil, err
+	}
+	var fileIds []uint64
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadFolder(tx, projectId, uint64(req.FolderId)); err != nil {
+			return err
+		}
+		folderIds, err := descendantFolderIds(tx, projectId, uint64(req.FolderId))
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Files{}).
+			Joins("JOIN project_files ON project_files.file_id = files.id").
+			Where("project_files.project_id = ? AND files.folder_id IN ?", projectId, folderIds).
+			Pluck("files.id", &fileIds).Error; err != nil {
+			return err
+		}
+		if !req.Recursive && (len(folderIds) > 1 || len(fileIds) > 0) {
+			return errors.New("folder is not empty")
+		}
+		if err := trashFilesTx(tx, projectId, fileIds, uint64(userId)); err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", folderIds).Delete(&model.Folders{}).Error
+	})
+	if err != nil {
+		l.Errorf("[DeleteFolder] Failed to delete folder: %v", err)
+		return nil, err
+	}
+
+	l.Infof("[DeleteFolder] UserId=%d, ProjectId=%d, FolderId=%d, trashed files=%d",
+		userId, projectId, req.FolderId, len(fileIds))
+
+	return &types.BaseResp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
@@ -0,0 +1,54 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteRetentionPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteRetentionPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteRetentionPolicyLogic {
+	return &DeleteRetentionPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteRetentionPolicy 删除保留策略，之后匹配的文件按其它策略或不再清理；仅 owner 或 admin 可操作
+func (l *DeleteRetentionPolicyLogic) DeleteRetentionPolicy(req *types.DeleteRetentionPolicyReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.PolicyId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.RetentionManage); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	policy, err := loadRetentionPolicy(db, uint64(req.ProjectId), uint64(req.PolicyId))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Delete(policy).Error; err != nil {
+		return nil, err
+	}
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
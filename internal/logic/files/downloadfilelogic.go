@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -63,27 +64,15 @@ func (l *DownloadFileLogic) DownloadFile(req *types.DownloadFileReq) (resp *type
 	}
 
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-			Where("project_id = ? AND user_id = ?", projectFile.ProjectId, userId).Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectFile.ProjectId, userId, permission.ProjectView); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
-		}
 	}
 
 	// 获取当前版本信息
-	versionId := file.CurrentVersionId
-	if req.VersionId > 0 {
-		versionId = uint64(req.VersionId)
-	}
-	if req.VersionNumber > 0 {
-		var v model.FileVersions
-		if err := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ? AND version_number = ?", file.Id, req.VersionNumber).First(&v).Error; err != nil {
-			return nil, err
-		}
-		versionId = v.Id
+	versionId, err := resolveVersionId(l.svcCtx.DB.WithContext(l.ctx), file, req.VersionId, req.VersionNumber, req.Label)
+	if err != nil {
+		return nil, err
 	}
 
 	version, err := l.svcCtx.FileVersionsModel.FindOne(l.ctx, versionId)
@@ -44,16 +44,9 @@ func (l *DownloadTemplateLogic) DownloadTemplate(req *types.DownloadFileReq) (re
 	}
 
 	// 获取版本信息
-	versionId := file.CurrentVersionId
-	if req.VersionId > 0 {
-		versionId = uint64(req.VersionId)
-	}
-	if req.VersionNumber > 0 {
-		var v model.FileVersions
-		if err := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ? AND version_number = ?", file.Id, req.VersionNumber).First(&v).Error; err != nil {
-			return nil, err
-		}
-		versionId = v.Id
+	versionId, err := resolveVersionId(l.svcCtx.DB.WithContext(l.ctx), file, req.VersionId, req.VersionNumber, req.Label)
+	if err != nil {
+		return nil, err
 	}
 
 	version, err := l.svcCtx.FileVersionsModel.FindOne(l.ctx, versionId)
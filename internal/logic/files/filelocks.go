@@ -0,0 +1,91 @@
+package files
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+// maxLockNoteLength 锁备注的长度上限（字符）
+const maxLockNoteLength = 255
+
+var ErrFileLocked = errorx.New(http.StatusLocked, "file_locked", "file is locked by another user")
+
+// checkFileLock 文件被其他用户持有有效锁时返回 ErrFileLocked，details 中附带持有者与过期时间
+func checkFileLock(db *gorm.DB, fileId uint64, userId int64) error {
+	lock, err := model.ActiveFileLock(db, fileId)
+	if err != nil {
+		return err
+	}
+	if lock == nil || lock.LockedBy == uint64(userId) {
+		return nil
+	}
+	return fileLockedError(lock)
+}
+
+func fileLockedError(lock *model.FileLocks) error {
+	return ErrFileLocked.WithDetails(map[string]any{
+		"fileId":    lock.FileId,
+		"lockedBy":  lock.LockedBy,
+		"expiresAt": lock.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// fileLockItems 转换锁记录，并批量补充文件路径与持有者用户名
+func fileLockItems(db *gorm.DB, projectId uint64, locks []model.FileLocks) ([]types.FileLockItem, error) {
+	fileIds := make([]uint64, 0, len(locks))
+	userIds := make([]uint64, 0, len(locks))
+	for _, lock := range locks {
+		fileIds = append(fileIds, lock.FileId)
+		userIds = append(userIds, lock.LockedBy)
+	}
+	paths := make(map[uint64]string, len(locks))
+	names := make(map[uint64]string, len(locks))
+	if len(locks) > 0 {
+		folderPaths, err := model.FolderPaths(db, projectId)
+		if err != nil {
+			return nil, err
+		}
+		var files []model.Files
+		if err := db.Where("id IN ?", fileIds).Find(&files).Error; err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			paths[f.Id] = model.JoinFolderPath(folderPaths[f.FolderId], f.Name)
+		}
+		var users []model.Users
+		if err := db.Select("id", "username").Where("id IN ?", userIds).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names[u.Id] = u.Username
+		}
+	}
+	items := make([]types.FileLockItem, 0, len(locks))
+	for _, lock := range locks {
+		items = append(items, types.FileLockItem{
+			FileId:       int64(lock.FileId),
+			ProjectId:    int64(lock.ProjectId),
+			Path:         paths[lock.FileId],
+			LockedBy:     int64(lock.LockedBy),
+			LockedByName: names[lock.LockedBy],
+			Note:         lock.Note,
+			ExpiresAt:    lock.ExpiresAt.Format(time.RFC3339),
+			CreatedAt:    lock.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return items, nil
+}
+
+func fileLockItem(db *gorm.DB, lock *model.FileLocks) (*types.FileLockItem, error) {
+	items, err := fileLockItems(db, lock.ProjectId, []model.FileLocks{*lock})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
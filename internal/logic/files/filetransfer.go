@@ -0,0 +1,62 @@
+package files
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+	"gorm.io/gorm"
+)
+
+// loadProjectFile 查找未删除的文件及其所属项目
+func loadProjectFile(ctx context.Context, svcCtx *svc.ServiceContext, fileId int64) (*model.Files, uint64, error) {
+	var file model.Files
+	if err := svcCtx.DB.WithContext(ctx).Where("id = ?", fileId).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, errors.New("file not found")
+		}
+		return nil, 0, err
+	}
+	var projectFile model.ProjectFiles
+	if err := svcCtx.DB.WithContext(ctx).Where("file_id = ?", file.Id).First(&projectFile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, errors.New("file not found")
+		}
+		return nil, 0, err
+	}
+	return &file, projectFile.ProjectId, nil
+}
+
+// fileNameTaken 项目内文件按目录 + 名称寻址（PreUpload 按路径追加版本），同一目录下同名文件不能共存
+func fileNameTaken(tx *gorm.DB, projectId uint64, folderId uint64, name string) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ? AND files.folder_id = ? AND files.name = ?", projectId, folderId, name).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// toProjectFileItem dir 为文件所在目录的路径，根目录为空
+func toProjectFileItem(projectId uint64, dir string, file *model.Files, version *model.FileVersions) *types.ProjectFileItem {
+	return &types.ProjectFileItem{
+		Id:               int64(file.Id),
+		ProjectId:        int64(projectId),
+		Name:             file.Name,
+		FolderId:         int64(file.FolderId),
+		Path:             model.JoinFolderPath(dir, file.Name),
+		FileCategory:     file.FileCategory,
+		FileFormat:       file.FileFormat,
+		CurrentVersionId: int64(file.CurrentVersionId),
+		VersionId:        int64(version.Id),
+		VersionNumber:    int64(version.VersionNumber),
+		SizeBytes:        int64(version.SizeBytes),
+		Hash:             version.Hash,
+		CreatedAt:        file.CreatedAt.Format("2006-01-02 15:04:05"),
+		StorageKey:       version.StorageKey,
+	}
+}
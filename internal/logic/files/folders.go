@@ -0,0 +1,219 @@
+package files
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errFolderNotFound = errors.New("folder not found")
+
+// splitPath 将 a/b/c 形式的项目内路径拆成各级名称，忽略首尾的 /；不允许空段、. 、.. 与反斜杠
+func splitPath(path string) ([]string, error) {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+	segs := strings.Split(path, "/")
+	for _, seg := range segs {
+		if err := validateEntryName(seg); err != nil {
+			return nil, err
+		}
+	}
+	return segs, nil
+}
+
+// validateEntryName 校验单级目录名或文件名
+func validateEntryName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.TrimSpace(name) != name {
+		return errors.New("invalid path segment: " + name)
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return errors.New("invalid path segment: " + name)
+	}
+	if len(name) > 255 {
+		return errors.New("path segment too long")
+	}
+	return nil
+}
+
+// loadFolder 查找项目内的目录；folderId 为 0 表示根目录，返回 nil
+func loadFolder(db *gorm.DB, projectId uint64, folderId uint64) (*model.Folders, error) {
+	if folderId == 0 {
+		return nil, nil
+	}
+	var folder model.Folders
+	if err := db.Where("id = ? AND project_id = ?", folderId, projectId).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errFolderNotFound
+		}
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// lockFolderEntries 锁定目录行（根目录锁定项目行），目录不存在时返回 errFolderNotFound。
+// 按名称查找后新建或移入文件的操作在此串行，避免并发请求在同一目录下创建同名文件
+func lockFolderEntries(tx *gorm.DB, projectId uint64, folderId uint64) error {
+	if folderId == 0 {
+		var project model.Projects
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", projectId).First(&project).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("project not found")
+			}
+			return err
+		}
+		return nil
+	}
+	var folder model.Folders
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ? AND project_id = ?", folderId, projectId).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errFolderNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveFolderPath 从 parentId 出发逐级查找目录，返回最后一级目录 ID
+func resolveFolderPath(db *gorm.DB, projectId uint64, parentId uint64, segs []string) (uint64, error) {
+	for _, seg := range segs {
+		var folder model.Folders
+		if err := db.Where("project_id = ? AND parent_id = ? AND name = ?", projectId, parentId, seg).First(&folder).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, errFolderNotFound
+			}
+			return 0, err
+		}
+		parentId = folder.Id
+	}
+	return parentId, nil
+}
+
+// ensureFolderPath 从 parentId 出发逐级查找目录，不存在的目录自动创建（类似 mkdir -p）
+func ensureFolderPath(tx *gorm.DB, projectId uint64, parentId uint64, segs []string, userId uint64) (uint64, error) {
+	for _, seg := range segs {
+		var folder model.Folders
+		err := tx.Where("project_id = ? AND parent_id = ? AND name = ?", projectId, parentId, seg).First(&folder).Error
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, err
+			}
+			taken, err := fileNameTaken(tx, projectId, parentId, seg)
+			if err != nil {
+				return 0, err
+			}
+			if taken {
+				return 0, errors.New("a file with the same name already exists: " + seg)
+			}
+			folder = model.Folders{ProjectId: projectId, ParentId: parentId, Name: seg, CreatedBy: userId}
+			if err := tx.Create(&folder).Error; err != nil {
+				return 0, err
+			}
+		}
+		parentId = folder.Id
+	}
+	return parentId, nil
+}
+
+// folderNameTaken 同一目录下不能有同名目录
+func folderNameTaken(tx *gorm.DB, projectId uint64, parentId uint64, name string) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.Folders{}).
+		Where("project_id = ? AND parent_id = ? AND name = ?", projectId, parentId, name).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// entryNameTaken 同一目录下文件与目录共用命名空间
+func entryNameTaken(tx *gorm.DB, projectId uint64, folderId uint64, name string) (bool, error) {
+	taken, err := folderNameTaken(tx, projectId, folderId, name)
+	if err != nil || taken {
+		return taken, err
+	}
+	return fileNameTaken(tx, projectId, folderId, name)
+}
+
+// descendantFolderIds 返回目录自身及其全部子目录 ID
+func descendantFolderIds(db *gorm.DB, projectId uint64, folderId uint64) ([]uint64, error) {
+	ids := []uint64{folderId}
+	frontier := []uint64{folderId}
+	for len(frontier) > 0 {
+		var children []uint64
+		if err := db.Model(&model.Folders{}).
+			Where("project_id = ? AND parent_id IN ?", projectId, frontier).
+			Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, children...)
+		frontier = children
+	}
+	return ids, nil
+}
+
+// trashFilesTx 将文件移入回收站：软删除并记录删除人与原路径，版本与内容引用保留以便恢复；文件锁随之释放。
+// 需要在删除所在目录之前调用
+func trashFilesTx(tx *gorm.DB, projectId uint64, fileIds []uint64, userId uint64) error {
+	if len(fileIds) == 0 {
+		return nil
+	}
+	paths, err := model.FolderPaths(tx, projectId)
+	if err != nil {
+		return err
+	}
+	var files []model.Files
+	if err := tx.Where("id IN ?", fileIds).Find(&files).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, f := range files {
+		if err := tx.Model(&model.Files{}).Where("id = ?", f.Id).Updates(map[string]any{
+			"deleted_at":   now,
+			"deleted_by":   userId,
+			"deleted_path": model.JoinFolderPath(paths[f.FolderId], f.Name),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("file_id IN ?", fileIds).Delete(&model.FileLocks{}).Error
+}
+
+func toFolderItem(folder *model.Folders, path string) *types.FolderItem {
+	return &types.FolderItem{
+		Id:        int64(folder.Id),
+		ProjectId: int64(folder.ProjectId),
+		ParentId:  int64(folder.ParentId),
+		Name:      folder.Name,
+		Path:      path,
+		CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// currentVersions 批量读取文件的当前版本，没有当前版本的文件不在结果中
+func currentVersions(db *gorm.DB, files []model.Files) (map[uint64]*model.FileVersions, error) {
+	ids := make([]uint64, 0, len(files))
+	for _, f := range files {
+		if f.CurrentVersionId > 0 {
+			ids = append(ids, f.CurrentVersionId)
+		}
+	}
+	result := make(map[uint64]*model.FileVersions, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var versions []model.FileVersions
+	if err := db.Where("id IN ?", ids).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		result[versions[i].FileId] = &versions[i]
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetChangesetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetChangesetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetChangesetLogic {
+	return &GetChangesetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetChangesetLogic) GetChangeset(req *types.GetChangesetReq) (resp *types.ChangesetDetailResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 {
+		return nil, errChangesetNotFound
+	}
+	changeset, _, err := loadChangeset(l.ctx, l.svcCtx, req.Id, userId, permission.ProjectView)
+	if err != nil {
+		return nil, err
+	}
+	return changesetDetail(l.svcCtx.DB.WithContext(l.ctx), changeset)
+}
@@ -0,0 +1,81 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetFileByPathLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetFileByPathLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetFileByPathLogic {
+	return &GetFileByPathLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetFileByPath 按项目内路径（如 src/main.go）查找文件并返回当前版本信息
+func (l *GetFileByPathLogic) GetFileByPath(req *types.GetFileByPathReq) (resp *types.ProjectFileItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	segs, err := splitPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	folderId, err := resolveFolderPath(db, projectId, 0, segs[:len(segs)-1])
+	if err != nil {
+		if errors.Is(err, errFolderNotFound) {
+			return nil, errors.New("file not found")
+		}
+		return nil, err
+	}
+	var file model.Files
+	if err := db.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ? AND files.folder_id = ? AND files.name = ?", projectId, folderId, segs[len(segs)-1]).
+		First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("file not found")
+		}
+		return nil, err
+	}
+
+	var current model.FileVersions
+	if file.CurrentVersionId > 0 {
+		if err := db.Where("id = ?", file.CurrentVersionId).First(&current).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	paths, err := model.FolderPaths(db, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return toProjectFileItem(projectId, paths[folderId], &file, &current), nil
+}
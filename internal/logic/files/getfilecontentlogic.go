@@ -7,9 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/storage"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -31,11 +32,11 @@ func NewGetFileContentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Ge
 }
 
 // GetFileContent 获取文件内容（代理访问）
-// 直接返回文件内容，适合图片预览等场景
-func (l *GetFileContentLogic) GetFileContent(req *types.GetFileContentReq) (io.ReadCloser, string, error) {
+// 返回当前版本对象的元信息，由 handler 按 Range / 条件请求输出，适合图片预览、音视频拖动等场景
+func (l *GetFileContentLogic) GetFileContent(req *types.GetFileContentReq) (*storage.ObjectContent, error) {
 	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
 	if !ok {
-		return nil, "", errors.New("unauthorized")
+		return nil, errors.New("unauthorized")
 	}
 	userId, _ := userIdNumber.Int64()
 
@@ -46,26 +47,20 @@ func (l *GetFileContentLogic) GetFileContent(req *types.GetFileContentReq) (io.R
 		First(&file).Error
 	if err != nil {
 		if errors.Is(err, model.ErrNotFound) {
-			return nil, "", errors.New("file not found")
+			return nil, errors.New("file not found")
 		}
-		return nil, "", err
+		return nil, err
 	}
 
 	// Get project_id from project_files
 	var projectFile model.ProjectFiles
 	if err := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ?", file.Id).First(&projectFile).Error; err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	// 检查用户是否有权限访问该项目的文件
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", projectFile.ProjectId, userId).
-		Count(&count).Error; err != nil {
-		return nil, "", err
-	}
-	if count == 0 {
-		return nil, "", errors.New("project not found or permission denied")
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectFile.ProjectId, userId, permission.ProjectView); err != nil {
+		return nil, err
 	}
 
 	// 获取当前版本信息
@@ -75,19 +70,13 @@ func (l *GetFileContentLogic) GetFileContent(req *types.GetFileContentReq) (io.R
 		First(&version).Error
 	if err != nil {
 		if errors.Is(err, model.ErrNotFound) {
-			return nil, "", errors.New("file version not found")
+			return nil, errors.New("file version not found")
 		}
-		return nil, "", err
+		return nil, err
 	}
 
-	// 从 OSS 获取文件内容
 	if l.svcCtx.ObjectStore == nil {
-		return nil, "", errors.New("object store not configured")
-	}
-	reader, err := l.svcCtx.ObjectStore.GetObject(l.ctx, version.StorageKey)
-	if err != nil {
-		l.Errorf("[GetFileContent] Failed to get object from OSS: %v", err)
-		return nil, "", err
+		return nil, errors.New("object store not configured")
 	}
 
 	// 获取 Content-Type
@@ -96,5 +85,12 @@ func (l *GetFileContentLogic) GetFileContent(req *types.GetFileContentReq) (io.R
 	l.Infof("[GetFileContent] Serving file content for fileId=%d, versionId=%d, contentType=%s",
 		req.Id, version.Id, contentType)
 
-	return reader, contentType, nil
+	// ETag 取自版本内容 hash，内容不变时客户端可用 If-None-Match 得到 304
+	return &storage.ObjectContent{
+		Key:          version.StorageKey,
+		ContentType:  contentType,
+		SizeBytes:    int64(version.SizeBytes),
+		ETag:         `"` + version.Hash + `"`,
+		LastModified: version.CreatedAt,
+	}, nil
 }
@@ -0,0 +1,185 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/textdiff"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// diffMaxBytes 参与比较的单个版本大小上限
+const diffMaxBytes = 2 << 20
+
+// diffMaxContext 上下文行数上限
+const diffMaxContext = 100
+
+var (
+	ErrDiffNotText     = errorx.New(http.StatusUnprocessableEntity, "diff_not_supported", "diff is only supported for text files")
+	ErrDiffBinary      = errorx.New(http.StatusUnprocessableEntity, "binary_content", "version content is binary")
+	ErrDiffTooLarge    = errorx.New(http.StatusRequestEntityTooLarge, "diff_too_large", fmt.Sprintf("version content exceeds %d bytes", diffMaxBytes))
+	errVersionNotReady = errors.New("file version not found or not ready")
+)
+
+type GetFileDiffLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetFileDiffLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetFileDiffLogic {
+	return &GetFileDiffLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetFileDiff 比较文本文件的两个版本，返回统一差异文本与结构化的段列表。
+// to 默认当前版本，from 默认 to 之前最近的已完成版本
+func (l *GetFileDiffLogic) GetFileDiff(req *types.GetFileDiffReq) (resp *types.FileDiffResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.From < 0 || req.To < 0 {
+		return nil, model.InputParamInvalid
+	}
+	file, projectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+	if file.FileCategory != "text" {
+		return nil, ErrDiffNotText
+	}
+	if l.svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx).Where("file_id = ? AND status = ?", file.Id, model.FileVersionStatusReady)
+	var to model.FileVersions
+	if req.To > 0 {
+		err = db.Session(&gorm.Session{}).Where("version_number = ?", req.To).First(&to).Error
+	} else {
+		err = db.Session(&gorm.Session{}).Where("id = ?", file.CurrentVersionId).First(&to).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errVersionNotReady
+		}
+		return nil, err
+	}
+	var from model.FileVersions
+	if req.From > 0 {
+		err = db.Session(&gorm.Session{}).Where("version_number = ?", req.From).First(&from).Error
+	} else {
+		err = db.Session(&gorm.Session{}).Where("version_number < ?", to.VersionNumber).Order("version_number DESC").First(&from).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errVersionNotReady
+		}
+		return nil, err
+	}
+
+	resp = &types.FileDiffResp{
+		FileId:        int64(file.Id),
+		FromVersionId: int64(from.Id),
+		FromVersion:   int64(from.VersionNumber),
+		ToVersionId:   int64(to.Id),
+		ToVersion:     int64(to.VersionNumber),
+		Hunks:         make([]types.FileDiffHunk, 0),
+	}
+	// 内容相同（含去重后共用对象）无需读取存储
+	if from.Hash == to.Hash && from.Hash != "" {
+		resp.Identical = true
+		return resp, nil
+	}
+
+	oldData, err := l.readVersion(&from)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := l.readVersion(&to)
+	if err != nil {
+		return nil, err
+	}
+
+	contextLines := int(req.Context)
+	if contextLines > diffMaxContext {
+		contextLines = diffMaxContext
+	}
+	hunks := textdiff.Diff(textdiff.SplitLines(oldData), textdiff.SplitLines(newData), contextLines)
+	resp.Identical = len(hunks) == 0
+	for i := range hunks {
+		h := &hunks[i]
+		item := types.FileDiffHunk{
+			Header:   h.Header(),
+			OldStart: int64(h.OldStart),
+			OldLines: int64(h.OldLines),
+			NewStart: int64(h.NewStart),
+			NewLines: int64(h.NewLines),
+			Lines:    make([]types.FileDiffLine, 0, len(h.Lines)),
+		}
+		for _, line := range h.Lines {
+			switch line.Type {
+			case textdiff.LineAdd:
+				resp.Additions++
+			case textdiff.LineDelete:
+				resp.Deletions++
+			}
+			item.Lines = append(item.Lines, types.FileDiffLine{
+				Type:    line.Type,
+				Content: line.Content,
+				OldLine: int64(line.OldLine),
+				NewLine: int64(line.NewLine),
+			})
+		}
+		resp.Hunks = append(resp.Hunks, item)
+	}
+	resp.Unified = textdiff.Unified(
+		fmt.Sprintf("%s@v%d", file.Name, from.VersionNumber),
+		fmt.Sprintf("%s@v%d", file.Name, to.VersionNumber),
+		hunks,
+	)
+	return resp, nil
+}
+
+// readVersion 读取版本内容，超过大小上限或为二进制内容时返回错误
+func (l *GetFileDiffLogic) readVersion(version *model.FileVersions) ([]byte, error) {
+	if version.SizeBytes > diffMaxBytes {
+		return nil, ErrDiffTooLarge
+	}
+	reader, err := l.svcCtx.ObjectStore.GetObject(l.ctx, version.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(io.LimitReader(reader, diffMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > diffMaxBytes {
+		return nil, ErrDiffTooLarge
+	}
+	if textdiff.IsBinary(data) {
+		return nil, ErrDiffBinary
+	}
+	return data, nil
+}
@@ -0,0 +1,99 @@
+package files
+
+import (
+	"context"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type InitiateMultipartUploadLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewInitiateMultipartUploadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *InitiateMultipartUploadLogic {
+	return &InitiateMultipartUploadLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// InitiateMultipartUpload 创建版本记录与对象存储分片上传会话，版本在 complete 后才成为当前版本
+func (l *InitiateMultipartUploadLogic) InitiateMultipartUpload(req *types.InitiateMultipartUploadReq) (resp *types.InitiateMultipartUploadResp, err error) {
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	prepared, err := NewPreUploadFileLogic(l.ctx, l.svcCtx).prepareUpload(&types.PreUploadReq{
+		ProjectId:                req.ProjectId,
+		Name:                     req.Name,
+		Path:                     req.Path,
+		FolderId:                 req.FolderId,
+		FileCategory:             req.FileCategory,
+		FileFormat:               req.FileFormat,
+		SizeBytes:                req.SizeBytes,
+		Hash:                     req.Hash,
+		ContentType:              req.ContentType,
+		Message:                  req.Message,
+		Labels:                   req.Labels,
+		ChangesetId:              req.ChangesetId,
+		ExpectedCurrentVersionId: req.ExpectedCurrentVersionId,
+		IfMatch:                  req.IfMatch,
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	version := prepared.version
+
+	if prepared.deduplicated {
+		return &types.InitiateMultipartUploadResp{
+			FileId:        int64(prepared.file.Id),
+			VersionId:     int64(version.Id),
+			VersionNumber: int64(version.VersionNumber),
+			ContentType:   prepared.contentType,
+			SkipUpload:    true,
+		}, nil
+	}
+
+	uploadId, err := l.svcCtx.ObjectStore.CreateMultipartUpload(l.ctx, version.StorageKey, prepared.contentType)
+	if err != nil {
+		l.Errorf("[MultipartUpload] Failed to create multipart upload for %s: %v", version.StorageKey, err)
+		discardPreparedVersion(l.ctx, l.svcCtx, version)
+		return nil, err
+	}
+
+	partSize := normalizeMultipartPartSize(req.SizeBytes, req.PartSize)
+	upload := &model.MultipartUploads{
+		UploadId:      uploadId,
+		ProjectId:     uint64(req.ProjectId),
+		FileId:        prepared.file.Id,
+		FileVersionId: version.Id,
+		StorageKey:    version.StorageKey,
+		ContentType:   prepared.contentType,
+		SizeBytes:     uint64(req.SizeBytes),
+		PartSize:      uint64(partSize),
+		Status:        model.MultipartStatusUploading,
+		CreatedBy:     uint64(prepared.userId),
+	}
+	if err := l.svcCtx.DB.WithContext(l.ctx).Create(upload).Error; err != nil {
+		_ = l.svcCtx.ObjectStore.AbortMultipartUpload(l.ctx, version.StorageKey, uploadId)
+		discardPreparedVersion(l.ctx, l.svcCtx, version)
+		return nil, err
+	}
+	l.Infof("[MultipartUpload] Initiated id=%d, versionId=%d, key=%s, partSize=%d", upload.Id, version.Id, version.StorageKey, partSize)
+
+	return &types.InitiateMultipartUploadResp{
+		UploadId:      int64(upload.Id),
+		FileId:        int64(prepared.file.Id),
+		VersionId:     int64(version.Id),
+		VersionNumber: int64(version.VersionNumber),
+		ContentType:   prepared.contentType,
+		PartSize:      partSize,
+		PartCount:     multipartPartCount(req.SizeBytes, partSize),
+	}, nil
+}
@@ -0,0 +1,90 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListChangesetsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListChangesetsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListChangesetsLogic {
+	return &ListChangesetsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListChangesetsLogic) ListChangesets(req *types.ListChangesetsReq) (resp *types.ChangesetListResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	switch req.Status {
+	case "", model.ChangesetStatusOpen, model.ChangesetStatusCommitted, model.ChangesetStatusAborted, model.ChangesetStatusRolledBack:
+	default:
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	page := int(req.Page)
+	size := int(req.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	query := db.Model(&model.Changesets{}).Where("project_id = ?", req.ProjectId)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var list []model.Changesets
+	if err := query.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]types.ChangesetItem, 0, len(list))
+	for i := range list {
+		item, err := changesetItem(db, &list[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return &types.ChangesetListResp{
+		List: items,
+		Page: types.PageResp{
+			Page:     int64(page),
+			PageSize: int64(size),
+			Total:    total,
+		},
+	}, nil
+}
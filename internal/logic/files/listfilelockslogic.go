@@ -0,0 +1,57 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListFileLocksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListFileLocksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListFileLocksLogic {
+	return &ListFileLocksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListFileLocks 返回项目内未过期的文件锁
+func (l *ListFileLocksLogic) ListFileLocks(req *types.ListFileLocksReq) (resp *types.FileLockListResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	var locks []model.FileLocks
+	if err := db.Where("project_id = ? AND expires_at > ?", projectId, time.Now()).Order("expires_at ASC").Find(&locks).Error; err != nil {
+		return nil, err
+	}
+	items, err := fileLockItems(db, projectId, locks)
+	if err != nil {
+		return nil, err
+	}
+	return &types.FileLockListResp{List: items}, nil
+}
@@ -7,12 +7,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type ListFileVersionsLogic struct {
@@ -59,13 +62,9 @@ func (l *ListFileVersionsLogic) ListFileVersions(req *types.ListFileVersionsReq)
 	}
 
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", projectFile.ProjectId, userId).Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectFile.ProjectId, userId, permission.ProjectView); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
-		}
 	}
 
 	page := int(req.Page)
@@ -80,27 +79,25 @@ func (l *ListFileVersionsLogic) ListFileVersions(req *types.ListFileVersionsReq)
 		size = 100
 	}
 	offset := (page - 1) * size
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	query := func() *gorm.DB {
+		q := db.Model(&model.FileVersions{}).Where("file_id = ?", req.Id)
+		if label := strings.TrimSpace(req.Label); label != "" {
+			q = q.Where("id IN (?)", db.Model(&model.FileVersionLabels{}).Select("version_id").Where("file_id = ? AND label = ?", req.Id, label))
+		}
+		return q
+	}
 	var list []model.FileVersions
-	if err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("file_id = ?", req.Id).Offset(offset).Limit(size).Order("version_number desc").Find(&list).Error; err != nil {
+	if err = query().Offset(offset).Limit(size).Order("version_number desc").Find(&list).Error; err != nil {
 		return nil, err
 	}
 	var total int64
-	if err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("file_id = ?", req.Id).Count(&total).Error; err != nil {
+	if err = query().Count(&total).Error; err != nil {
 		return nil, err
 	}
-	items := make([]types.FileVersionItem, 0, len(list))
-	for _, v := range list {
-		items = append(items, types.FileVersionItem{
-			Id:            int64(v.Id),
-			FileId:        int64(v.FileId),
-			VersionNumber: int64(v.VersionNumber),
-			SizeBytes:     int64(v.SizeBytes),
-			Hash:          v.Hash,
-			StorageKey:    v.StorageKey,
-			CreatedAt:     v.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:     v.UpdatedAt.Format("2006-01-02 15:04:05"),
-			CreatedBy:     int64(v.CreatedBy),
-		})
+	items, err := fileVersionItems(db, list)
+	if err != nil {
+		return nil, err
 	}
 	resp = &types.FileVersionListResp{
 		List: items,
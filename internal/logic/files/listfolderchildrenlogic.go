@@ -0,0 +1,94 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListFolderChildrenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListFolderChildrenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListFolderChildrenLogic {
+	return &ListFolderChildrenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListFolderChildren 列出目录下的直接子目录与文件，folderId 为 0 表示项目根目录
+func (l *ListFolderChildrenLogic) ListFolderChildren(req *types.ListFolderChildrenReq) (resp *types.FolderChildrenResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FolderId < 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+	folderId := uint64(req.FolderId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	folder, err := loadFolder(db, projectId, folderId)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := model.FolderPaths(db, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.FolderChildrenResp{
+		Folder:  types.FolderItem{ProjectId: req.ProjectId},
+		Folders: make([]types.FolderItem, 0),
+		Files:   make([]types.ProjectFileItem, 0),
+	}
+	if folder != nil {
+		resp.Folder = *toFolderItem(folder, paths[folder.Id])
+	}
+
+	var folders []model.Folders
+	if err := db.Where("project_id = ? AND parent_id = ?", projectId, folderId).Order("name ASC").Find(&folders).Error; err != nil {
+		return nil, err
+	}
+	for i := range folders {
+		resp.Folders = append(resp.Folders, *toFolderItem(&folders[i], paths[folders[i].Id]))
+	}
+
+	var files []model.Files
+	if err := db.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ? AND files.folder_id = ?", projectId, folderId).
+		Order("files.name ASC").Find(&files).Error; err != nil {
+		return nil, err
+	}
+	versions, err := currentVersions(db, files)
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		version, ok := versions[files[i].Id]
+		if !ok {
+			// 尚无已完成版本的文件只返回文件信息
+			version = &model.FileVersions{}
+		}
+		resp.Files = append(resp.Files, *toProjectFileItem(projectId, paths[folderId], &files[i], version))
+	}
+	return resp, nil
+}
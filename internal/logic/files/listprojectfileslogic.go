@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -41,13 +42,9 @@ func (l *ListProjectFilesLogic) ListProjectFiles(req *types.ListProjectFilesReq)
 	}
 
 	// Check project membership
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", req.ProjectId, userId).Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	page := int(req.Page)
 	size := int(req.PageSize)
@@ -68,15 +65,21 @@ func (l *ListProjectFilesLogic) ListProjectFiles(req *types.ListProjectFilesReq)
 		Offset(offset).Limit(size).Order("files.id desc").Find(&files).Error; err != nil {
 		return nil, err
 	}
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), uint64(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
 	items := make([]types.ProjectFileItem, 0, len(files))
 	for _, f := range files {
 		// 获取当前版本（由 current_version_id 指定）
 		var currentVersion model.FileVersions
 		err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("id = ?", f.CurrentVersionId).First(&currentVersion).Error
 		if err != nil {
-			// 如果当前版本找不到，回退到最新版本
+			// 如果当前版本找不到，回退到最新版本（优先已完成上传的版本）
 			l.Logger.Infof("Current version %d not found for file %d, fallback to latest", f.CurrentVersionId, f.Id)
-			err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("file_id = ?", f.Id).Order("version_number desc").Limit(1).First(&currentVersion).Error
+			err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("file_id = ?", f.Id).
+				Order("status = 'ready' DESC, version_number DESC").
+				Limit(1).First(&currentVersion).Error
 			if err != nil {
 				return nil, err
 			}
@@ -85,6 +88,8 @@ func (l *ListProjectFilesLogic) ListProjectFiles(req *types.ListProjectFilesReq)
 			Id:               int64(f.Id),
 			ProjectId:        req.ProjectId,
 			Name:             f.Name,
+			FolderId:         int64(f.FolderId),
+			Path:             model.JoinFolderPath(paths[f.FolderId], f.Name),
 			FileCategory:     f.FileCategory,
 			FileFormat:       f.FileFormat,
 			CurrentVersionId: int64(f.CurrentVersionId),
@@ -0,0 +1,55 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListRetentionPoliciesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListRetentionPoliciesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListRetentionPoliciesLogic {
+	return &ListRetentionPoliciesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListRetentionPolicies 列出项目的版本保留策略，项目成员可查看
+func (l *ListRetentionPoliciesLogic) ListRetentionPolicies(req *types.ListRetentionPoliciesReq) (resp *types.RetentionPolicyListResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	var policies []model.VersionRetentionPolicies
+	if err := l.svcCtx.DB.WithContext(l.ctx).Where("project_id = ?", req.ProjectId).
+		Order("path_pattern ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	items := make([]types.RetentionPolicyItem, 0, len(policies))
+	for i := range policies {
+		items = append(items, *retentionPolicyItem(&policies[i]))
+	}
+	return &types.RetentionPolicyListResp{List: items}, nil
+}
@@ -0,0 +1,84 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListTrashLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListTrashLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListTrashLogic {
+	return &ListTrashLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListTrash 列出项目回收站中的文件，按删除时间倒序；早于回收站功能、已无版本记录的删除文件不列出
+func (l *ListTrashLogic) ListTrash(req *types.ListTrashReq) (resp *types.TrashListResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	page := int(req.Page)
+	size := int(req.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	query := db.Unscoped().Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ? AND files.deleted_at IS NOT NULL", req.ProjectId).
+		Where("EXISTS (SELECT 1 FROM file_versions WHERE file_versions.file_id = files.id)")
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var list []model.Files
+	if err := query.Select("files.*").Order("files.deleted_at DESC, files.id DESC").
+		Offset((page - 1) * size).Limit(size).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	items, err := trashItems(db, list, l.svcCtx.TrashRetention())
+	if err != nil {
+		return nil, err
+	}
+	return &types.TrashListResp{
+		List: items,
+		Page: types.PageResp{
+			Page:     int64(page),
+			PageSize: int64(size),
+			Total:    total,
+		},
+	}, nil
+}
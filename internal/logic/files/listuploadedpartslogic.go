@@ -0,0 +1,61 @@
+package files
+
+import (
+	"context"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListUploadedPartsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListUploadedPartsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListUploadedPartsLogic {
+	return &ListUploadedPartsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListUploadedParts 返回已上传的分片，客户端据此断点续传缺失的分片
+func (l *ListUploadedPartsLogic) ListUploadedParts(req *types.ListUploadedPartsReq) (resp *types.UploadedPartListResp, err error) {
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	upload, err := loadMultipartUpload(l.ctx, l.svcCtx, req.Id, permission.ProjectView)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.UploadedPartListResp{
+		Status:    upload.Status,
+		PartSize:  int64(upload.PartSize),
+		PartCount: multipartPartCount(int64(upload.SizeBytes), int64(upload.PartSize)),
+		Parts:     []types.UploadedPartItem{},
+	}
+	if upload.Status != model.MultipartStatusUploading {
+		return resp, nil
+	}
+
+	parts, err := l.svcCtx.ObjectStore.ListUploadedParts(l.ctx, upload.StorageKey, upload.UploadId)
+	if err != nil {
+		l.Errorf("[MultipartUpload] Failed to list parts of id=%d: %v", upload.Id, err)
+		return nil, err
+	}
+	for _, p := range parts {
+		resp.Parts = append(resp.Parts, types.UploadedPartItem{
+			PartNumber: int64(p.PartNumber),
+			ETag:       p.ETag,
+			SizeBytes:  p.SizeBytes,
+		})
+	}
+	return resp, nil
+}
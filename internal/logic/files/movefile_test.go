@@ -0,0 +1,51 @@
+package files
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestMoveFileBlockedByReferences(t *testing.T) {
+	cases := []struct {
+		name       string
+		changesets int64
+		manifests  int64
+		want       error
+	}{
+		{"open changeset", 1, 0, ErrFileInChangeset},
+		{"software manifest", 0, 1, ErrFileReferenced},
+	}
+	for _, tc := range cases {
+		db, err := permissiontest.Open(permissiontest.Options{
+			Role: permission.RoleAdmin,
+			Tables: map[string]permissiontest.Table{
+				"files": {
+					Columns: []string{"id", "name", "current_version_id"},
+					Rows:    [][]driver.Value{{int64(7), "a.txt", int64(11)}},
+				},
+				"project_files":      {Columns: []string{"project_id", "file_id"}, Rows: [][]driver.Value{{int64(1), int64(7)}}},
+				"file_versions":      {Columns: []string{"sum"}, Rows: [][]driver.Value{{int64(3)}}},
+				"file_locks":         {Columns: []string{"id"}},
+				"storage_quotas":     {Columns: []string{"id"}},
+				"changeset_files":    {Columns: []string{"count"}, Rows: [][]driver.Value{{tc.changesets}}},
+				"software_manifests": {Columns: []string{"count"}, Rows: [][]driver.Value{{tc.manifests}}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewMoveFileLogic(ctx, &svc.ServiceContext{DB: db}).MoveFile(&types.MoveFileReq{Id: 7, TargetProjectId: 2})
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type MoveFileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewMoveFileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *MoveFileLogic {
+	return &MoveFileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+var (
+	ErrFileInChangeset = errorx.New(http.StatusConflict, "file_in_changeset", "file has uploads in an open changeset")
+	ErrFileReferenced  = errorx.New(http.StatusConflict, "file_referenced", "file is referenced by software manifests, builds or releases")
+)
+
+// MoveFile 将文件及其全部版本移动到目标项目。对象按内容存储、与项目无关，
+// 只需改写 project_files 关联，文件 ID 与版本历史保持不变。被他人锁定、在打开的变更集中，
+// 或仍被源项目的软件清单、构建、发布引用的文件不能移动，否则这些记录会指向其它项目的文件。
+func (l *MoveFileLogic) MoveFile(req *types.MoveFileReq) (resp *types.ProjectFileItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.TargetProjectId <= 0 || req.TargetFolderId < 0 {
+		return nil, model.InputParamInvalid
+	}
+
+	file, sourceProjectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	targetProjectId := uint64(req.TargetProjectId)
+	if sourceProjectId == targetProjectId {
+		return nil, errors.New("file already belongs to target project")
+	}
+
+	// 移出项目等同于在源项目删除文件，需要 owner 或 admin
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, sourceProjectId, userId, permission.FileDelete); err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, targetProjectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = file.Name
+	} else if err := validateEntryName(name); err != nil {
+		return nil, err
+	}
+	targetFolderId := uint64(req.TargetFolderId)
+
+	var totalBytes int64
+	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).
+		Select("COALESCE(SUM(size_bytes),0)").
+		Where("file_id = ? AND status <> ?", file.Id, model.FileVersionStatusFailed).
+		Scan(&totalBytes).Error; err != nil {
+		return nil, err
+	}
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		// 用户用量按上传者统计，移动不改变；只校验目标项目配额。与预上传一致，配额先于文件行加锁
+		if err := l.svcCtx.CheckStorageQuota(tx, targetProjectId, 0, totalBytes); err != nil {
+			return err
+		}
+		// 锁住文件行，与预上传、变更集提交串行
+		if _, err := lockFileRow(tx, file.Id); err != nil {
+			return err
+		}
+		if err := checkFileLock(tx, file.Id, userId); err != nil {
+			return err
+		}
+		if err := checkFileMovable(tx, sourceProjectId, file.Id); err != nil {
+			return err
+		}
+		if err := lockFolderEntries(tx, targetProjectId, targetFolderId); err != nil {
+			return err
+		}
+		taken, err := entryNameTaken(tx, targetProjectId, targetFolderId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a file or folder with the same name already exists in target folder")
+		}
+		if err := tx.Model(&model.ProjectFiles{}).
+			Where("file_id = ? AND project_id = ?", file.Id, sourceProjectId).
+			Update("project_id", targetProjectId).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MultipartUploads{}).
+			Where("file_id = ? AND project_id = ?", file.Id, sourceProjectId).
+			Update("project_id", targetProjectId).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.FileLocks{}).
+			Where("file_id = ?", file.Id).
+			Update("project_id", targetProjectId).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Files{}).Where("id = ?", file.Id).
+			Updates(map[string]any{"name": name, "folder_id": targetFolderId}).Error; err != nil {
+			return err
+		}
+		file.Name = name
+		file.FolderId = targetFolderId
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var current model.FileVersions
+	if file.CurrentVersionId > 0 {
+		if err := l.svcCtx.DB.WithContext(l.ctx).Where("id = ?", file.CurrentVersionId).First(&current).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), targetProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[MoveFile] UserId=%d moved fileId=%d from project %d to project %d", userId, file.Id, sourceProjectId, targetProjectId)
+	return toProjectFileItem(targetProjectId, paths[targetFolderId], file, &current), nil
+}
+
+// checkFileMovable 文件在源项目打开的变更集中时返回 ErrFileInChangeset，仍被软件清单、构建或发布引用时返回 ErrFileReferenced
+func checkFileMovable(tx *gorm.DB, projectId uint64, fileId uint64) error {
+	var count int64
+	if err := tx.Model(&model.ChangesetFiles{}).
+		Joins("JOIN changesets ON changesets.id = changeset_files.changeset_id").
+		Where("changeset_files.file_id = ? AND changesets.project_id = ? AND changesets.status = ?",
+			fileId, projectId, model.ChangesetStatusOpen).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFileInChangeset
+	}
+
+	references := []struct {
+		model  any
+		column string
+	}{
+		{&model.SoftwareManifests{}, "manifest_file_id"},
+		{&model.BuildVersions{}, "build_version_file_id"},
+		{&model.Releases{}, "release_manifest_file_id"},
+	}
+	for _, ref := range references {
+		if err := tx.Model(ref.model).
+			Where("project_id = ? AND "+ref.column+" = ?", projectId, fileId).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrFileReferenced
+		}
+	}
+	return nil
+}
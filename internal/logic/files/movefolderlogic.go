@@ -0,0 +1,93 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type MoveFolderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewMoveFolderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *MoveFolderLogic {
+	return &MoveFolderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// MoveFolder 将目录连同其内容移动到同一项目的另一个目录下，不能移动到自身或其子目录中
+func (l *MoveFolderLogic) MoveFolder(req *types.MoveFolderReq) (resp *types.FolderItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FolderId <= 0 || req.ParentId < 0 {
+		return nil, model.InputParamInvalid
+	}
+
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	parentId := uint64(req.ParentId)
+	var folder *model.Folders
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		folder, err = loadFolder(tx, projectId, uint64(req.FolderId))
+		if err != nil {
+			return err
+		}
+		if folder.ParentId == parentId {
+			return nil
+		}
+		if _, err := loadFolder(tx, projectId, parentId); err != nil {
+			return err
+		}
+		subtree, err := descendantFolderIds(tx, projectId, folder.Id)
+		if err != nil {
+			return err
+		}
+		for _, id := range subtree {
+			if id == parentId {
+				return errors.New("cannot move a folder into itself or its subfolder")
+			}
+		}
+		taken, err := entryNameTaken(tx, projectId, parentId, folder.Name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a file or folder with the same name already exists in target folder")
+		}
+		if err := tx.Model(&model.Folders{}).Where("id = ?", folder.Id).Update("parent_id", parentId).Error; err != nil {
+			return err
+		}
+		folder.ParentId = parentId
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), projectId)
+	if err != nil {
+		return nil, err
+	}
+	l.Infof("[MoveFolder] UserId=%d, ProjectId=%d, FolderId=%d, Path=%s", userId, projectId, folder.Id, paths[folder.Id])
+	return toFolderItem(folder, paths[folder.Id]), nil
+}
@@ -0,0 +1,100 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMultipartPartSize int64 = 16 << 20
+	minMultipartPartSize     int64 = 5 << 20
+	maxMultipartParts        int64 = 10000
+)
+
+// normalizeMultipartPartSize 分片大小不小于 5MB（S3/OSS 限制），分片数不超过 10000
+func normalizeMultipartPartSize(sizeBytes int64, partSize int64) int64 {
+	if partSize <= 0 {
+		partSize = defaultMultipartPartSize
+	}
+	if partSize < minMultipartPartSize {
+		partSize = minMultipartPartSize
+	}
+	if sizeBytes > partSize*maxMultipartParts {
+		partSize = (sizeBytes + maxMultipartParts - 1) / maxMultipartParts
+	}
+	return partSize
+}
+
+func multipartPartCount(sizeBytes int64, partSize int64) int64 {
+	if sizeBytes <= 0 || partSize <= 0 {
+		return 0
+	}
+	return (sizeBytes + partSize - 1) / partSize
+}
+
+// discardPreparedVersion 初始化分片上传失败时回收刚插入的版本及其内容引用
+func discardPreparedVersion(ctx context.Context, svcCtx *svc.ServiceContext, version *model.FileVersions) {
+	_ = svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := model.ReleaseFileBlobs(tx, []model.FileVersions{*version}); err != nil {
+			return err
+		}
+		if err := tx.Where("version_id = ?", version.Id).Delete(&model.FileVersionLabels{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", version.Id).Delete(&model.FileVersions{}).Error
+	})
+}
+
+// loadMultipartUpload 查询上传会话，仅会话创建者可以继续操作，且仍需具备项目中 action 的权限：
+// 被降级、移出项目或项目归档后不能再继续上传；管理员不受角色限制，但归档项目同样只读
+func loadMultipartUpload(ctx context.Context, svcCtx *svc.ServiceContext, id int64, action permission.Action) (*model.MultipartUploads, error) {
+	adminIdNumber, ok := ctx.Value("adminId").(json.Number)
+	isAdmin := ok
+	userIdNumber := adminIdNumber
+	if !ok {
+		userIdNumber, ok = ctx.Value("userId").(json.Number)
+		if !ok {
+			return nil, errors.New("unauthorized")
+		}
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if id <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if svcCtx.DB == nil {
+		return nil, errors.New("db not configured")
+	}
+	if svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+
+	var upload model.MultipartUploads
+	if err := svcCtx.DB.WithContext(ctx).Where("id = ?", id).First(&upload).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("multipart upload not found")
+		}
+		return nil, err
+	}
+	if isAdmin {
+		if permission.Writes(action) {
+			if err := permission.CheckWritable(ctx, svcCtx.DB, upload.ProjectId); err != nil {
+				return nil, err
+			}
+		}
+		return &upload, nil
+	}
+	if upload.CreatedBy != uint64(userId) {
+		return nil, errors.New("multipart upload not found")
+	}
+	if _, err := permission.Authorize(ctx, svcCtx.DB, upload.ProjectId, userId, action); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
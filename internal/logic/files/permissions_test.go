@@ -0,0 +1,294 @@
+package files
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+func TestPreUploadRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: true,
+		permission.RoleViewer:    false,
+	}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewPreUploadFileLogic(ctx, &svc.ServiceContext{DB: db}).PreUploadFile(&types.PreUploadReq{
+			ProjectId:    1,
+			Name:         "a.txt",
+			FileCategory: "text",
+			FileFormat:   "txt",
+			SizeBytes:    3,
+			Hash:         "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		})
+		return err
+	})
+}
+
+func TestPreUploadArchivedProject(t *testing.T) {
+	db, err := permissiontest.OpenProjectDB(permission.RoleDeveloper, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &types.PreUploadReq{
+		ProjectId:    1,
+		Name:         "a.txt",
+		FileCategory: "text",
+		FileFormat:   "txt",
+		SizeBytes:    3,
+		Hash:         "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+	if _, err := NewPreUploadFileLogic(ctx, &svc.ServiceContext{DB: db}).PreUploadFile(req); !errors.Is(err, permission.ErrProjectArchived) {
+		t.Errorf("member: got %v", err)
+	}
+	// 管理员上传同样被拒绝
+	ctx = context.WithValue(context.Background(), "adminId", json.Number("1"))
+	if _, err := NewPreUploadFileLogic(ctx, &svc.ServiceContext{DB: db}).PreUploadFile(req); !errors.Is(err, permission.ErrProjectArchived) {
+		t.Errorf("admin: got %v", err)
+	}
+}
+
+// multipartDB 上传会话 5 由用户 7 在项目 1 中创建，仍在上传中
+func multipartDB(t *testing.T, role string, status string) *gorm.DB {
+	t.Helper()
+	db, err := permissiontest.Open(permissiontest.Options{
+		Role:   role,
+		Status: status,
+		Tables: map[string]permissiontest.Table{
+			"multipart_uploads": {
+				Columns: []string{"id", "upload_id", "project_id", "file_id", "file_version_id", "storage_key", "size_bytes", "part_size", "status", "created_by"},
+				Rows: [][]driver.Value{{int64(5), "u1", int64(1), int64(7), int64(13), "blobs/sha256/ab/abcd",
+					int64(10 << 20), int64(5 << 20), model.MultipartStatusUploading, int64(7)}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// multipartCalls 分片上传的各个后续接口
+func multipartCalls(ctx context.Context, svcCtx *svc.ServiceContext) map[string]func() error {
+	return map[string]func() error{
+		"CompleteMultipartUpload": func() error {
+			_, err := NewCompleteMultipartUploadLogic(ctx, svcCtx).CompleteMultipartUpload(&types.CompleteMultipartUploadReq{Id: 5})
+			return err
+		},
+		"AbortMultipartUpload": func() error {
+			_, err := NewAbortMultipartUploadLogic(ctx, svcCtx).AbortMultipartUpload(&types.AbortMultipartUploadReq{Id: 5})
+			return err
+		},
+		"PresignUploadPart": func() error {
+			_, err := NewPresignUploadPartLogic(ctx, svcCtx).PresignUploadPart(&types.PresignUploadPartReq{Id: 5, PartNumber: 1})
+			return err
+		},
+		"ListUploadedParts": func() error {
+			_, err := NewListUploadedPartsLogic(ctx, svcCtx).ListUploadedParts(&types.ListUploadedPartsReq{Id: 5})
+			return err
+		},
+	}
+}
+
+func TestMultipartUploadArchivedProject(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx := &svc.ServiceContext{DB: multipartDB(t, permission.RoleDeveloper, "archived"), ObjectStore: store}
+	member := context.WithValue(context.Background(), "userId", json.Number("7"))
+	for name, call := range multipartCalls(member, svcCtx) {
+		err := call()
+		if archived := errors.Is(err, permission.ErrProjectArchived); archived != (name != "ListUploadedParts") {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+	// 管理员同样不能在归档项目上继续上传
+	admin := context.WithValue(context.Background(), "adminId", json.Number("1"))
+	if err := multipartCalls(admin, svcCtx)["CompleteMultipartUpload"](); !errors.Is(err, permission.ErrProjectArchived) {
+		t.Errorf("admin: got %v", err)
+	}
+}
+
+// fileRolesDB 文件 7（待完成版本 13）、变更集 3、分片上传会话 5 都属于项目 1，变更集与会话由 author 创建
+func fileRolesDB(t *testing.T, role string, author int64) *gorm.DB {
+	t.Helper()
+	db, err := permissiontest.Open(permissiontest.Options{
+		Role: role,
+		Tables: map[string]permissiontest.Table{
+			"files": {
+				Columns: []string{"id", "name", "current_version_id"},
+				Rows:    [][]driver.Value{{int64(7), "a.txt", int64(11)}},
+			},
+			"project_files": {
+				Columns: []string{"project_id", "file_id"},
+				Rows:    [][]driver.Value{{int64(1), int64(7)}},
+			},
+			"file_versions": {
+				Columns: []string{"id", "file_id", "version_number", "size_bytes", "status"},
+				Rows:    [][]driver.Value{{int64(13), int64(7), int64(3), int64(3), model.FileVersionStatusPending}},
+			},
+			"changesets": {
+				Columns: []string{"id", "project_id", "status", "created_by"},
+				Rows:    [][]driver.Value{{int64(3), int64(1), model.ChangesetStatusOpen, author}},
+			},
+			"multipart_uploads": {
+				Columns: []string{"id", "upload_id", "project_id", "file_id", "file_version_id", "storage_key", "size_bytes", "part_size", "status", "created_by"},
+				Rows: [][]driver.Value{{int64(5), "u1", int64(1), int64(7), int64(13), "blobs/sha256/ab/abcd",
+					int64(10 << 20), int64(5 << 20), model.MultipartStatusUploading, author}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// TestMutatingFileEndpointRoles 通过各接口的 logic 校验角色：被拒绝的角色返回 permission denied，
+// 非成员返回 ErrNotMember，允许的角色在权限校验之后失败或成功都可以
+func TestMutatingFileEndpointRoles(t *testing.T) {
+	writers := map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true, permission.RoleDeveloper: true}
+	managers := map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true}
+	members := map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true, permission.RoleDeveloper: true, permission.RoleViewer: true}
+
+	type roleCase struct {
+		name    string
+		author  int64 // 变更集与分片上传会话的创建者，当前用户为 7
+		allowed map[string]bool
+		call    func(ctx context.Context, svcCtx *svc.ServiceContext) error
+	}
+	cases := []roleCase{
+		{"DeleteFile", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewDeleteFileLogic(ctx, svcCtx).DeleteFile(&types.DeleteFileReq{Id: 7})
+			return err
+		}},
+		{"RollbackVersion", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewRollbackVersionLogic(ctx, svcCtx).RollbackVersion(&types.RollbackVersionReq{Id: 7, VersionNumber: 1})
+			return err
+		}},
+		{"CompleteFileVersion", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCompleteFileVersionLogic(ctx, svcCtx).CompleteFileVersion(&types.CompleteFileVersionReq{Id: 7, VersionId: 13})
+			return err
+		}},
+		{"MoveFile", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewMoveFileLogic(ctx, svcCtx).MoveFile(&types.MoveFileReq{Id: 7, TargetProjectId: 2})
+			return err
+		}},
+		{"CopyFile", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCopyFileLogic(ctx, svcCtx).CopyFile(&types.CopyFileReq{Id: 7, TargetProjectId: 2})
+			return err
+		}},
+		{"AcquireFileLock", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewAcquireFileLockLogic(ctx, svcCtx).AcquireFileLock(&types.AcquireFileLockReq{Id: 7})
+			return err
+		}},
+		{"AcquireFileLock force", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewAcquireFileLockLogic(ctx, svcCtx).AcquireFileLock(&types.AcquireFileLockReq{Id: 7, Force: true})
+			return err
+		}},
+		{"RenewFileLock", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewRenewFileLockLogic(ctx, svcCtx).RenewFileLock(&types.RenewFileLockReq{Id: 7})
+			return err
+		}},
+		{"ReleaseFileLock", 7, members, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewReleaseFileLockLogic(ctx, svcCtx).ReleaseFileLock(&types.ReleaseFileLockReq{Id: 7})
+			return err
+		}},
+		{"ReleaseFileLock force", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewReleaseFileLockLogic(ctx, svcCtx).ReleaseFileLock(&types.ReleaseFileLockReq{Id: 7, Force: true})
+			return err
+		}},
+		{"CreateChangeset", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCreateChangesetLogic(ctx, svcCtx).CreateChangeset(&types.CreateChangesetReq{ProjectId: 1})
+			return err
+		}},
+		{"CommitChangeset", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCommitChangesetLogic(ctx, svcCtx).CommitChangeset(&types.CommitChangesetReq{Id: 3})
+			return err
+		}},
+		{"AbortChangeset", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewAbortChangesetLogic(ctx, svcCtx).AbortChangeset(&types.AbortChangesetReq{Id: 3})
+			return err
+		}},
+		{"AbortChangeset of another user", 8, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewAbortChangesetLogic(ctx, svcCtx).AbortChangeset(&types.AbortChangesetReq{Id: 3})
+			return err
+		}},
+		{"RollbackChangeset", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewRollbackChangesetLogic(ctx, svcCtx).RollbackChangeset(&types.RollbackChangesetReq{Id: 3})
+			return err
+		}},
+		{"RestoreTrashFile", 7, writers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewRestoreTrashFileLogic(ctx, svcCtx).RestoreTrashFile(&types.RestoreTrashFileReq{ProjectId: 1, FileId: 7})
+			return err
+		}},
+		{"PurgeTrashFile", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewPurgeTrashFileLogic(ctx, svcCtx).PurgeTrashFile(&types.PurgeTrashFileReq{ProjectId: 1, FileId: 7})
+			return err
+		}},
+		{"CreateRetentionPolicy", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewCreateRetentionPolicyLogic(ctx, svcCtx).CreateRetentionPolicy(&types.CreateRetentionPolicyReq{ProjectId: 1, KeepLast: 5})
+			return err
+		}},
+		{"UpdateRetentionPolicy", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewUpdateRetentionPolicyLogic(ctx, svcCtx).UpdateRetentionPolicy(&types.UpdateRetentionPolicyReq{ProjectId: 1, PolicyId: 1, KeepLast: 5})
+			return err
+		}},
+		{"DeleteRetentionPolicy", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewDeleteRetentionPolicyLogic(ctx, svcCtx).DeleteRetentionPolicy(&types.DeleteRetentionPolicyReq{ProjectId: 1, PolicyId: 1})
+			return err
+		}},
+		{"PruneVersions", 7, managers, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			_, err := NewPruneVersionsLogic(ctx, svcCtx).PruneVersions(&types.PruneVersionsReq{ProjectId: 1, DryRun: true})
+			return err
+		}},
+	}
+	for name := range multipartCalls(nil, nil) {
+		allowed := writers
+		if name == "ListUploadedParts" {
+			allowed = members
+		}
+		cases = append(cases, roleCase{name, 7, allowed, func(ctx context.Context, svcCtx *svc.ServiceContext) error {
+			return multipartCalls(ctx, svcCtx)[name]()
+		}})
+	}
+
+	store, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			permissiontest.CheckRoles(t, c.allowed, func(role string) error {
+				db := fileRolesDB(t, role, c.author)
+				svcCtx := &svc.ServiceContext{
+					DB:                db,
+					ObjectStore:       store,
+					FilesModel:        model.NewFilesModel(db, nil),
+					FileVersionsModel: model.NewFileVersionsModel(db, nil),
+				}
+				return c.call(ctx, svcCtx)
+			})
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package files
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrVersionConflict = errorx.New(http.StatusConflict, "version_conflict", "file has been modified since it was last read")
+
+// versionConflict details 中附带文件当前版本，客户端据此重新读取后再提交；file 为 nil 表示文件尚不存在
+func versionConflict(file *model.Files) error {
+	details := map[string]any{"fileId": uint64(0), "currentVersionId": uint64(0)}
+	if file != nil {
+		details["fileId"] = file.Id
+		details["currentVersionId"] = file.CurrentVersionId
+	}
+	return ErrVersionConflict.WithDetails(details)
+}
+
+func isVersionConflict(err error) bool {
+	var ce *errorx.CodeError
+	return errors.As(err, &ce) && ce.Code == ErrVersionConflict.Code
+}
+
+// parseIfMatch 拆分 If-Match 中的实体标签，去掉弱标记与引号
+func parseIfMatch(header string) []string {
+	tags := make([]string, 0, 1)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		tag = strings.TrimPrefix(tag, "W/")
+		tag = strings.Trim(tag, `"`)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// checkUploadPrecondition 校验客户端读取时的文件状态仍然有效：expectedVersionId 为当前版本 ID，
+// ifMatch 为当前版本内容的 ETag（内容 hash），* 只要求文件已有当前版本。file 需已在事务中加锁。
+// 返回客户端基于的版本 ID，记录在新版本上供 complete 时再次校验；未带前置条件或只有 * 时返回 0
+func checkUploadPrecondition(tx *gorm.DB, file *model.Files, expectedVersionId int64, ifMatch string) (uint64, error) {
+	var baseVersionId uint64
+	if expectedVersionId > 0 {
+		if file.CurrentVersionId != uint64(expectedVersionId) {
+			return 0, versionConflict(file)
+		}
+		baseVersionId = file.CurrentVersionId
+	}
+	tags := parseIfMatch(ifMatch)
+	if len(tags) == 0 {
+		return baseVersionId, nil
+	}
+	if file.CurrentVersionId == 0 {
+		return 0, versionConflict(file)
+	}
+	var current model.FileVersions
+	if err := tx.Select("id", "hash").Where("id = ?", file.CurrentVersionId).First(&current).Error; err != nil {
+		return 0, err
+	}
+	matched := false
+	for _, tag := range tags {
+		if strings.EqualFold(tag, current.Hash) {
+			return file.CurrentVersionId, nil
+		}
+		matched = matched || tag == "*"
+	}
+	if !matched {
+		return 0, versionConflict(file)
+	}
+	return baseVersionId, nil
+}
+
+// checkBaseVersion complete 时在已加锁的文件行上再次校验：预上传之后有其它版本成为当前版本则冲突，
+// 后完成的上传不会静默覆盖先完成的
+func checkBaseVersion(file *model.Files, version *model.FileVersions) error {
+	if version.BaseVersionId == 0 || file.CurrentVersionId == version.BaseVersionId {
+		return nil
+	}
+	return versionConflict(file)
+}
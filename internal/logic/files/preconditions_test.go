@@ -0,0 +1,197 @@
+package files
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	cases := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"*", []string{"*"}},
+		{`"abc"`, []string{"abc"}},
+		{`W/"abc", "def"`, []string{"abc", "def"}},
+		{` "abc" ,, `, []string{"abc"}},
+	}
+	for _, c := range cases {
+		if got := parseIfMatch(c.header); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("parseIfMatch(%q) = %v, want %v", c.header, got, c.want)
+		}
+	}
+}
+
+const abcHash = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+// preconditionDB 文件 7 的当前版本为 currentVersionId；查询版本时返回 version，为 nil 时返回内容为 abc 的当前版本
+func preconditionDB(t *testing.T, currentVersionId uint64, version *model.FileVersions) *gorm.DB {
+	t.Helper()
+	if version == nil {
+		version = &model.FileVersions{Id: currentVersionId, FileId: 7, SizeBytes: 3, Hash: abcHash, StorageKey: "blobs/old", Status: model.FileVersionStatusReady}
+	}
+	db, err := permissiontest.Open(permissiontest.Options{
+		Role: permission.RoleDeveloper,
+		Tables: map[string]permissiontest.Table{
+			"files": {
+				Columns: []string{"id", "name", "current_version_id"},
+				Rows:    [][]driver.Value{{int64(7), "a.txt", int64(currentVersionId)}},
+			},
+			"file_versions": {
+				Columns: []string{"id", "file_id", "version_number", "size_bytes", "hash", "storage_key", "status", "created_at", "base_version_id"},
+				Rows: [][]driver.Value{{int64(version.Id), int64(version.FileId), int64(version.VersionNumber), int64(version.SizeBytes),
+					version.Hash, version.StorageKey, version.Status, time.Now(), int64(version.BaseVersionId)}},
+			},
+			"project_files":       {Columns: []string{"project_id", "file_id"}, Rows: [][]driver.Value{{int64(1), int64(7)}}},
+			"users":               {Columns: []string{"id"}, Rows: [][]driver.Value{{int64(7)}}},
+			"storage_quotas":      {Columns: []string{"id"}},
+			"file_blobs":          {Columns: []string{"id"}},
+			"file_version_labels": {Columns: []string{"id"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPreUploadStalePrecondition(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name     string
+		expected int64
+		ifMatch  string
+	}{
+		{"expected version", 10, ""},
+		{"if-match", 0, `"` + strings.Repeat("0", 64) + `"`},
+	}
+	for _, c := range cases {
+		db := preconditionDB(t, 11, nil)
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err := NewPreUploadFileLogic(ctx, &svc.ServiceContext{DB: db, ObjectStore: store}).PreUploadFile(&types.PreUploadReq{
+			ProjectId:                1,
+			Name:                     "a.txt",
+			FileCategory:             "text",
+			FileFormat:               "txt",
+			SizeBytes:                3,
+			Hash:                     abcHash,
+			ExpectedCurrentVersionId: c.expected,
+			IfMatch:                  c.ifMatch,
+		})
+		var ce *errorx.CodeError
+		if !errors.As(err, &ce) || ce.Status != http.StatusConflict || ce.Code != ErrVersionConflict.Code {
+			t.Fatalf("%s: expected 409 version_conflict, got %v", c.name, err)
+		}
+		if details, _ := ce.Details.(map[string]any); details["currentVersionId"] != uint64(11) {
+			t.Fatalf("%s: unexpected details %v", c.name, ce.Details)
+		}
+	}
+}
+
+// TestCompleteFileVersionPrecondition 两个客户端基于版本 11 预上传：先完成的成为当前版本（12）后，后完成的在 complete 时冲突
+func TestCompleteFileVersionPrecondition(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := model.BlobStorageKey(abcHash)
+	if _, err := store.PutObject(ctx, key, strings.NewReader("abc")); err != nil {
+		t.Fatal(err)
+	}
+	pending := &model.FileVersions{
+		Id:            13,
+		FileId:        7,
+		VersionNumber: 3,
+		SizeBytes:     3,
+		Hash:          abcHash,
+		StorageKey:    key,
+		Status:        model.FileVersionStatusPending,
+		BaseVersionId: 11,
+	}
+	ctx = context.WithValue(ctx, "userId", json.Number("7"))
+	complete := func(currentVersionId uint64) error {
+		db := preconditionDB(t, currentVersionId, pending)
+		svcCtx := &svc.ServiceContext{DB: db, ObjectStore: store, FileVersionsModel: model.NewFileVersionsModel(db, nil)}
+		_, err := NewCompleteFileVersionLogic(ctx, svcCtx).CompleteFileVersion(&types.CompleteFileVersionReq{Id: 7, VersionId: 13})
+		return err
+	}
+
+	err = complete(12)
+	var ce *errorx.CodeError
+	if !errors.As(err, &ce) || ce.Status != http.StatusConflict || ce.Code != ErrVersionConflict.Code {
+		t.Fatalf("second completion: expected 409 version_conflict, got %v", err)
+	}
+	if details, _ := ce.Details.(map[string]any); details["currentVersionId"] != uint64(12) {
+		t.Fatalf("unexpected details %v", ce.Details)
+	}
+	if err := complete(11); err != nil {
+		t.Fatalf("completion on the base version: %v", err)
+	}
+}
+
+// TestPreUploadUnverifiedBlobUsesStagingKey 相同 hash 的内容尚未校验时，上传 URL 指向本版本独占的临时对象而不是共用对象
+func TestPreUploadUnverifiedBlobUsesStagingKey(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := permissiontest.Open(permissiontest.Options{
+		Role: permission.RoleDeveloper,
+		Tables: map[string]permissiontest.Table{
+			"files": {
+				Columns: []string{"id", "name", "current_version_id"},
+				Rows:    [][]driver.Value{{int64(7), "a.txt", int64(0)}},
+			},
+			"file_blobs": {
+				Columns: []string{"id", "hash", "storage_key", "size_bytes", "ref_count", "verified"},
+				Rows:    [][]driver.Value{{int64(3), abcHash, model.BlobStorageKey(abcHash), int64(3), int64(1), false}},
+			},
+			"file_versions":       {Columns: []string{"max"}, Rows: [][]driver.Value{{int64(0)}}},
+			"project_files":       {Columns: []string{"project_id", "file_id"}, Rows: [][]driver.Value{{int64(1), int64(7)}}},
+			"users":               {Columns: []string{"id"}, Rows: [][]driver.Value{{int64(7)}}},
+			"storage_quotas":      {Columns: []string{"id"}},
+			"file_version_labels": {Columns: []string{"id"}},
+			"file_locks":          {Columns: []string{"id"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+	resp, err := NewPreUploadFileLogic(ctx, &svc.ServiceContext{DB: db, ObjectStore: store}).PreUploadFile(&types.PreUploadReq{
+		ProjectId:    1,
+		Name:         "a.txt",
+		FileCategory: "text",
+		FileFormat:   "txt",
+		SizeBytes:    3,
+		Hash:         abcHash,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.SkipUpload || !strings.Contains(resp.UploadUrl, "staging%2F") {
+		t.Fatalf("expected an upload URL for a staging object, got %+v", resp)
+	}
+}
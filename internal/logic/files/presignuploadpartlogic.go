@@ -0,0 +1,62 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PresignUploadPartLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPresignUploadPartLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PresignUploadPartLogic {
+	return &PresignUploadPartLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PresignUploadPartLogic) PresignUploadPart(req *types.PresignUploadPartReq) (resp *types.PresignUploadPartResp, err error) {
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	upload, err := loadMultipartUpload(l.ctx, l.svcCtx, req.Id, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != model.MultipartStatusUploading {
+		return nil, errors.New("multipart upload is " + upload.Status)
+	}
+	partCount := multipartPartCount(int64(upload.SizeBytes), int64(upload.PartSize))
+	if req.PartNumber <= 0 || req.PartNumber > partCount {
+		return nil, errors.New("partNumber out of range")
+	}
+
+	url, err := l.svcCtx.ObjectStore.PresignUploadPart(
+		l.ctx,
+		upload.StorageKey,
+		upload.UploadId,
+		int(req.PartNumber),
+		time.Duration(l.svcCtx.StorageExpireSeconds())*time.Second,
+	)
+	if err != nil {
+		l.Errorf("[MultipartUpload] Failed to sign part %d of id=%d: %v", req.PartNumber, upload.Id, err)
+		return nil, err
+	}
+
+	return &types.PresignUploadPartResp{
+		UploadUrl:  url,
+		PartNumber: req.PartNumber,
+	}, nil
+}
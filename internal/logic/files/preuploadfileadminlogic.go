@@ -29,3 +29,19 @@ func NewPreUploadFileAdminLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 func (l *PreUploadFileAdminLogic) PreUploadFileAdmin(req *types.PreUploadReq) (resp *types.PreUploadResp, err error) {
 	return NewPreUploadFileLogic(l.ctx, l.svcCtx).PreUploadFile(req)
 }
+
+// PreUploadStagedFileAdmin 内容已由服务端写入临时对象 stagedKey 时创建版本，版本直接引用该对象，不返回上传 URL；
+// 本项目中已有相同内容时 SkipUpload 为 true，临时对象不再被引用，由调用方删除
+func (l *PreUploadFileAdminLogic) PreUploadStagedFileAdmin(req *types.PreUploadReq, stagedKey string) (resp *types.PreUploadResp, err error) {
+	prepared, err := NewPreUploadFileLogic(l.ctx, l.svcCtx).prepareUpload(req, stagedKey)
+	if err != nil {
+		return nil, err
+	}
+	return &types.PreUploadResp{
+		FileId:        int64(prepared.file.Id),
+		VersionId:     int64(prepared.version.Id),
+		VersionNumber: int64(prepared.version.VersionNumber),
+		ContentType:   prepared.contentType,
+		SkipUpload:    prepared.deduplicated,
+	}, nil
+}
@@ -7,39 +7,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// generateStoragePath 生成 OSS 存储路径
-// 格式: 用户ID/项目ID/assets/hash前四位_hash后四位.后缀
-func generateStoragePath(projectId, userId int64, fileCategory, fileHash, fileName string) string {
-	// 取 hash 的前四位和后四位
-	var shortHash string
-	if len(fileHash) >= 8 {
-		shortHash = fileHash[:4] + "_" + fileHash[len(fileHash)-4:]
-	} else {
-		shortHash = fileHash
-	}
-
-	// 清理文件名，获取后缀
-	cleanName := filepath.Base(fileName)
-	ext := filepath.Ext(cleanName)
-
-	// 构建路径: userId/projectId/assets/hash.ext
-	newFileName := shortHash + ext
-	return fmt.Sprintf("%d/%d/assets/%s", userId, projectId, newFileName)
-}
-
 // getContentTypeByFormat 根据文件格式返回对应的 Content-Type
 func getContentTypeByFormat(format string) string {
 	switch strings.ToLower(format) {
@@ -106,7 +86,19 @@ func NewPreUploadFileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Pre
 	}
 }
 
-func (l *PreUploadFileLogic) PreUploadFile(req *types.PreUploadReq) (resp *types.PreUploadResp, err error) {
+// preparedUpload PreUpload 与分片上传共用的准备结果：已插入但尚未上传内容的 pending 版本；
+// deduplicated 为 true 时本项目中已有相同内容，版本直接为 ready 并已设为当前版本
+type preparedUpload struct {
+	userId       int64
+	file         *model.Files
+	version      *model.FileVersions
+	contentType  string
+	deduplicated bool
+}
+
+// prepareUpload 校验身份、参数与项目权限，查找或创建文件并插入新版本记录；
+// stagedKey 非空表示内容已由服务端写入该临时对象，版本直接引用它，无需再上传
+func (l *PreUploadFileLogic) prepareUpload(req *types.PreUploadReq, stagedKey string) (*preparedUpload, error) {
 	adminIdNumber, ok := l.ctx.Value("adminId").(json.Number)
 	isAdmin := ok
 	userIdNumber := adminIdNumber
@@ -121,8 +113,23 @@ func (l *PreUploadFileLogic) PreUploadFile(req *types.PreUploadReq) (resp *types
 	if req == nil {
 		return nil, errors.New("request body is required")
 	}
-	if strings.TrimSpace(req.Name) == "" {
-		return nil, errors.New("name is required")
+	if strings.TrimSpace(req.Name) == "" && strings.TrimSpace(req.Path) == "" {
+		return nil, errors.New("name or path is required")
+	}
+	if req.FolderId < 0 {
+		return nil, model.InputParamInvalid
+	}
+	// path 的最后一级为文件名，其余为目录；只给 name 时文件位于 folderId 指定的目录
+	var folderSegs []string
+	name := strings.TrimSpace(req.Name)
+	if strings.TrimSpace(req.Path) != "" {
+		segs, err := splitPath(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		folderSegs, name = segs[:len(segs)-1], segs[len(segs)-1]
+	} else if err := validateEntryName(name); err != nil {
+		return nil, err
 	}
 	if strings.TrimSpace(req.FileCategory) == "" {
 		return nil, errors.New("fileCategory is required")
@@ -133,95 +140,235 @@ func (l *PreUploadFileLogic) PreUploadFile(req *types.PreUploadReq) (resp *types
 	if strings.TrimSpace(req.Hash) == "" {
 		return nil, errors.New("hash is required")
 	}
+	fileHash := strings.ToLower(strings.TrimSpace(req.Hash))
+	if !model.IsSha256Hex(fileHash) {
+		return nil, errors.New("hash must be a sha256 hex string")
+	}
 	if req.SizeBytes <= 0 {
 		return nil, errors.New("sizeBytes is required")
 	}
-	if req.ProjectId < 0 || (!isAdmin && req.ProjectId <= 0) {
+	if req.ProjectId < 0 || (!isAdmin && req.ProjectId <= 0) || req.ChangesetId < 0 || req.ExpectedCurrentVersionId < 0 {
 		return nil, model.InputParamInvalid
 	}
+	message, err := model.NormalizeVersionMessage(req.Message)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := model.NormalizeVersionLabels(req.Labels)
+	if err != nil {
+		return nil, err
+	}
 
+	// 管理员不受角色限制，但归档项目同样只读
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", req.ProjectId, userId).Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.FileWrite); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
+	} else if err := permission.CheckWritable(l.ctx, l.svcCtx.DB, uint64(req.ProjectId)); err != nil {
+		return nil, err
+	}
+
+	// 上传到变更集时只允许作者向本项目下打开的变更集追加
+	changesetId := uint64(req.ChangesetId)
+	if changesetId > 0 {
+		if isAdmin {
+			return nil, errors.New("changesets are not supported for admin uploads")
+		}
+		changeset, _, err := loadChangeset(l.ctx, l.svcCtx, req.ChangesetId, userId, permission.FileWrite)
+		if err != nil {
+			return nil, err
+		}
+		if changeset.ProjectId != uint64(req.ProjectId) {
+			return nil, errChangesetNotFound
 		}
+		if changeset.CreatedBy != uint64(userId) {
+			return nil, errors.New("permission denied: only the changeset author can upload into it")
+		}
+		if changeset.Status != model.ChangesetStatusOpen {
+			return nil, ErrChangesetNotOpen
+		}
+	}
+
+	// 检查 OSS 是否已配置
+	if l.svcCtx.ObjectStore == nil {
+		l.Errorf("[PreUpload] Object store not configured")
+		return nil, errors.New("object store not configured")
 	}
 
-	// ensure file exists or create
+	// 按目录 + 名称查找文件，不存在时创建（缺失的目录一并创建）
+	projectId := uint64(req.ProjectId)
 	var file *model.Files
-	// try find by name through project_files join
-	err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.Files{}).
-		Joins("JOIN project_files ON project_files.file_id = files.id").
-		Where("project_files.project_id = ? AND files.name = ?", req.ProjectId, req.Name).
-		First(&file).Error
-	if err != nil {
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadFolder(tx, projectId, uint64(req.FolderId)); err != nil {
+			return err
+		}
+		folderId, err := ensureFolderPath(tx, projectId, uint64(req.FolderId), folderSegs, uint64(userId))
+		if err != nil {
+			return err
+		}
+		if err := lockFolderEntries(tx, projectId, folderId); err != nil {
+			return err
+		}
+		var existing model.Files
+		err = tx.Model(&model.Files{}).
+			Joins("JOIN project_files ON project_files.file_id = files.id").
+			Where("project_files.project_id = ? AND files.folder_id = ? AND files.name = ?", projectId, folderId, name).
+			First(&existing).Error
+		if err == nil {
+			file = &existing
+			return nil
+		}
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, err
+			return err
+		}
+		// 客户端期望的是已有文件，说明文件在读取后被删除或移走
+		if req.ExpectedCurrentVersionId > 0 || len(parseIfMatch(req.IfMatch)) > 0 {
+			return versionConflict(nil)
+		}
+		taken, err := folderNameTaken(tx, projectId, folderId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a folder with the same name already exists: " + name)
 		}
-		// create new file
 		newFile := &model.Files{
-			Name:         req.Name,
+			Name:         name,
 			FileCategory: req.FileCategory,
 			FileFormat:   req.FileFormat,
+			FolderId:     folderId,
 		}
-		_, err = l.svcCtx.FilesModel.Insert(l.ctx, newFile)
-		if err != nil {
-			return nil, err
-		}
-		// create project_file relationship
-		projectFile := &model.ProjectFiles{
-			ProjectId: uint64(req.ProjectId),
-			FileId:    newFile.Id,
+		if err := tx.Create(newFile).Error; err != nil {
+			return err
 		}
-		_, err = l.svcCtx.ProjectFilesModel.Insert(l.ctx, projectFile)
-		if err != nil {
-			return nil, err
+		if err := tx.Create(&model.ProjectFiles{ProjectId: projectId, FileId: newFile.Id}).Error; err != nil {
+			return err
 		}
 		file = newFile
-	}
-	// get max version
-	var maxVerNumber int64
-	err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).Where("file_id = ?", file.Id).Select("COALESCE(MAX(version_number),0)").Scan(&maxVerNumber).Error
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	nextVer := maxVerNumber + 1
+	// 版本记录与内容引用在同一事务中创建；内容已存在且校验过时无需再次上传
+	newVer := &model.FileVersions{
+		FileId:      file.Id,
+		SizeBytes:   uint64(req.SizeBytes),
+		Hash:        fileHash,
+		CreatedBy:   uint64(userId),
+		Status:      model.FileVersionStatusPending,
+		Message:     message,
+		ChangesetId: changesetId,
+	}
+	deduplicated := false
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		// 配额在插入版本的事务中校验，先于文件行加锁；管理员上传不受配额限制
+		if !isAdmin {
+			if err := l.svcCtx.CheckStorageQuota(tx, projectId, uint64(userId), req.SizeBytes); err != nil {
+				return err
+			}
+		}
+		// 锁定文件行：同一文件的并发预上传在此串行，版本号计算与前置条件校验不会交错
+		var locked model.Files
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", file.Id).First(&locked).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return versionConflict(nil)
+			}
+			return err
+		}
+		baseVersionId, err := checkUploadPrecondition(tx, &locked, req.ExpectedCurrentVersionId, req.IfMatch)
+		if err != nil {
+			return err
+		}
+		newVer.BaseVersionId = baseVersionId
+		if changesetId > 0 {
+			changeset, err := lockChangeset(tx, changesetId)
+			if err != nil {
+				return err
+			}
+			if changeset.Status != model.ChangesetStatusOpen {
+				return ErrChangesetNotOpen
+			}
+		}
+		// 他人检出的文件不能追加版本；管理员上传不受限制
+		if !isAdmin {
+			if err := checkFileLock(tx, file.Id, userId); err != nil {
+				return err
+			}
+		}
+		// 相同内容已校验过且本项目中已有引用时免上传；其余情况一律上传到本版本独占的临时对象，
+		// complete 校验 hash 后才归入按 hash 寻址的共用对象，未校验的内容不会写到共用对象上
+		existing, err := model.LockFileBlob(tx, fileHash)
+		if err != nil {
+			return err
+		}
+		reuse := false
+		if existing != nil && existing.Verified {
+			if existing.SizeBytes != uint64(req.SizeBytes) {
+				return errors.New("sizeBytes does not match existing content with the same hash")
+			}
+			if reuse, err = model.ProjectUsesStorageKey(tx, projectId, existing.StorageKey); err != nil {
+				return err
+			}
+		}
+		switch {
+		case reuse:
+			blob, err := model.AcquireFileBlob(tx, fileHash, existing.StorageKey, uint64(req.SizeBytes))
+			if err != nil {
+				return err
+			}
+			newVer.StorageKey = blob.StorageKey
+		case stagedKey != "":
+			newVer.StorageKey = stagedKey
+		default:
+			if newVer.StorageKey, err = model.StagingStorageKey(); err != nil {
+				return err
+			}
+		}
 
-	// 生成 OSS 存储路径: 项目ID/用户ID/文件类型/文件名_文件hash(简短).后缀
-	objectPath := generateStoragePath(req.ProjectId, userId, req.FileCategory, req.Hash, req.Name)
-	l.Infof("[PreUpload] ProjectId=%d, UserId=%d, File=%s, GeneratedPath=%s", req.ProjectId, userId, req.Name, objectPath)
+		// 文件行已锁定，MAX+1 不会与并发的预上传得到相同版本号
+		var maxVerNumber int64
+		if err := tx.Model(&model.FileVersions{}).Where("file_id = ?", file.Id).Select("COALESCE(MAX(version_number),0)").Scan(&maxVerNumber).Error; err != nil {
+			return err
+		}
+		newVer.VersionNumber = uint64(maxVerNumber + 1)
 
-	// create version row (without actual upload)
-	newVer := &model.FileVersions{
-		FileId:        file.Id,
-		VersionNumber: uint64(nextVer),
-		SizeBytes:     uint64(req.SizeBytes),
-		Hash:          req.Hash,
-		StorageKey:    objectPath,
-		CreatedBy:     uint64(userId),
-	}
-	_, err = l.svcCtx.FileVersionsModel.Insert(l.ctx, newVer)
+		if reuse {
+			newVer.Status = model.FileVersionStatusReady
+		}
+		if err := tx.Create(newVer).Error; err != nil {
+			return err
+		}
+		if err := model.SetVersionLabels(tx, file.Id, newVer.Id, labels, uint64(userId)); err != nil {
+			return err
+		}
+		if changesetId > 0 {
+			if err := addChangesetFile(tx, changesetId, file.Id, newVer.Id); err != nil {
+				return err
+			}
+		}
+		if !reuse {
+			return nil
+		}
+		deduplicated = true
+		// 变更集中的版本在提交时才成为当前版本
+		if changesetId > 0 {
+			return nil
+		}
+		return tx.Model(&model.Files{}).Where("id = ?", file.Id).Update("current_version_id", newVer.Id).Error
+	})
 	if err != nil {
 		l.Errorf("[PreUpload] Failed to insert version: %v", err)
 		return nil, err
 	}
-	l.Infof("[PreUpload] Version inserted: VersionId=%d, StorageKey=%s", newVer.Id, objectPath)
-
-	// update file current_version_id
-	file.CurrentVersionId = newVer.Id
-	_, err = l.svcCtx.FilesModel.Update(l.ctx, int64(file.Id), file)
-	if err != nil {
-		l.Errorf("[PreUpload] Failed to update file: %v", err)
-		return nil, err
+	if deduplicated && changesetId == 0 {
+		file.CurrentVersionId = newVer.Id
 	}
-	// 检查 OSS 是否已配置
-	if l.svcCtx.ObjectStore == nil {
-		l.Errorf("[PreUpload] Object store not configured")
-		return nil, errors.New("object store not configured")
+	if deduplicated {
+		indexFileContentAsync(l.svcCtx, newVer)
 	}
+	l.Infof("[PreUpload] ProjectId=%d, UserId=%d, File=%s, VersionId=%d, StorageKey=%s, Deduplicated=%v",
+		req.ProjectId, userId, name, newVer.Id, newVer.StorageKey, deduplicated)
 
 	// 确定 Content-Type
 	contentType := req.ContentType
@@ -230,9 +377,39 @@ func (l *PreUploadFileLogic) PreUploadFile(req *types.PreUploadReq) (resp *types
 		contentType = getContentTypeByFormat(req.FileFormat)
 	}
 
+	return &preparedUpload{
+		userId:       userId,
+		file:         file,
+		version:      newVer,
+		contentType:  contentType,
+		deduplicated: deduplicated,
+	}, nil
+}
+
+// PreUploadFile 返回直传 URL，版本在 POST /files/:id/versions/:versionId/complete 校验通过后才成为当前版本；
+// 本项目中已有相同内容时不返回 URL（skipUpload），版本直接生效
+func (l *PreUploadFileLogic) PreUploadFile(req *types.PreUploadReq) (resp *types.PreUploadResp, err error) {
+	prepared, err := l.prepareUpload(req, "")
+	if err != nil {
+		return nil, err
+	}
+	file := prepared.file
+	newVer := prepared.version
+	contentType := prepared.contentType
+
+	if prepared.deduplicated {
+		return &types.PreUploadResp{
+			FileId:        int64(file.Id),
+			VersionId:     int64(newVer.Id),
+			VersionNumber: int64(newVer.VersionNumber),
+			ContentType:   contentType,
+			SkipUpload:    true,
+		}, nil
+	}
+
 	url, err := l.svcCtx.ObjectStore.PresignPutObject(
 		l.ctx,
-		objectPath,
+		newVer.StorageKey,
 		contentType,
 		time.Duration(l.svcCtx.StorageExpireSeconds())*time.Second,
 	)
@@ -0,0 +1,75 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/jobs"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PruneVersionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPruneVersionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PruneVersionsLogic {
+	return &PruneVersionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// PruneVersions 立即按项目的保留策略清理版本并返回报告，dryRun 时只预览将被清理的版本；仅 owner 或 admin 可操作
+func (l *PruneVersionsLogic) PruneVersions(req *types.PruneVersionsReq) (resp *types.PruneVersionsResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.RetentionManage); err != nil {
+		return nil, err
+	}
+
+	report, err := jobs.PruneProjectVersions(l.ctx, l.svcCtx, uint64(req.ProjectId), req.DryRun)
+	if err != nil {
+		l.Errorf("[PruneVersions] Failed to prune project %d: %v", req.ProjectId, err)
+		return nil, err
+	}
+
+	resp = &types.PruneVersionsResp{
+		ProjectId:      int64(report.ProjectId),
+		DryRun:         report.DryRun,
+		ScannedFiles:   int64(report.ScannedFiles),
+		PrunedVersions: int64(report.PrunedVersions),
+		PrunedBytes:    report.PrunedBytes,
+		DeletedObjects: int64(report.DeletedObjects),
+		Files:          make([]types.PrunedFileItem, 0, len(report.Files)),
+	}
+	for _, f := range report.Files {
+		numbers := make([]int64, 0, len(f.VersionNumbers))
+		for _, n := range f.VersionNumbers {
+			numbers = append(numbers, int64(n))
+		}
+		resp.Files = append(resp.Files, types.PrunedFileItem{
+			FileId:         int64(f.FileId),
+			Path:           f.Path,
+			PolicyId:       int64(f.PolicyId),
+			VersionNumbers: numbers,
+			SizeBytes:      f.SizeBytes,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type PurgeTrashFileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPurgeTrashFileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PurgeTrashFileLogic {
+	return &PurgeTrashFileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// PurgeTrashFile 不等保留期结束，立即彻底删除回收站中的文件，仅 owner 或 admin 可操作
+func (l *PurgeTrashFileLogic) PurgeTrashFile(req *types.PurgeTrashFileReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FileId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileDelete); err != nil {
+		return nil, err
+	}
+
+	var releasedKeys []string
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		file, err := loadTrashedFile(tx, projectId, uint64(req.FileId))
+		if err != nil {
+			return err
+		}
+		releasedKeys, err = model.PurgeFiles(tx, []uint64{file.Id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储未配置时对象留给存储清理任务
+	if l.svcCtx.ObjectStore != nil {
+		for _, key := range releasedKeys {
+			if err := l.svcCtx.ObjectStore.DeleteObject(l.ctx, key); err != nil {
+				l.Errorf("[PurgeTrashFile] Failed to delete object %s: %v", key, err)
+			}
+		}
+	}
+
+	l.Infof("[PurgeTrashFile] UserId=%d purged fileId=%d from project %d", userId, req.FileId, projectId)
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
@@ -0,0 +1,82 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReleaseFileLockLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReleaseFileLockLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReleaseFileLockLogic {
+	return &ReleaseFileLockLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ReleaseFileLock 释放自己的锁；owner / admin 可用 force 释放他人的锁。文件未被锁定时直接返回成功
+func (l *ReleaseFileLockLogic) ReleaseFileLock(req *types.ReleaseFileLockReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	file, projectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	role, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.ProjectView)
+	if err != nil {
+		return nil, err
+	}
+	if req.Force {
+		if err := permission.Check(role, permission.FileLockOverride); err != nil {
+			return nil, err
+		}
+	}
+
+	var lock model.FileLocks
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("file_id = ?", file.Id).First(&lock).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if lock.LockedBy != uint64(userId) && lock.Active(time.Now()) && !req.Force {
+			return fileLockedError(&lock)
+		}
+		return tx.Where("id = ?", lock.Id).Delete(&model.FileLocks{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lock.Id > 0 {
+		l.Infof("[FileLock] UserId=%d released lock of fileId=%d held by userId=%d", userId, file.Id, lock.LockedBy)
+	}
+
+	return &types.BaseResp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
@@ -0,0 +1,85 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type RenameFolderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRenameFolderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RenameFolderLogic {
+	return &RenameFolderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RenameFolder 重命名目录，子目录与文件的路径随之改变
+func (l *RenameFolderLogic) RenameFolder(req *types.RenameFolderReq) (resp *types.FolderItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FolderId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := validateEntryName(name); err != nil {
+		return nil, err
+	}
+
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	var folder *model.Folders
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		folder, err = loadFolder(tx, projectId, uint64(req.FolderId))
+		if err != nil {
+			return err
+		}
+		if folder.Name == name {
+			return nil
+		}
+		taken, err := entryNameTaken(tx, projectId, folder.ParentId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a file or folder with the same name already exists")
+		}
+		if err := tx.Model(&model.Folders{}).Where("id = ?", folder.Id).Update("name", name).Error; err != nil {
+			return err
+		}
+		folder.Name = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), projectId)
+	if err != nil {
+		return nil, err
+	}
+	l.Infof("[RenameFolder] UserId=%d, ProjectId=%d, FolderId=%d, Path=%s", userId, projectId, folder.Id, paths[folder.Id])
+	return toFolderItem(folder, paths[folder.Id]), nil
+}
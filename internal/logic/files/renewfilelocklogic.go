@@ -0,0 +1,73 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RenewFileLockLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRenewFileLockLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RenewFileLockLogic {
+	return &RenewFileLockLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RenewFileLock 延长自己持有的锁；锁已过期但尚未被他人检出时同样可以续期
+func (l *RenewFileLockLogic) RenewFileLock(req *types.RenewFileLockReq) (resp *types.FileLockItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.TtlSeconds < 0 {
+		return nil, model.InputParamInvalid
+	}
+	file, projectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	var lock model.FileLocks
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("file_id = ?", file.Id).First(&lock).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("file is not locked")
+			}
+			return err
+		}
+		if lock.LockedBy != uint64(userId) {
+			if lock.Active(time.Now()) {
+				return fileLockedError(&lock)
+			}
+			return errors.New("file is not locked")
+		}
+		lock.ExpiresAt = time.Now().Add(l.svcCtx.FileLockTTL(req.TtlSeconds))
+		return tx.Model(&model.FileLocks{}).Where("id = ?", lock.Id).Update("expires_at", lock.ExpiresAt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fileLockItem(l.svcCtx.DB.WithContext(l.ctx), &lock)
+}
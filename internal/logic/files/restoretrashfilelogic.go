@@ -0,0 +1,124 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type RestoreTrashFileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRestoreTrashFileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RestoreTrashFileLogic {
+	return &RestoreTrashFileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RestoreTrashFile 从回收站恢复文件，全部版本与当前版本保持不变。
+// 原目录仍存在时恢复到原目录，否则按删除时的路径重新创建目录；原位置已有同名条目时需指定新名称
+func (l *RestoreTrashFileLogic) RestoreTrashFile(req *types.RestoreTrashFileReq) (resp *types.ProjectFileItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.FileId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name != "" {
+		if err := validateEntryName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var file *model.Files
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		file, err = loadTrashedFile(tx, projectId, uint64(req.FileId))
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = file.Name
+		}
+
+		folderId := file.FolderId
+		if _, err := loadFolder(tx, projectId, folderId); err != nil {
+			if !errors.Is(err, errFolderNotFound) {
+				return err
+			}
+			// 原目录已删除：按删除时的路径重建
+			folderId = 0
+			if idx := strings.LastIndex(file.DeletedPath, "/"); idx > 0 {
+				segs, err := splitPath(file.DeletedPath[:idx])
+				if err != nil {
+					return err
+				}
+				if folderId, err = ensureFolderPath(tx, projectId, 0, segs, uint64(userId)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := lockFolderEntries(tx, projectId, folderId); err != nil {
+			return err
+		}
+		taken, err := entryNameTaken(tx, projectId, folderId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("a file or folder with the same name already exists, specify a new name to restore")
+		}
+		if err := tx.Unscoped().Model(&model.Files{}).Where("id = ?", file.Id).Updates(map[string]any{
+			"name":         name,
+			"folder_id":    folderId,
+			"deleted_at":   nil,
+			"deleted_by":   0,
+			"deleted_path": "",
+		}).Error; err != nil {
+			return err
+		}
+		file.Name = name
+		file.FolderId = folderId
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var current model.FileVersions
+	if file.CurrentVersionId > 0 {
+		if err := l.svcCtx.DB.WithContext(l.ctx).Where("id = ?", file.CurrentVersionId).First(&current).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	paths, err := model.FolderPaths(l.svcCtx.DB.WithContext(l.ctx), projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[RestoreTrashFile] UserId=%d restored fileId=%d in project %d", userId, file.Id, projectId)
+	return toProjectFileItem(projectId, paths[file.FolderId], file, &current), nil
+}
@@ -0,0 +1,50 @@
+package files
+
+import (
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+var errRetentionPolicyNotFound = errors.New("retention policy not found")
+
+// validateRetention 策略至少要按数量或时间之一保留，避免误删全部历史版本
+func validateRetention(keepLast int64, keepDays int64) error {
+	if keepLast < 0 || keepDays < 0 {
+		return model.InputParamInvalid
+	}
+	if keepLast == 0 && keepDays == 0 {
+		return errors.New("keepLast or keepDays is required")
+	}
+	if keepDays > 36500 {
+		return errors.New("keepDays too large")
+	}
+	return nil
+}
+
+func loadRetentionPolicy(db *gorm.DB, projectId uint64, policyId uint64) (*model.VersionRetentionPolicies, error) {
+	var policy model.VersionRetentionPolicies
+	if err := db.Where("id = ? AND project_id = ?", policyId, projectId).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errRetentionPolicyNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func retentionPolicyItem(p *model.VersionRetentionPolicies) *types.RetentionPolicyItem {
+	return &types.RetentionPolicyItem{
+		Id:          int64(p.Id),
+		ProjectId:   int64(p.ProjectId),
+		PathPattern: p.PathPattern,
+		KeepLast:    int64(p.KeepLast),
+		KeepDays:    int64(p.KeepDays),
+		CreatedBy:   int64(p.CreatedBy),
+		CreatedAt:   p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   p.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
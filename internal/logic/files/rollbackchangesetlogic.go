@@ -0,0 +1,106 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RollbackChangesetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRollbackChangesetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RollbackChangesetLogic {
+	return &RollbackChangesetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RollbackChangeset 整体撤销已提交的变更集：各文件恢复到提交前的当前版本，由变更集新建的文件移入回收站。
+// 提交之后任一文件又有新的当前版本或已被删除时不做任何修改，返回冲突的文件列表
+func (l *RollbackChangesetLogic) RollbackChangeset(req *types.RollbackChangesetReq) (resp *types.ChangesetDetailResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 {
+		return nil, errChangesetNotFound
+	}
+	changeset, _, err := loadChangeset(l.ctx, l.svcCtx, req.Id, userId, permission.FileWrite)
+	if err != nil {
+		return nil, err
+	}
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockChangeset(tx, changeset.Id)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.ChangesetStatusCommitted {
+			return errors.New("only committed changesets can be rolled back")
+		}
+		var entries []model.ChangesetFiles
+		if err := tx.Where("changeset_id = ?", changeset.Id).Order("file_id ASC").Find(&entries).Error; err != nil {
+			return err
+		}
+
+		conflicts := make([]uint64, 0)
+		created := make([]uint64, 0)
+		for _, e := range entries {
+			var file model.Files
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", e.FileId).First(&file).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && file.CurrentVersionId != e.VersionId) {
+				conflicts = append(conflicts, e.FileId)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkFileLock(tx, e.FileId, userId); err != nil {
+				return err
+			}
+			if e.PreviousVersionId == 0 {
+				created = append(created, e.FileId)
+			}
+		}
+		if len(conflicts) > 0 {
+			return ErrChangesetConflict.WithDetails(map[string]any{"fileIds": conflicts})
+		}
+
+		for _, e := range entries {
+			if e.PreviousVersionId == 0 {
+				continue
+			}
+			if err := tx.Model(&model.Files{}).Where("id = ?", e.FileId).
+				Update("current_version_id", e.PreviousVersionId).Error; err != nil {
+				return err
+			}
+		}
+		if err := trashFilesTx(tx, changeset.ProjectId, created, uint64(userId)); err != nil {
+			return err
+		}
+
+		changeset.Status = model.ChangesetStatusRolledBack
+		return tx.Model(&model.Changesets{}).Where("id = ?", changeset.Id).Update("status", changeset.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[Changeset] UserId=%d rolled back changeset %d", userId, changeset.Id)
+	return changesetDetail(l.svcCtx.DB.WithContext(l.ctx), changeset)
+}
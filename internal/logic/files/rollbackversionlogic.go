@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -55,20 +56,13 @@ func (l *RollbackVersionLogic) RollbackVersion(req *types.RollbackVersionReq) (r
 		return nil, err
 	}
 
-	// 检查项目成员权限（需要 developer 及以上角色）
-	var member model.ProjectMembers
-	if err := l.svcCtx.DB.WithContext(l.ctx).
-		Where("project_id = ? AND user_id = ?", projectFile.ProjectId, userId).
-		First(&member).Error; err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			return nil, errors.New("project not found or permission denied")
-		}
+	// 回滚需要 developer 及以上角色
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectFile.ProjectId, userId, permission.FileWrite); err != nil {
 		return nil, err
 	}
 
-	// 检查权限：viewer 不能回滚版本
-	if member.Role == "viewer" {
-		return nil, errors.New("permission denied: viewer cannot rollback versions")
+	if err := checkFileLock(l.svcCtx.DB.WithContext(l.ctx), file.Id, userId); err != nil {
+		return nil, err
 	}
 
 	// 查找目标版本
@@ -81,6 +75,21 @@ func (l *RollbackVersionLogic) RollbackVersion(req *types.RollbackVersionReq) (r
 		return nil, err
 	}
 
+	// 只能回滚到已完成上传校验的版本
+	if targetVersion.Status != model.FileVersionStatusReady {
+		return nil, errors.New("target version is not ready")
+	}
+	// 未提交变更集中的版本不能单独生效
+	if targetVersion.ChangesetId > 0 {
+		var changeset model.Changesets
+		if err := l.svcCtx.DB.WithContext(l.ctx).Where("id = ?", targetVersion.ChangesetId).First(&changeset).Error; err != nil {
+			return nil, err
+		}
+		if changeset.Status == model.ChangesetStatusOpen || changeset.Status == model.ChangesetStatusAborted {
+			return nil, errors.New("target version belongs to an uncommitted changeset")
+		}
+	}
+
 	// 检查目标版本是否已经是当前版本
 	if file.CurrentVersionId == targetVersion.Id {
 		return nil, errors.New("target version is already the current version")
@@ -0,0 +1,142 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	contentIndexTimeout = time.Minute
+	// minSearchQueryLength 与 MySQL ngram_token_size 默认值一致，更短的词无法命中全文索引
+	minSearchQueryLength = 2
+	maxSearchMatches     = 5
+	maxSearchLineLength  = 200
+)
+
+var errSearchQueryTooShort = errors.New("search query must be at least 2 characters")
+
+// indexFileContentAsync 文本版本校验通过后在后台写入全文索引，失败只记录日志，该版本只能按元数据搜索
+func indexFileContentAsync(svcCtx *svc.ServiceContext, version *model.FileVersions) {
+	if svcCtx.ObjectStore == nil || int64(version.SizeBytes) > svcCtx.SearchMaxIndexBytes() {
+		return
+	}
+	versionId := version.Id
+	fileId := version.FileId
+	storageKey := version.StorageKey
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), contentIndexTimeout)
+		defer cancel()
+		db := svcCtx.DB.WithContext(ctx)
+		var file model.Files
+		if err := db.Select("id", "file_category").Where("id = ?", fileId).First(&file).Error; err != nil {
+			return
+		}
+		if file.FileCategory != "text" {
+			return
+		}
+		reader, err := svcCtx.ObjectStore.GetObject(ctx, storageKey)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("[Search] read %s failed: %v", storageKey, err)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(reader, svcCtx.SearchMaxIndexBytes()+1))
+		_ = reader.Close()
+		if err != nil || int64(len(data)) > svcCtx.SearchMaxIndexBytes() {
+			return
+		}
+		content, ok := model.IndexableText(data)
+		if !ok {
+			return
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.FileVersionContents{
+			VersionId: versionId,
+			FileId:    fileId,
+			Content:   content,
+		}).Error; err != nil {
+			logx.WithContext(ctx).Errorf("[Search] index version %d failed: %v", versionId, err)
+		}
+	})
+}
+
+// normalizeSearchQuery 去掉引号后作为短语在 BOOLEAN MODE 中匹配，符号、下划线等不会被当作运算符
+func normalizeSearchQuery(q string) (string, error) {
+	q = strings.TrimSpace(strings.ReplaceAll(q, `"`, " "))
+	if q == "" {
+		return "", nil
+	}
+	if utf8.RuneCountInString(q) < minSearchQueryLength {
+		return "", errSearchQueryTooShort
+	}
+	return q, nil
+}
+
+// globToLike 将文件名通配符（* 与 ?）转换为 LIKE 模式，转义 LIKE 自身的通配符
+func globToLike(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseSearchTime 支持 2006-01-02 15:04:05 与 2006-01-02 两种格式；endOfDay 时只有日期的上界取当天结束
+func parseSearchTime(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("invalid time: " + value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
+
+// matchLines 返回内容中包含 q 的行（不区分大小写），最多 maxSearchMatches 行，过长的行截断
+func matchLines(content string, q string) []types.FileSearchMatch {
+	matches := make([]types.FileSearchMatch, 0)
+	needle := strings.ToLower(q)
+	for i, line := range strings.Split(content, "\n") {
+		if !strings.Contains(strings.ToLower(line), needle) {
+			continue
+		}
+		line = strings.TrimRight(line, "\r")
+		if len(line) > maxSearchLineLength {
+			cut := maxSearchLineLength
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			line = line[:cut]
+		}
+		matches = append(matches, types.FileSearchMatch{Line: int64(i + 1), Text: line})
+		if len(matches) >= maxSearchMatches {
+			break
+		}
+	}
+	return matches
+}
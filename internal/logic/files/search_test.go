@@ -0,0 +1,41 @@
+package files
+
+import "testing"
+
+func TestGlobToLike(t *testing.T) {
+	cases := map[string]string{
+		"*.go":       "%.go",
+		"main?.ts":   "main_.ts",
+		"100%_done*": `100\%\_done%`,
+		`dir\name`:   `dir\\name`,
+		"plain.txt":  "plain.txt",
+	}
+	for glob, want := range cases {
+		if got := globToLike(glob); got != want {
+			t.Fatalf("globToLike(%q) = %q, want %q", glob, got, want)
+		}
+	}
+}
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	if q, err := normalizeSearchQuery(`  "renderFrame" `); err != nil || q != "renderFrame" {
+		t.Fatalf("unexpected result %q, %v", q, err)
+	}
+	if q, err := normalizeSearchQuery("   "); err != nil || q != "" {
+		t.Fatalf("blank query should be ignored, got %q, %v", q, err)
+	}
+	if _, err := normalizeSearchQuery("x"); err != errSearchQueryTooShort {
+		t.Fatalf("expected errSearchQueryTooShort, got %v", err)
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	content := "package main\r\nfunc RenderFrame() {}\n// renderframe helper\nother"
+	matches := matchLines(content, "renderFrame")
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Line != 2 || matches[0].Text != "func RenderFrame() {}" || matches[1].Line != 3 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
@@ -0,0 +1,164 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SearchProjectFilesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSearchProjectFilesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchProjectFilesLogic {
+	return &SearchProjectFilesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SearchProjectFiles 按名称、类型、大小、更新时间与上传者过滤项目文件，条件都作用于文件的当前版本，按更新时间倒序；
+// q 不为空时只返回当前版本内容包含 q 的文本文件，并附带命中的行
+func (l *SearchProjectFilesLogic) SearchProjectFiles(req *types.SearchProjectFilesReq) (resp *types.FileSearchResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.MinSize < 0 || req.MaxSize < 0 {
+		return nil, model.InputParamInvalid
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+	q, err := normalizeSearchQuery(req.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	page := int(req.Page)
+	size := int(req.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	query := db.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Joins("JOIN file_versions cv ON cv.id = files.current_version_id").
+		Where("project_files.project_id = ? AND cv.status = ?", req.ProjectId, model.FileVersionStatusReady)
+	if name := strings.TrimSpace(req.Name); name != "" {
+		query = query.Where("files.name LIKE ?", globToLike(name))
+	}
+	if category := strings.TrimSpace(req.Category); category != "" {
+		query = query.Where("files.file_category = ?", category)
+	}
+	if format := strings.TrimSpace(req.Format); format != "" {
+		query = query.Where("files.file_format = ?", strings.ToLower(format))
+	}
+	if req.MinSize > 0 {
+		query = query.Where("cv.size_bytes >= ?", req.MinSize)
+	}
+	if req.MaxSize > 0 {
+		query = query.Where("cv.size_bytes <= ?", req.MaxSize)
+	}
+	if req.UpdatedFrom != "" {
+		from, err := parseSearchTime(req.UpdatedFrom, false)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("cv.created_at >= ?", from)
+	}
+	if req.UpdatedTo != "" {
+		to, err := parseSearchTime(req.UpdatedTo, true)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("cv.created_at <= ?", to)
+	}
+	if req.CreatedBy > 0 {
+		query = query.Where("cv.created_by = ?", req.CreatedBy)
+	}
+	if q != "" {
+		query = query.Joins("JOIN file_version_contents fc ON fc.version_id = files.current_version_id").
+			Where("MATCH(fc.content) AGAINST (? IN BOOLEAN MODE)", `"`+q+`"`)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var files []model.Files
+	if err := query.Select("files.*").Order("cv.created_at DESC, files.id DESC").
+		Offset((page - 1) * size).Limit(size).Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	versions, err := currentVersions(db, files)
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[uint64]string)
+	if q != "" && len(files) > 0 {
+		versionIds := make([]uint64, 0, len(files))
+		for _, f := range files {
+			versionIds = append(versionIds, f.CurrentVersionId)
+		}
+		var rows []model.FileVersionContents
+		if err := db.Where("version_id IN ?", versionIds).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			contents[r.VersionId] = r.Content
+		}
+	}
+	paths, err := model.FolderPaths(db, uint64(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]types.FileSearchItem, 0, len(files))
+	for i := range files {
+		f := &files[i]
+		version, ok := versions[f.Id]
+		if !ok {
+			continue
+		}
+		item := types.FileSearchItem{
+			File:      *toProjectFileItem(uint64(req.ProjectId), paths[f.FolderId], f, version),
+			UpdatedAt: version.CreatedAt.Format("2006-01-02 15:04:05"),
+			CreatedBy: int64(version.CreatedBy),
+			Matches:   make([]types.FileSearchMatch, 0),
+		}
+		if q != "" {
+			item.Matches = matchLines(contents[f.CurrentVersionId], q)
+		}
+		items = append(items, item)
+	}
+	return &types.FileSearchResp{
+		List: items,
+		Page: types.PageResp{
+			Page:     int64(page),
+			PageSize: int64(size),
+			Total:    total,
+		},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type SetVersionLabelsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetVersionLabelsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetVersionLabelsLogic {
+	return &SetVersionLabelsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetVersionLabels 以请求中的标签整体替换版本标签，空列表表示清除；viewer 不可修改
+func (l *SetVersionLabelsLogic) SetVersionLabels(req *types.SetVersionLabelsReq) (resp *types.FileVersionItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Id <= 0 || req.VersionId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	labels, err := model.NormalizeVersionLabels(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	file, projectId, err := loadProjectFile(l.ctx, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, projectId, userId, permission.FileWrite); err != nil {
+		return nil, err
+	}
+
+	var version model.FileVersions
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND file_id = ?", req.VersionId, file.Id).First(&version).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("file version not found")
+			}
+			return err
+		}
+		return model.SetVersionLabels(tx, file.Id, version.Id, labels, uint64(userId))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[SetVersionLabels] UserId=%d fileId=%d versionId=%d labels=%v", userId, file.Id, version.Id, labels)
+	return fileVersionItem(l.svcCtx.DB.WithContext(l.ctx), &version)
+}
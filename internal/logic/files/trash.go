@@ -0,0 +1,84 @@
+package files
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+var errTrashFileNotFound = errors.New("file not found in trash")
+
+// loadTrashedFile 查找项目回收站中的文件
+func loadTrashedFile(db *gorm.DB, projectId uint64, fileId uint64) (*model.Files, error) {
+	var file model.Files
+	err := db.Unscoped().
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("files.id = ? AND project_files.project_id = ? AND files.deleted_at IS NOT NULL", fileId, projectId).
+		First(&file).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errTrashFileNotFound
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// trashItems 转换回收站文件，批量补充版本统计与删除人用户名
+func trashItems(db *gorm.DB, files []model.Files, retention time.Duration) ([]types.TrashItem, error) {
+	fileIds := make([]uint64, 0, len(files))
+	userIds := make([]uint64, 0, len(files))
+	for _, f := range files {
+		fileIds = append(fileIds, f.Id)
+		userIds = append(userIds, f.DeletedBy)
+	}
+	type versionStat struct {
+		FileId       uint64
+		VersionCount int64
+		SizeBytes    int64
+	}
+	stats := make(map[uint64]versionStat, len(fileIds))
+	names := make(map[uint64]string, len(userIds))
+	if len(fileIds) > 0 {
+		var rows []versionStat
+		if err := db.Model(&model.FileVersions{}).
+			Select("file_id, COUNT(*) AS version_count, COALESCE(SUM(size_bytes),0) AS size_bytes").
+			Where("file_id IN ? AND status = ?", fileIds, model.FileVersionStatusReady).
+			Group("file_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			stats[r.FileId] = r
+		}
+		var users []model.Users
+		if err := db.Select("id", "username").Where("id IN ?", userIds).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names[u.Id] = u.Username
+		}
+	}
+	items := make([]types.TrashItem, 0, len(files))
+	for _, f := range files {
+		deletedAt := f.DeletedAt.Time
+		items = append(items, types.TrashItem{
+			FileId:        int64(f.Id),
+			Name:          f.Name,
+			Path:          f.DeletedPath,
+			FileCategory:  f.FileCategory,
+			FileFormat:    f.FileFormat,
+			VersionCount:  stats[f.Id].VersionCount,
+			SizeBytes:     stats[f.Id].SizeBytes,
+			DeletedBy:     int64(f.DeletedBy),
+			DeletedByName: names[f.DeletedBy],
+			DeletedAt:     deletedAt.Format("2006-01-02 15:04:05"),
+			PurgeAt:       deletedAt.Add(retention).Format("2006-01-02 15:04:05"),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateRetentionPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateRetentionPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateRetentionPolicyLogic {
+	return &UpdateRetentionPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateRetentionPolicy 修改保留数量与天数，路径模式不可修改；仅 owner 或 admin 可操作
+func (l *UpdateRetentionPolicyLogic) UpdateRetentionPolicy(req *types.UpdateRetentionPolicyReq) (resp *types.RetentionPolicyItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.PolicyId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if err := validateRetention(req.KeepLast, req.KeepDays); err != nil {
+		return nil, err
+	}
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.RetentionManage); err != nil {
+		return nil, err
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	policy, err := loadRetentionPolicy(db, uint64(req.ProjectId), uint64(req.PolicyId))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Model(policy).Updates(map[string]any{
+		"keep_last": req.KeepLast,
+		"keep_days": req.KeepDays,
+	}).Error; err != nil {
+		return nil, err
+	}
+	if policy, err = loadRetentionPolicy(db, uint64(req.ProjectId), uint64(req.PolicyId)); err != nil {
+		return nil, err
+	}
+	return retentionPolicyItem(policy), nil
+}
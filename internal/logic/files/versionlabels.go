@@ -0,0 +1,102 @@
+package files
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"gorm.io/gorm"
+)
+
+// fileVersionItems 转换版本记录，并批量补充标签与上传者用户名
+func fileVersionItems(db *gorm.DB, versions []model.FileVersions) ([]types.FileVersionItem, error) {
+	versionIds := make([]uint64, 0, len(versions))
+	userIds := make([]uint64, 0, len(versions))
+	for _, v := range versions {
+		versionIds = append(versionIds, v.Id)
+		userIds = append(userIds, v.CreatedBy)
+	}
+	labels, err := model.VersionLabels(db, versionIds)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint64]string, len(userIds))
+	if len(userIds) > 0 {
+		var users []model.Users
+		if err := db.Select("id", "username").Where("id IN ?", userIds).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names[u.Id] = u.Username
+		}
+	}
+	items := make([]types.FileVersionItem, 0, len(versions))
+	for _, v := range versions {
+		versionLabels := labels[v.Id]
+		if versionLabels == nil {
+			versionLabels = []string{}
+		}
+		items = append(items, types.FileVersionItem{
+			Id:            int64(v.Id),
+			FileId:        int64(v.FileId),
+			VersionNumber: int64(v.VersionNumber),
+			SizeBytes:     int64(v.SizeBytes),
+			Hash:          v.Hash,
+			StorageKey:    v.StorageKey,
+			Status:        v.Status,
+			CreatedAt:     v.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:     v.UpdatedAt.Format("2006-01-02 15:04:05"),
+			CreatedBy:     int64(v.CreatedBy),
+			CreatedByName: names[v.CreatedBy],
+			Message:       v.Message,
+			Labels:        versionLabels,
+			ChangesetId:   int64(v.ChangesetId),
+		})
+	}
+	return items, nil
+}
+
+// fileVersionItem 单个版本的 fileVersionItems
+func fileVersionItem(db *gorm.DB, version *model.FileVersions) (*types.FileVersionItem, error) {
+	items, err := fileVersionItems(db, []model.FileVersions{*version})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// resolveVersionId 按 versionNumber / label / versionId 的优先级选择版本，均未指定时取当前版本；
+// label 解析为带该标签、版本号最大的已完成版本
+func resolveVersionId(db *gorm.DB, file *model.Files, versionId int64, versionNumber int64, label string) (uint64, error) {
+	if versionNumber > 0 {
+		var v model.FileVersions
+		if err := db.Where("file_id = ? AND version_number = ?", file.Id, versionNumber).First(&v).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, errors.New("file version not found")
+			}
+			return 0, err
+		}
+		return v.Id, nil
+	}
+	if label = strings.TrimSpace(label); label != "" {
+		var v model.FileVersions
+		err := db.Model(&model.FileVersions{}).
+			Joins("JOIN file_version_labels ON file_version_labels.version_id = file_versions.id").
+			Where("file_version_labels.file_id = ? AND file_version_labels.label = ? AND file_versions.status = ?", file.Id, label, model.FileVersionStatusReady).
+			Order("file_versions.version_number DESC").
+			First(&v).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, errors.New("no version with label: " + label)
+			}
+			return 0, err
+		}
+		return v.Id, nil
+	}
+	if versionId > 0 {
+		return uint64(versionId), nil
+	}
+	return file.CurrentVersionId, nil
+}
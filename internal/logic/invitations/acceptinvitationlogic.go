@@ -0,0 +1,81 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package invitations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type AcceptInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAcceptInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AcceptInvitationLogic {
+	return &AcceptInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AcceptInvitationLogic) AcceptInvitation(req *types.InvitationTokenReq) (resp *types.AcceptInvitationResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Token == "" {
+		return nil, model.InputParamInvalid
+	}
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInvitation(l.ctx, l.svcCtx, tx, req.Token, userId)
+		if err != nil {
+			return err
+		}
+		resp = &types.AcceptInvitationResp{ProjectId: int64(inv.ProjectId), Role: inv.Role}
+
+		// 已经是成员时保留现有角色，只把邀请标为已接受
+		var member model.ProjectMembers
+		if err := tx.Where("project_id = ? AND user_id = ?", inv.ProjectId, userId).Limit(1).Find(&member).Error; err != nil {
+			return err
+		}
+		if member.Id > 0 {
+			resp.Role = member.Role
+		} else if err := tx.Create(&model.ProjectMembers{
+			ProjectId: inv.ProjectId,
+			UserId:    uint64(userId),
+			Role:      inv.Role,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ProjectInvitations{}).Where("id = ?", inv.Id).Updates(map[string]any{
+			"status":       model.InvitationStatusAccepted,
+			"invitee_id":   inv.InviteeId,
+			"responded_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[AcceptInvitation] UserId=%d joined project %d as %s", userId, resp.ProjectId, resp.Role)
+	return resp, nil
+}
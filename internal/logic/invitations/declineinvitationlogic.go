@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package invitations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type DeclineInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeclineInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeclineInvitationLogic {
+	return &DeclineInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeclineInvitationLogic) DeclineInvitation(req *types.InvitationTokenReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.Token == "" {
+		return nil, model.InputParamInvalid
+	}
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInvitation(l.ctx, l.svcCtx, tx, req.Token, userId)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.ProjectInvitations{}).Where("id = ?", inv.Id).Updates(map[string]any{
+			"status":       model.InvitationStatusDeclined,
+			"invitee_id":   inv.InviteeId,
+			"responded_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[DeclineInvitation] UserId=%d declined an invitation", userId)
+	return &types.BaseResp{Code: 0, Msg: "ok"}, nil
+}
@@ -0,0 +1,55 @@
+package invitations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvitationInvalid    = errorx.New(http.StatusNotFound, "invitation_invalid", "invitation is invalid or has expired")
+	ErrInvitationNotForUser = errorx.New(http.StatusForbidden, "invitation_not_for_user", "invitation is addressed to another user")
+)
+
+// lockInvitation 校验令牌并锁定对应的待处理邀请；邀请须发给当前用户，
+// 尚未关联用户的邀请按邮箱匹配后关联
+func lockInvitation(ctx context.Context, svcCtx *svc.ServiceContext, tx *gorm.DB, token string, userId int64) (*model.ProjectInvitations, error) {
+	now := time.Now()
+	id, err := model.ParseInvitationToken(svcCtx.InvitationSecret(), token, now)
+	if err != nil {
+		return nil, ErrInvitationInvalid
+	}
+	var inv model.ProjectInvitations
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, err
+	}
+	if inv.Token != token || inv.Status != model.InvitationStatusPending || !now.Before(inv.ExpiresAt) {
+		return nil, ErrInvitationInvalid
+	}
+	if inv.InviteeId == uint64(userId) {
+		return &inv, nil
+	}
+	if inv.InviteeId != 0 {
+		return nil, ErrInvitationNotForUser
+	}
+	user, err := svcCtx.UsersModel.FindOne(ctx, uint64(userId))
+	if err != nil {
+		return nil, err
+	}
+	if model.NormalizeEmail(user.Email) != inv.Email {
+		return nil, ErrInvitationNotForUser
+	}
+	inv.InviteeId = user.Id
+	return &inv, nil
+}
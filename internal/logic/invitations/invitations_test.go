@@ -0,0 +1,29 @@
+package invitations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+)
+
+func TestLockInvitationRejectsBadToken(t *testing.T) {
+	svcCtx := &svc.ServiceContext{}
+	svcCtx.Config.Invitation.Secret = "secret"
+
+	expired, err := model.SignInvitationToken("secret", 1, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := model.SignInvitationToken("other", 1, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{"garbage", expired, forged} {
+		if _, err := lockInvitation(context.Background(), svcCtx, nil, token, 7); err != ErrInvitationInvalid {
+			t.Errorf("%q: got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package invitations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListInvitationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListInvitationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListInvitationsLogic {
+	return &ListInvitationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListInvitationsLogic) ListInvitations() (resp *types.ListInvitationsResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	// 登录之后才注册到该邮箱的邀请（或修改了邮箱）在这里补充关联
+	user, err := l.svcCtx.UsersModel.FindOne(l.ctx, uint64(userId))
+	if err != nil {
+		return nil, err
+	}
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	if _, err := model.AttachInvitations(db, user.Id, user.Email); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		model.ProjectInvitations
+		ProjectName string `gorm:"column:project_name"`
+	}
+	if err := db.Table("project_invitations").
+		Select("project_invitations.*, projects.name AS project_name").
+		Joins("JOIN projects ON projects.id = project_invitations.project_id").
+		Where("project_invitations.invitee_id = ? AND project_invitations.status = ? AND project_invitations.expires_at > ?",
+			user.Id, model.InvitationStatusPending, time.Now()).
+		Order("project_invitations.id DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	resp = &types.ListInvitationsResp{List: make([]types.InvitationItem, 0, len(rows))}
+	for _, r := range rows {
+		resp.List = append(resp.List, types.InvitationItem{
+			Id:          int64(r.Id),
+			ProjectId:   int64(r.ProjectId),
+			ProjectName: r.ProjectName,
+			Email:       r.Email,
+			Role:        r.Role,
+			Status:      r.Status,
+			InvitedBy:   int64(r.InvitedBy),
+			Token:       r.Token,
+			ExpiresAt:   r.ExpiresAt.Format("2006-01-02 15:04:05"),
+			CreatedAt:   r.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return resp, nil
+}
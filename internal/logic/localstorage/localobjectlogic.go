@@ -0,0 +1,125 @@
+package localstorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LocalObjectLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLocalObjectLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LocalObjectLogic {
+	return &LocalObjectLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *LocalObjectLogic) localStore() (*storage.LocalStore, error) {
+	localStore, ok := l.svcCtx.ObjectStore.(*storage.LocalStore)
+	if !ok || localStore == nil {
+		return nil, errors.New("local storage not enabled")
+	}
+	return localStore, nil
+}
+
+// OpenObject 校验 GET 签名后打开对象
+func (l *LocalObjectLogic) OpenObject(req *types.LocalObjectReq) (io.ReadCloser, string, int64, error) {
+	if req == nil || strings.TrimSpace(req.Key) == "" {
+		return nil, "", 0, model.InputParamInvalid
+	}
+	localStore, err := l.localStore()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if err := localStore.VerifySignature("GET", req.Key, req.Expires, "", req.Signature); err != nil {
+		return nil, "", 0, err
+	}
+
+	stat, err := localStore.StatObject(l.ctx, req.Key)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	reader, err := localStore.GetObject(l.ctx, req.Key)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	contentType := strings.TrimSpace(stat.ContentType)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return reader, contentType, stat.SizeBytes, nil
+}
+
+// PutObject 校验 PUT 签名后写入对象，签名时指定了 Content-Type 的必须与请求一致
+func (l *LocalObjectLogic) PutObject(req *types.LocalObjectReq, contentType string, body io.Reader) (int64, error) {
+	if req == nil || strings.TrimSpace(req.Key) == "" {
+		return 0, model.InputParamInvalid
+	}
+	localStore, err := l.localStore()
+	if err != nil {
+		return 0, err
+	}
+	if err := localStore.VerifySignature("PUT", req.Key, req.Expires, req.ContentType, req.Signature); err != nil {
+		return 0, err
+	}
+
+	signedType := strings.TrimSpace(req.ContentType)
+	if signedType != "" && !sameMediaType(signedType, contentType) {
+		return 0, storage.ErrLocalSignatureInvalid
+	}
+
+	n, err := localStore.PutObject(l.ctx, req.Key, body)
+	if err != nil {
+		l.Errorf("[LocalObject] Failed to write object %s: %v", req.Key, err)
+		return 0, err
+	}
+	l.Infof("[LocalObject] Stored object %s, size=%d, ext=%s", req.Key, n, path.Ext(req.Key))
+	return n, nil
+}
+
+// PutPart 校验分片签名后写入分片
+func (l *LocalObjectLogic) PutPart(req *types.LocalObjectReq, body io.Reader) (*storage.UploadedPart, error) {
+	if req == nil || strings.TrimSpace(req.Key) == "" || strings.TrimSpace(req.UploadId) == "" || req.PartNumber <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	localStore, err := l.localStore()
+	if err != nil {
+		return nil, err
+	}
+	if err := localStore.VerifyPartSignature(req.Key, req.UploadId, req.PartNumber, req.Expires, req.Signature); err != nil {
+		return nil, err
+	}
+
+	part, err := localStore.PutPart(l.ctx, req.Key, req.UploadId, req.PartNumber, body)
+	if err != nil {
+		l.Errorf("[LocalObject] Failed to write part %d of %s: %v", req.PartNumber, req.Key, err)
+		return nil, err
+	}
+	return part, nil
+}
+
+func sameMediaType(a string, b string) bool {
+	ma, _, errA := mime.ParseMediaType(a)
+	mb, _, errB := mime.ParseMediaType(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	}
+	return strings.EqualFold(ma, mb)
+}
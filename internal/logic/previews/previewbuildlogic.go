@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/storage"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PreviewBuildLogic struct {
@@ -81,15 +85,9 @@ func (l *PreviewBuildLogic) requireBuildVersionAccess(buildVersionId int64) (*mo
 		return nil, err
 	}
 
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-		Where("project_id = ? AND user_id = ?", bv.ProjectId, userId).
-		Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, bv.ProjectId, userId, permission.ProjectView); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 	return &bv, nil
 }
 
@@ -408,40 +406,44 @@ func (l *PreviewBuildLogic) ResolveAssetStorageKey(buildVersionId int64, request
 	return storageKey, nil
 }
 
-func (l *PreviewBuildLogic) OpenAssetObject(buildVersionId int64, requestedPath string) (io.ReadCloser, string, int64, error) {
+// OpenAssetObject 解析预览资源对应的对象并返回输出所需的元信息；
+// 内容寻址的对象用 key 中的 sha256 作为 ETag，其它对象使用存储返回的 ETag
+func (l *PreviewBuildLogic) OpenAssetObject(buildVersionId int64, requestedPath string) (*storage.ObjectContent, error) {
 	if l.svcCtx.ObjectStore == nil {
-		return nil, "", 0, errors.New("object store not configured")
+		return nil, errors.New("object store not configured")
 	}
 
 	reqPath := normalizeBuildPath(requestedPath)
 	if reqPath == "" {
-		return nil, "", 0, model.InputParamInvalid
+		return nil, model.InputParamInvalid
 	}
 
 	storageKey, err := l.ResolveAssetStorageKey(buildVersionId, reqPath)
 	if err != nil {
-		return nil, "", 0, err
+		return nil, err
 	}
 
-	var contentType string
-	var contentLength int64
-
-	if stat, err := l.svcCtx.ObjectStore.StatObject(l.ctx, storageKey); err == nil && stat != nil {
-		contentType = strings.TrimSpace(stat.ContentType)
-		contentLength = stat.SizeBytes
+	stat, err := l.svcCtx.ObjectStore.StatObject(l.ctx, storageKey)
+	if err != nil {
+		return nil, model.ErrNotFound
+	}
+	content := &storage.ObjectContent{
+		Key:          storageKey,
+		ContentType:  strings.TrimSpace(stat.ContentType),
+		SizeBytes:    stat.SizeBytes,
+		ETag:         stat.ETag,
+		LastModified: stat.LastModified,
+	}
+	if strings.HasPrefix(storageKey, "blobs/sha256/") {
+		content.ETag = `"` + path.Base(storageKey) + `"`
 	}
 
-	if contentType == "" {
+	if content.ContentType == "" {
 		if idx := strings.LastIndex(reqPath, "."); idx >= 0 && idx+1 < len(reqPath) {
-			contentType = getContentTypeByFormat(reqPath[idx+1:])
+			content.ContentType = getContentTypeByFormat(reqPath[idx+1:])
 		}
 	}
-
-	reader, err := l.svcCtx.ObjectStore.GetObject(l.ctx, storageKey)
-	if err != nil {
-		return nil, "", 0, err
-	}
-	return reader, contentType, contentLength, nil
+	return content, nil
 }
 
 func (l *PreviewBuildLogic) ensurePreviewStoragePrefix(bv *model.BuildVersions) (string, error) {
@@ -497,6 +499,35 @@ func (l *PreviewBuildLogic) PreuploadBuildFile(buildVersionId int64, relativePat
 	if l.svcCtx.ObjectStore == nil {
 		return "", "", "", errors.New("object store not configured")
 	}
+
+	// 同一路径重复上传时只按大小差值计入配额
+	userIdNumber, _ := l.ctx.Value("userId").(json.Number)
+	userId, _ := userIdNumber.Int64()
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.PreviewUploads
+		var existingBytes int64
+		if err := tx.Where("storage_key = ?", storageKey).First(&existing).Error; err == nil {
+			existingBytes = int64(existing.SizeBytes)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := l.svcCtx.CheckStorageQuota(tx, bv.ProjectId, uint64(userId), sizeBytes-existingBytes); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "storage_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"size_bytes", "created_by", "updated_at"}),
+		}).Create(&model.PreviewUploads{
+			ProjectId:      bv.ProjectId,
+			BuildVersionId: bv.Id,
+			StorageKey:     storageKey,
+			SizeBytes:      uint64(sizeBytes),
+			CreatedBy:      uint64(userId),
+		}).Error
+	})
+	if err != nil {
+		return "", "", "", err
+	}
 	uploadUrl, err := l.svcCtx.ObjectStore.PresignPutObject(
 		l.ctx,
 		storageKey,
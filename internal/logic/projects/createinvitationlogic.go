@@ -0,0 +1,121 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CreateInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateInvitationLogic {
+	return &CreateInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateInvitationLogic) CreateInvitation(req *types.CreateInvitationReq) (resp *types.InvitationItem, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	email, err := validateInvitationEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if !permission.ValidRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+	projectId := uint64(req.ProjectId)
+
+	var inv model.ProjectInvitations
+	var projectName string
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := permission.Authorize(l.ctx, tx, projectId, userId, permission.MemberManage)
+		if err != nil {
+			return err
+		}
+		if req.Role == permission.RoleOwner {
+			if err := permission.Check(role, permission.OwnerGrant); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.Projects{}).Select("name").Where("id = ?", projectId).Scan(&projectName).Error; err != nil {
+			return err
+		}
+
+		// 邮箱已注册时直接关联用户，已是成员则不再邀请
+		var invitee model.Users
+		if err := tx.Where("email = ?", email).Limit(1).Find(&invitee).Error; err != nil {
+			return err
+		}
+		if invitee.Id > 0 {
+			var members int64
+			if err := tx.Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", projectId, invitee.Id).Count(&members).Error; err != nil {
+				return err
+			}
+			if members > 0 {
+				return ErrMemberExists
+			}
+		}
+
+		// 重复邀请同一邮箱时作废之前的待处理邀请，相当于重新发送
+		now := time.Now()
+		if err := tx.Model(&model.ProjectInvitations{}).
+			Where("project_id = ? AND email = ? AND status = ?", projectId, email, model.InvitationStatusPending).
+			Updates(map[string]any{"status": model.InvitationStatusRevoked, "responded_at": now}).Error; err != nil {
+			return err
+		}
+
+		inv = model.ProjectInvitations{
+			ProjectId: projectId,
+			Email:     email,
+			Role:      req.Role,
+			InvitedBy: uint64(userId),
+			InviteeId: invitee.Id,
+			Status:    model.InvitationStatusPending,
+			ExpiresAt: now.Add(l.svcCtx.InvitationTTL()).Truncate(time.Second),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := tx.Create(&inv).Error; err != nil {
+			return err
+		}
+		token, err := model.SignInvitationToken(l.svcCtx.InvitationSecret(), inv.Id, inv.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		inv.Token = token
+		return tx.Model(&model.ProjectInvitations{}).Where("id = ?", inv.Id).Update("token", token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[CreateInvitation] UserId=%d invited %s to project %d as %s", userId, email, req.ProjectId, req.Role)
+	item := invitationItem(&inv, projectName, true)
+	return &item, nil
+}
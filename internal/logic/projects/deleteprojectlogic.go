@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -40,20 +41,35 @@ func (l *DeleteProjectLogic) DeleteProject(req *types.DeleteProjectReq) (resp *t
 		return nil, errors.New("id required")
 	}
 
-	// Check ownership
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).Where("id = ? AND owner_id = ?", req.Id, userId).Count(&count).Error; err != nil {
+	// 仅 owner 可删除项目
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.Id), userId, permission.ProjectDelete); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	tx := l.svcCtx.DB.WithContext(l.ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 	subFiles := tx.Table("project_files").Select("file_id").Where("project_id = ?", req.Id)
+	// 释放项目内所有版本的内容引用，提交后删除已无引用的对象
+	var versions []model.FileVersions
+	if err = tx.Where("file_id IN (?)", subFiles).Find(&versions).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	releasedKeys, err := model.ReleaseFileBlobs(tx, versions)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Where("file_id IN (?)", subFiles).Delete(&model.FileVersionLabels{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Where("file_id IN (?)", subFiles).Delete(&model.FileVersionContents{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err = tx.Table("file_versions").Where("file_id IN (?)", subFiles).Delete(&model.FileVersions{}).Error; err != nil {
 		tx.Rollback()
 		return nil, err
@@ -66,10 +82,31 @@ func (l *DeleteProjectLogic) DeleteProject(req *types.DeleteProjectReq) (resp *t
 		tx.Rollback()
 		return nil, err
 	}
+	if err = tx.Where("project_id = ?", req.Id).Delete(&model.FileLocks{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Where("project_id = ?", req.Id).Delete(&model.VersionRetentionPolicies{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	subChangesets := tx.Table("changesets").Select("id").Where("project_id = ?", req.Id)
+	if err = tx.Where("changeset_id IN (?)", subChangesets).Delete(&model.ChangesetFiles{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Where("project_id = ?", req.Id).Delete(&model.Changesets{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err = tx.Table("project_members").Where("project_id = ?", req.Id).Delete(&model.ProjectMembers{}).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+	if err = tx.Where("project_id = ?", req.Id).Delete(&model.ProjectInvitations{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err = tx.Table("projects").Where("id = ?", req.Id).Delete(&model.Projects{}).Error; err != nil {
 		tx.Rollback()
 		return nil, err
@@ -77,6 +114,13 @@ func (l *DeleteProjectLogic) DeleteProject(req *types.DeleteProjectReq) (resp *t
 	if err = tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	if l.svcCtx.ObjectStore != nil {
+		for _, key := range releasedKeys {
+			if err := l.svcCtx.ObjectStore.DeleteObject(l.ctx, key); err != nil {
+				l.Errorf("[DeleteProject] Failed to delete object %s: %v", key, err)
+			}
+		}
+	}
 	resp = &types.BaseResp{Code: 0, Msg: "ok"}
 
 	return resp, nil
@@ -0,0 +1,252 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/projectarchive"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ExportProjectLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewExportProjectLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExportProjectLogic {
+	return &ExportProjectLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ExportProject 校验权限并收集导出条目：每个文件的当前版本，allVersions 时附带全部已完成版本，manifest 时附带项目结构清单。
+// 内容由 handler 调用 projectarchive.WriteZip 流式写出
+func (l *ExportProjectLogic) ExportProject(req *types.ExportProjectReq) (*projectarchive.Export, error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if l.svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+
+	db := l.svcCtx.DB.WithContext(l.ctx)
+	if _, err := permission.Authorize(l.ctx, db, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+	projectId := uint64(req.ProjectId)
+	project, err := l.svcCtx.ProjectsModel.FindOne(l.ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := model.FolderPaths(db, projectId)
+	if err != nil {
+		return nil, err
+	}
+	var files []model.Files
+	if err := db.Model(&model.Files{}).
+		Joins("JOIN project_files ON project_files.file_id = files.id").
+		Where("project_files.project_id = ?", projectId).
+		Order("files.id ASC").Find(&files).Error; err != nil {
+		return nil, err
+	}
+	fileIds := make([]uint64, len(files))
+	for i, f := range files {
+		fileIds[i] = f.Id
+	}
+	var versions []model.FileVersions
+	if len(fileIds) > 0 {
+		if err := db.Where("file_id IN ? AND status = ?", fileIds, model.FileVersionStatusReady).
+			Order("file_id ASC, version_number ASC").Find(&versions).Error; err != nil {
+			return nil, err
+		}
+	}
+	versionsByFile := make(map[uint64][]model.FileVersions, len(files))
+	versionIds := make([]uint64, 0, len(versions))
+	for _, v := range versions {
+		versionsByFile[v.FileId] = append(versionsByFile[v.FileId], v)
+		versionIds = append(versionIds, v.Id)
+	}
+	labels, err := model.VersionLabels(db, versionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &projectarchive.Manifest{
+		FormatVersion: projectarchive.FormatVersion,
+		ExportedAt:    time.Now(),
+		Project: projectarchive.Project{
+			Id:          project.Id,
+			Name:        project.Name,
+			Description: project.Description.String,
+			CoverFileId: project.CoverFileId,
+		},
+		Files: make([]projectarchive.File, 0, len(files)),
+	}
+	export := &projectarchive.Export{
+		FileName: fmt.Sprintf("project-%d-%s.zip", projectId, manifest.ExportedAt.Format("20060102150405")),
+		Entries:  make([]projectarchive.Entry, 0, len(files)),
+	}
+	for _, f := range files {
+		filePath := model.JoinFolderPath(paths[f.FolderId], f.Name)
+		item := projectarchive.File{
+			Id:               f.Id,
+			FolderId:         f.FolderId,
+			Name:             f.Name,
+			Path:             filePath,
+			FileCategory:     f.FileCategory,
+			FileFormat:       f.FileFormat,
+			CurrentVersionId: f.CurrentVersionId,
+			Versions:         make([]projectarchive.Version, 0, len(versionsByFile[f.Id])),
+		}
+		for _, v := range versionsByFile[f.Id] {
+			version := projectarchive.Version{
+				Id:            v.Id,
+				VersionNumber: v.VersionNumber,
+				SizeBytes:     v.SizeBytes,
+				Hash:          v.Hash,
+				CreatedAt:     v.CreatedAt,
+				Message:       v.Message,
+				Labels:        labels[v.Id],
+			}
+			if v.Id == f.CurrentVersionId {
+				item.Entry = projectarchive.CurrentEntry(filePath)
+				export.Entries = append(export.Entries, projectarchive.Entry{Name: item.Entry, StorageKey: v.StorageKey, Modified: v.CreatedAt})
+			}
+			if req.AllVersions {
+				version.Entry = projectarchive.VersionEntry(filePath, v.VersionNumber)
+				export.Entries = append(export.Entries, projectarchive.Entry{Name: version.Entry, StorageKey: v.StorageKey, Modified: v.CreatedAt})
+			}
+			item.Versions = append(item.Versions, version)
+		}
+		manifest.Files = append(manifest.Files, item)
+	}
+
+	if req.Manifest {
+		if err := l.fillManifest(manifest, projectId, paths); err != nil {
+			return nil, err
+		}
+		export.Manifest = manifest
+	}
+
+	l.Infof("[ExportProject] UserId=%d, ProjectId=%d, files=%d, entries=%d, allVersions=%v, manifest=%v",
+		userId, projectId, len(files), len(export.Entries), req.AllVersions, req.Manifest)
+	return export, nil
+}
+
+// fillManifest 补充目录、软件、清单、构建版本与画布图层信息
+func (l *ExportProjectLogic) fillManifest(manifest *projectarchive.Manifest, projectId uint64, paths map[uint64]string) error {
+	db := l.svcCtx.DB.WithContext(l.ctx)
+
+	var folders []model.Folders
+	if err := db.Where("project_id = ?", projectId).Order("id ASC").Find(&folders).Error; err != nil {
+		return err
+	}
+	manifest.Folders = make([]projectarchive.Folder, 0, len(folders))
+	for _, f := range folders {
+		manifest.Folders = append(manifest.Folders, projectarchive.Folder{Id: f.Id, ParentId: f.ParentId, Name: f.Name, Path: paths[f.Id]})
+	}
+
+	var softwares []model.Softwares
+	if err := db.Where("project_id = ?", projectId).Order("id ASC").Find(&softwares).Error; err != nil {
+		return err
+	}
+	manifest.Softwares = make([]projectarchive.Software, 0, len(softwares))
+	for _, s := range softwares {
+		manifest.Softwares = append(manifest.Softwares, projectarchive.Software{
+			Id:              s.Id,
+			Name:            s.Name,
+			Description:     s.Description.String,
+			TemplateId:      s.TemplateId,
+			TechnologyStack: s.TechnologyStack,
+			Status:          s.Status,
+		})
+	}
+
+	var manifests []model.SoftwareManifests
+	if err := db.Where("project_id = ?", projectId).Order("id ASC").Find(&manifests).Error; err != nil {
+		return err
+	}
+	manifest.SoftwareManifests = make([]projectarchive.SoftwareManifest, 0, len(manifests))
+	for _, m := range manifests {
+		manifest.SoftwareManifests = append(manifest.SoftwareManifests, projectarchive.SoftwareManifest{
+			Id:                    m.Id,
+			SoftwareId:            m.SoftwareId,
+			ManifestFileId:        m.ManifestFileId,
+			ManifestFileVersionId: m.ManifestFileVersionId,
+			VersionNumber:         m.VersionNumber,
+			VersionDescription:    m.VersionDescription.String,
+		})
+	}
+
+	var builds []model.BuildVersions
+	if err := db.Where("project_id = ?", projectId).Order("id ASC").Find(&builds).Error; err != nil {
+		return err
+	}
+	manifest.BuildVersions = make([]projectarchive.BuildVersion, 0, len(builds))
+	for _, b := range builds {
+		manifest.BuildVersions = append(manifest.BuildVersions, projectarchive.BuildVersion{
+			Id:                        b.Id,
+			SoftwareManifestId:        b.SoftwareManifestId,
+			VersionNumber:             b.VersionNumber,
+			Description:               b.Description.String,
+			BuildVersionFileId:        b.BuildVersionFileId,
+			BuildVersionFileVersionId: b.BuildVersionFileVersionId,
+			EntryPath:                 b.EntryPath,
+		})
+	}
+
+	manifest.Layers = make([]projectarchive.Layer, 0)
+	canvas, err := l.svcCtx.WorkspaceCanvasModel.FindOneByProjectId(l.ctx, projectId)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	manifest.Canvas = &projectarchive.Canvas{
+		Id:              canvas.Id,
+		Name:            canvas.Name,
+		BackgroundColor: canvas.BackgroundColor,
+		Metadata:        canvas.Metadata.String,
+	}
+	layers, err := l.svcCtx.WorkspaceLayerModel.FindByCanvasId(l.ctx, canvas.Id)
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		manifest.Layers = append(manifest.Layers, projectarchive.Layer{
+			Id:         layer.Id,
+			LayerType:  layer.LayerType,
+			Name:       layer.Name,
+			ZIndex:     layer.ZIndex,
+			PositionX:  layer.PositionX,
+			PositionY:  layer.PositionY,
+			Width:      layer.Width,
+			Height:     layer.Height,
+			Rotation:   layer.Rotation,
+			Visible:    layer.Visible,
+			Locked:     layer.Locked,
+			Properties: layer.Properties,
+			FileId:     uint64(layer.FileId.Int64),
+		})
+	}
+	return nil
+}
@@ -8,8 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 
-	"github.com/anil-wu/spark-x/internal/model"
-
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -42,13 +41,9 @@ func (l *GetProjectLogic) GetProject(req *types.GetProjectReq) (resp *types.Proj
 	}
 
 	// Check project membership
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", req.Id, userId).Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.Id), userId, permission.ProjectView); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
-	}
 
 	p, err := l.svcCtx.ProjectsModel.FindOne(l.ctx, uint64(req.Id))
 	if err != nil {
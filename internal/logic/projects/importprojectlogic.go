@@ -0,0 +1,540 @@
+package projects
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/projectarchive"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type ImportProjectLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewImportProjectLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ImportProjectLogic {
+	return &ImportProjectLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// importVersion 待导入的版本及其在压缩包中的内容
+type importVersion struct {
+	file    *projectarchive.File
+	version *projectarchive.Version
+	entry   string
+	hash    string // 按内容计算的 sha256
+	rewrite bool   // 软件清单 / 构建版本文件，导入后需要改写其中的 fileId / versionId
+}
+
+// ImportProject 由导出包（上传的 ZIP，或 storageKey 指向的已上传对象）重建项目：
+// 新建项目与 owner 成员，还原目录、文件与版本、软件、软件清单、构建版本、画布与图层，所有 ID 重新映射。
+// 内容在事务前按 sha256 写入存储，已存在的内容直接复用
+func (l *ImportProjectLogic) ImportProject(req *types.ImportProjectReq, upload io.ReaderAt, size int64) (resp *types.ImportProjectResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil {
+		return nil, model.InputParamInvalid
+	}
+	if l.svcCtx.ObjectStore == nil {
+		return nil, errors.New("object store not configured")
+	}
+	if upload == nil {
+		tmp, tmpSize, err := l.downloadArchive(strings.TrimSpace(req.StorageKey), userId)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}()
+		upload, size = tmp, tmpSize
+	}
+
+	arc, err := projectarchive.OpenArchive(upload, size)
+	if err != nil {
+		return nil, err
+	}
+	manifest := arc.Manifest
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = manifest.Project.Name
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	versions, totalBytes, err := l.collectVersions(arc)
+	if err != nil {
+		return nil, err
+	}
+	// 新项目尚无用量，只校验导入者的用户配额；上传对象前先校验一次，事务内加锁后再以最终结果为准
+	if err := l.svcCtx.CheckStorageQuota(l.svcCtx.DB.WithContext(l.ctx), 0, uint64(userId), totalBytes); err != nil {
+		return nil, err
+	}
+	if err := l.uploadBlobs(arc, versions); err != nil {
+		return nil, err
+	}
+
+	resp = &types.ImportProjectResp{}
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := l.svcCtx.CheckStorageQuota(tx, 0, uint64(userId), totalBytes); err != nil {
+			return err
+		}
+		return l.restore(tx, arc, versions, name, uint64(userId), resp)
+	})
+	if err != nil {
+		l.Errorf("[ImportProject] UserId=%d import failed: %v", userId, err)
+		return nil, err
+	}
+
+	l.Infof("[ImportProject] UserId=%d imported project %d (source %d): files=%d versions=%d softwares=%d manifests=%d builds=%d layers=%d",
+		userId, resp.Project.Id, manifest.Project.Id, resp.Files, resp.Versions, resp.Softwares, resp.SoftwareManifests, resp.BuildVersions, resp.Layers)
+	return resp, nil
+}
+
+// downloadArchive 将导入者自己上传过的对象下载到临时文件，供 ZIP 随机读取
+func (l *ImportProjectLogic) downloadArchive(storageKey string, userId int64) (*os.File, int64, error) {
+	if storageKey == "" {
+		return nil, 0, errors.New("file or storageKey is required")
+	}
+	var count int64
+	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.FileVersions{}).
+		Where("storage_key = ? AND created_by = ? AND status = ?", storageKey, userId, model.FileVersionStatusReady).
+		Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if count == 0 {
+		return nil, 0, errors.New("archive not found or permission denied")
+	}
+	if limit := l.svcCtx.Config.Import.MaxUploadBytes; limit > 0 {
+		stat, err := l.svcCtx.ObjectStore.StatObject(l.ctx, storageKey)
+		if err != nil {
+			return nil, 0, err
+		}
+		if stat.SizeBytes > limit {
+			return nil, 0, fmt.Errorf("archive exceeds %d bytes", limit)
+		}
+	}
+
+	reader, err := l.svcCtx.ObjectStore.GetObject(l.ctx, storageKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = reader.Close() }()
+	tmp, err := os.CreateTemp("", "sparkx-import-*.zip")
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := io.Copy(tmp, reader)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return nil, 0, err
+	}
+	return tmp, n, nil
+}
+
+// collectVersions 确定每个文件可导入的版本：有独立条目的历史版本，以及 files/ 下的当前版本；
+// 逐个校验条目大小与 sha256
+func (l *ImportProjectLogic) collectVersions(arc *projectarchive.Archive) ([]*importVersion, int64, error) {
+	manifest := arc.Manifest
+	rewrite := make(map[uint64]bool)
+	for _, m := range manifest.SoftwareManifests {
+		rewrite[m.ManifestFileVersionId] = true
+	}
+	for _, b := range manifest.BuildVersions {
+		rewrite[b.BuildVersionFileVersionId] = true
+	}
+
+	var result []*importVersion
+	var totalBytes int64
+	for i := range manifest.Files {
+		f := &manifest.Files[i]
+		for j := range f.Versions {
+			v := &f.Versions[j]
+			entry := v.Entry
+			if entry == "" && v.Id == f.CurrentVersionId {
+				entry = f.Entry
+			}
+			if entry == "" {
+				continue
+			}
+			if !arc.Has(entry) {
+				return nil, 0, fmt.Errorf("archive entry %s not found", entry)
+			}
+			sum, err := hashEntry(arc, entry, v.SizeBytes)
+			if err != nil {
+				return nil, 0, err
+			}
+			if model.IsSha256Hex(v.Hash) && !strings.EqualFold(v.Hash, sum) {
+				return nil, 0, fmt.Errorf("archive entry %s hash mismatch", entry)
+			}
+			result = append(result, &importVersion{file: f, version: v, entry: entry, hash: sum, rewrite: rewrite[v.Id]})
+			totalBytes += int64(v.SizeBytes)
+		}
+	}
+	return result, totalBytes, nil
+}
+
+// hashEntry 计算条目内容的 sha256，并校验大小与清单一致（同时防止压缩炸弹）
+func hashEntry(arc *projectarchive.Archive, entry string, sizeBytes uint64) (string, error) {
+	rc, err := arc.Open(entry)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(rc, int64(sizeBytes)+1))
+	if err != nil {
+		return "", err
+	}
+	if uint64(n) != sizeBytes {
+		return "", fmt.Errorf("archive entry %s size mismatch", entry)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// uploadBlobs 将尚未存储的内容写入内容寻址路径；需改写的清单文件在事务中按改写后的内容写入
+func (l *ImportProjectLogic) uploadBlobs(arc *projectarchive.Archive, versions []*importVersion) error {
+	done := make(map[string]bool)
+	for _, iv := range versions {
+		if iv.rewrite || done[iv.hash] {
+			continue
+		}
+		done[iv.hash] = true
+		var blob model.FileBlobs
+		err := l.svcCtx.DB.WithContext(l.ctx).Where("hash = ?", iv.hash).First(&blob).Error
+		if err == nil && blob.Verified {
+			continue
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		rc, err := arc.Open(iv.entry)
+		if err != nil {
+			return err
+		}
+		_, err = l.svcCtx.ObjectStore.PutObject(l.ctx, model.BlobStorageKey(iv.hash), rc)
+		_ = rc.Close()
+		if err != nil {
+			l.Errorf("[ImportProject] Failed to upload %s: %v", iv.entry, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// acquireBlob 增加内容引用并标记为已校验；对象不存在时写入 content
+func (l *ImportProjectLogic) acquireBlob(tx *gorm.DB, sum string, sizeBytes uint64, content []byte) (*model.FileBlobs, error) {
+	blob, err := model.AcquireFileBlob(tx, sum, model.BlobStorageKey(sum), sizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Verified {
+		return blob, nil
+	}
+	if content != nil {
+		if _, err := l.svcCtx.ObjectStore.PutObject(l.ctx, blob.StorageKey, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Model(&model.FileBlobs{}).Where("id = ?", blob.Id).Update("verified", true).Error; err != nil {
+		return nil, err
+	}
+	blob.Verified = true
+	return blob, nil
+}
+
+// restore 在事务中写入全部记录
+func (l *ImportProjectLogic) restore(tx *gorm.DB, arc *projectarchive.Archive, versions []*importVersion, name string, userId uint64, resp *types.ImportProjectResp) error {
+	manifest := arc.Manifest
+
+	project := &model.Projects{
+		Name:        name,
+		Description: sql.NullString{String: manifest.Project.Description, Valid: manifest.Project.Description != ""},
+		OwnerId:     userId,
+		Status:      "active",
+	}
+	if err := tx.Create(project).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&model.ProjectMembers{ProjectId: project.Id, UserId: userId, Role: "owner"}).Error; err != nil {
+		return err
+	}
+
+	// 目录：按路径深度由浅到深创建，保证上级目录先于子目录
+	folders := append([]projectarchive.Folder(nil), manifest.Folders...)
+	sort.SliceStable(folders, func(i, j int) bool {
+		return strings.Count(folders[i].Path, "/") < strings.Count(folders[j].Path, "/")
+	})
+	folderIds := map[uint64]uint64{0: 0}
+	for _, f := range folders {
+		folder := &model.Folders{ProjectId: project.Id, ParentId: folderIds[f.ParentId], Name: f.Name, CreatedBy: userId}
+		if err := tx.Create(folder).Error; err != nil {
+			return err
+		}
+		folderIds[f.Id] = folder.Id
+		resp.Folders++
+	}
+
+	fileIds := make(map[uint64]uint64, len(manifest.Files))
+	for i := range manifest.Files {
+		f := &manifest.Files[i]
+		file := &model.Files{
+			Name:         f.Name,
+			FileCategory: f.FileCategory,
+			FileFormat:   f.FileFormat,
+			FolderId:     folderIds[f.FolderId],
+		}
+		if err := tx.Create(file).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.ProjectFiles{ProjectId: project.Id, FileId: file.Id}).Error; err != nil {
+			return err
+		}
+		fileIds[f.Id] = file.Id
+		resp.Files++
+	}
+
+	versionIds := make(map[uint64]uint64, len(versions))
+	created := make(map[*importVersion]*model.FileVersions, len(versions))
+	for _, iv := range versions {
+		version := &model.FileVersions{
+			FileId:        fileIds[iv.file.Id],
+			VersionNumber: iv.version.VersionNumber,
+			SizeBytes:     iv.version.SizeBytes,
+			Hash:          iv.hash,
+			StorageKey:    model.BlobStorageKey(iv.hash),
+			CreatedBy:     userId,
+			Status:        model.FileVersionStatusReady,
+			Message:       iv.version.Message,
+		}
+		if !iv.rewrite {
+			blob, err := l.acquireBlob(tx, iv.hash, iv.version.SizeBytes, nil)
+			if err != nil {
+				return err
+			}
+			version.StorageKey = blob.StorageKey
+		}
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		// 导入的标签不合法时忽略，不影响内容恢复
+		if labels, err := model.NormalizeVersionLabels(iv.version.Labels); err == nil {
+			if err := model.SetVersionLabels(tx, version.FileId, version.Id, labels, userId); err != nil {
+				return err
+			}
+		}
+		versionIds[iv.version.Id] = version.Id
+		created[iv] = version
+		resp.Versions++
+	}
+
+	// 清单文件中的 fileId / versionId 指向源项目，按映射改写后作为新内容存储
+	for _, iv := range versions {
+		if !iv.rewrite {
+			continue
+		}
+		content, err := readEntry(arc, iv.entry)
+		if err != nil {
+			return err
+		}
+		sum := iv.hash
+		if out, changed, err := projectarchive.RemapFileRefs(content, fileIds, versionIds); err != nil {
+			return err
+		} else if changed {
+			content = out
+			h := sha256.Sum256(content)
+			sum = hex.EncodeToString(h[:])
+		}
+		blob, err := l.acquireBlob(tx, sum, uint64(len(content)), content)
+		if err != nil {
+			return err
+		}
+		version := created[iv]
+		if err := tx.Model(&model.FileVersions{}).Where("id = ?", version.Id).Updates(map[string]any{
+			"hash":        sum,
+			"storage_key": blob.StorageKey,
+			"size_bytes":  uint64(len(content)),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	// 当前版本：源当前版本未导入时取已导入的最高版本
+	current := make(map[uint64]*model.FileVersions)
+	for _, iv := range versions {
+		if iv.version.Id == iv.file.CurrentVersionId {
+			current[created[iv].FileId] = created[iv]
+		}
+	}
+	for _, iv := range versions {
+		version := created[iv]
+		if c, ok := current[version.FileId]; !ok || (versionIds[iv.file.CurrentVersionId] == 0 && c.VersionNumber < version.VersionNumber) {
+			current[version.FileId] = version
+		}
+	}
+	for fileId, version := range current {
+		if err := tx.Model(&model.Files{}).Where("id = ?", fileId).Update("current_version_id", version.Id).Error; err != nil {
+			return err
+		}
+	}
+
+	if coverId, ok := fileIds[manifest.Project.CoverFileId]; ok && coverId > 0 {
+		if err := tx.Model(&model.Projects{}).Where("id = ?", project.Id).Update("cover_file_id", coverId).Error; err != nil {
+			return err
+		}
+		project.CoverFileId = coverId
+	}
+
+	softwareIds := make(map[uint64]uint64, len(manifest.Softwares))
+	for _, s := range manifest.Softwares {
+		software := &model.Softwares{
+			ProjectId:       project.Id,
+			Name:            s.Name,
+			Description:     sql.NullString{String: s.Description, Valid: s.Description != ""},
+			TemplateId:      s.TemplateId,
+			TechnologyStack: s.TechnologyStack,
+			Status:          s.Status,
+			CreatedBy:       userId,
+		}
+		if err := tx.Create(software).Error; err != nil {
+			return err
+		}
+		softwareIds[s.Id] = software.Id
+		resp.Softwares++
+	}
+
+	manifestIds := make(map[uint64]uint64, len(manifest.SoftwareManifests))
+	for _, m := range manifest.SoftwareManifests {
+		softwareId, ok := softwareIds[m.SoftwareId]
+		if !ok {
+			continue
+		}
+		sm := &model.SoftwareManifests{
+			ProjectId:             project.Id,
+			SoftwareId:            softwareId,
+			ManifestFileId:        fileIds[m.ManifestFileId],
+			ManifestFileVersionId: versionIds[m.ManifestFileVersionId],
+			VersionNumber:         m.VersionNumber,
+			VersionDescription:    sql.NullString{String: m.VersionDescription, Valid: m.VersionDescription != ""},
+			CreatedBy:             userId,
+		}
+		if err := tx.Create(sm).Error; err != nil {
+			return err
+		}
+		manifestIds[m.Id] = sm.Id
+		resp.SoftwareManifests++
+	}
+
+	// 预览资源不在导出包中，需要重新上传后才能预览
+	for _, b := range manifest.BuildVersions {
+		manifestId, ok := manifestIds[b.SoftwareManifestId]
+		if !ok {
+			continue
+		}
+		bv := &model.BuildVersions{
+			ProjectId:                 project.Id,
+			SoftwareManifestId:        manifestId,
+			VersionNumber:             b.VersionNumber,
+			Description:               sql.NullString{String: b.Description, Valid: b.Description != ""},
+			BuildVersionFileId:        fileIds[b.BuildVersionFileId],
+			BuildVersionFileVersionId: versionIds[b.BuildVersionFileVersionId],
+			EntryPath:                 b.EntryPath,
+			CreatedBy:                 userId,
+		}
+		if err := tx.Create(bv).Error; err != nil {
+			return err
+		}
+		resp.BuildVersions++
+	}
+
+	canvas := &model.WorkspaceCanvas{
+		ProjectId:       project.Id,
+		Name:            "Main Canvas",
+		BackgroundColor: "#ffffff",
+		CreatedBy:       userId,
+	}
+	if manifest.Canvas != nil {
+		canvas.Name = manifest.Canvas.Name
+		canvas.BackgroundColor = manifest.Canvas.BackgroundColor
+		canvas.Metadata = sql.NullString{String: manifest.Canvas.Metadata, Valid: manifest.Canvas.Metadata != ""}
+	}
+	if err := tx.Create(canvas).Error; err != nil {
+		return err
+	}
+	for _, layer := range manifest.Layers {
+		properties := layer.Properties
+		if out, changed, err := projectarchive.RemapFileRefs([]byte(properties), fileIds, versionIds); err == nil && changed {
+			properties = strings.TrimSpace(string(out))
+		}
+		row := &model.WorkspaceLayer{
+			CanvasId:   canvas.Id,
+			LayerType:  layer.LayerType,
+			Name:       layer.Name,
+			ZIndex:     layer.ZIndex,
+			PositionX:  layer.PositionX,
+			PositionY:  layer.PositionY,
+			Width:      layer.Width,
+			Height:     layer.Height,
+			Rotation:   layer.Rotation,
+			Visible:    layer.Visible,
+			Locked:     layer.Locked,
+			Properties: properties,
+			CreatedBy:  userId,
+		}
+		if fileId, ok := fileIds[layer.FileId]; ok && fileId > 0 {
+			row.FileId = sql.NullInt64{Int64: int64(fileId), Valid: true}
+		}
+		if err := tx.Create(row).Error; err != nil {
+			return err
+		}
+		resp.Layers++
+	}
+
+	resp.Project = types.ProjectResp{
+		Id:          int64(project.Id),
+		Name:        project.Name,
+		Description: project.Description.String,
+		CoverFileId: int64(project.CoverFileId),
+		OwnerId:     int64(project.OwnerId),
+		Status:      project.Status,
+		CreatedAt:   project.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   project.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	return nil
+}
+
+// readEntry 读取清单类小文件的完整内容（大小已在 collectVersions 中校验）
+func readEntry(arc *projectarchive.Archive, entry string) ([]byte, error) {
+	rc, err := arc.Open(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
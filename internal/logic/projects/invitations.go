@@ -0,0 +1,54 @@
+package projects
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+var (
+	ErrInvitationNotFound   = errorx.New(http.StatusNotFound, "invitation_not_found", "invitation not found")
+	ErrInvitationNotPending = errorx.New(http.StatusConflict, "invitation_not_pending", "invitation is no longer pending")
+	errInvalidEmail         = errors.New("invalid email")
+)
+
+// validateInvitationEmail 只做基本格式检查，返回小写邮箱
+func validateInvitationEmail(email string) (string, error) {
+	email = model.NormalizeEmail(email)
+	at := strings.Index(email, "@")
+	if at <= 0 || at == len(email)-1 || len(email) > 255 || strings.ContainsAny(email, " \t\r\n") {
+		return "", errInvalidEmail
+	}
+	return email, nil
+}
+
+// invitationStatus 待处理但已过期的邀请显示为 expired
+func invitationStatus(inv *model.ProjectInvitations, now time.Time) string {
+	if inv.Status == model.InvitationStatusPending && !now.Before(inv.ExpiresAt) {
+		return "expired"
+	}
+	return inv.Status
+}
+
+func invitationItem(inv *model.ProjectInvitations, projectName string, withToken bool) types.InvitationItem {
+	item := types.InvitationItem{
+		Id:          int64(inv.Id),
+		ProjectId:   int64(inv.ProjectId),
+		ProjectName: projectName,
+		Email:       inv.Email,
+		Role:        inv.Role,
+		Status:      invitationStatus(inv, time.Now()),
+		InvitedBy:   int64(inv.InvitedBy),
+		ExpiresAt:   inv.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:   inv.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if withToken {
+		item.Token = inv.Token
+	}
+	return item
+}
@@ -0,0 +1,68 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestValidateInvitationEmail(t *testing.T) {
+	if got, err := validateInvitationEmail(" Bob@Example.com "); err != nil || got != "bob@example.com" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "bob", "@example.com", "bob@", "bob smith@example.com"} {
+		if _, err := validateInvitationEmail(bad); err != errInvalidEmail {
+			t.Errorf("%q: got %v", bad, err)
+		}
+	}
+}
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Now()
+	inv := &model.ProjectInvitations{Status: model.InvitationStatusPending, ExpiresAt: now.Add(time.Minute)}
+	if got := invitationStatus(inv, now); got != model.InvitationStatusPending {
+		t.Fatalf("got %q", got)
+	}
+	if got := invitationStatus(inv, now.Add(time.Hour)); got != "expired" {
+		t.Fatalf("got %q", got)
+	}
+	inv.Status = model.InvitationStatusAccepted
+	if got := invitationStatus(inv, now.Add(time.Hour)); got != model.InvitationStatusAccepted {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestCreateInvitationRoles(t *testing.T) {
+	for _, c := range []struct {
+		role    string
+		invitee string
+		denied  bool
+	}{
+		{permission.RoleViewer, permission.RoleViewer, true},
+		{permission.RoleDeveloper, permission.RoleViewer, true},
+		{permission.RoleAdmin, permission.RoleDeveloper, false},
+		{permission.RoleAdmin, permission.RoleOwner, true},
+		{permission.RoleOwner, permission.RoleOwner, false},
+	} {
+		db, err := permissiontest.OpenDB(c.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewCreateInvitationLogic(ctx, &svc.ServiceContext{DB: db}).CreateInvitation(&types.CreateInvitationReq{
+			ProjectId: 1,
+			Email:     "new@example.com",
+			Role:      c.invitee,
+		})
+		if permission.IsDenied(err) != c.denied {
+			t.Errorf("%s inviting %s: denied=%v, got %v", c.role, c.invitee, c.denied, err)
+		}
+	}
+}
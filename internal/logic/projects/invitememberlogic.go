@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -40,13 +41,34 @@ func (l *InviteMemberLogic) InviteMember(req *types.InviteMemberReq) (resp *type
 		return nil, errors.New("invalid params")
 	}
 
-	// Check if user is owner
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).Where("id = ? AND owner_id = ?", req.ProjectId, userId).Count(&count).Error; err != nil {
+	if !permission.ValidRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+
+	// owner 与 admin 可邀请成员
+	role, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.MemberManage)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role == permission.RoleOwner {
+		if err := permission.Check(role, permission.OwnerGrant); err != nil {
+			return nil, err
+		}
+	}
+
+	var users int64
+	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.Users{}).Where("id = ?", req.InvitedUserId).Count(&users).Error; err != nil {
+		return nil, err
+	}
+	if users == 0 {
+		return nil, errors.New("invited user not found")
+	}
+	var members int64
+	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", req.ProjectId, req.InvitedUserId).Count(&members).Error; err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
+	if members > 0 {
+		return nil, ErrMemberExists
 	}
 
 	pm := &model.ProjectMembers{
@@ -0,0 +1,72 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type LeaveProjectLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLeaveProjectLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LeaveProjectLogic {
+	return &LeaveProjectLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *LeaveProjectLogic) LeaveProject(req *types.LeaveProjectReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, errors.New("id required")
+	}
+	projectId := uint64(req.ProjectId)
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		owners, err := lockOwners(tx, projectId)
+		if err != nil {
+			return err
+		}
+		member, err := lockMember(tx, projectId, uint64(userId))
+		if errors.Is(err, ErrMemberNotFound) {
+			return permission.ErrNotMember
+		}
+		if err != nil {
+			return err
+		}
+		// 最后一个 owner 需要先转让项目
+		if member.Role == permission.RoleOwner {
+			if err := releaseOwner(tx, projectId, owners, member.UserId); err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", member.Id).Delete(&model.ProjectMembers{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[LeaveProject] UserId=%d left project %d", userId, req.ProjectId)
+	return &types.BaseResp{Code: 0, Msg: "ok"}, nil
+}
@@ -0,0 +1,72 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListProjectInvitationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListProjectInvitationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListProjectInvitationsLogic {
+	return &ListProjectInvitationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListProjectInvitationsLogic) ListProjectInvitations(req *types.ListProjectInvitationsReq) (resp *types.ListInvitationsResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	status := strings.TrimSpace(req.Status)
+	if status == "" {
+		status = model.InvitationStatusPending
+	}
+	switch status {
+	case "all", model.InvitationStatusPending, model.InvitationStatusAccepted, model.InvitationStatusDeclined, model.InvitationStatusRevoked:
+	default:
+		return nil, model.InputParamInvalid
+	}
+
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.MemberManage); err != nil {
+		return nil, err
+	}
+
+	query := l.svcCtx.DB.WithContext(l.ctx).Where("project_id = ?", req.ProjectId)
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	var list []model.ProjectInvitations
+	if err := query.Order("id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	resp = &types.ListInvitationsResp{List: make([]types.InvitationItem, 0, len(list))}
+	for i := range list {
+		resp.List = append(resp.List, invitationItem(&list[i], "", false))
+	}
+	return resp, nil
+}
@@ -0,0 +1,77 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListProjectMembersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListProjectMembersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListProjectMembersLogic {
+	return &ListProjectMembersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListProjectMembersLogic) ListProjectMembers(req *types.ListProjectMembersReq) (resp *types.ListProjectMembersResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 {
+		return nil, errors.New("id required")
+	}
+
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		UserId    uint64
+		Username  string
+		Email     string
+		Avatar    string
+		Role      string
+		CreatedAt time.Time
+	}
+	if err := l.svcCtx.DB.WithContext(l.ctx).Table("project_members").
+		Select("project_members.user_id, users.username, users.email, users.avatar, project_members.role, project_members.created_at").
+		Joins("LEFT JOIN users ON users.id = project_members.user_id").
+		Where("project_members.project_id = ?", req.ProjectId).
+		Order("FIELD(project_members.role, 'owner', 'admin', 'developer', 'viewer'), project_members.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	resp = &types.ListProjectMembersResp{List: make([]types.ProjectMemberItem, 0, len(rows))}
+	for _, r := range rows {
+		resp.List = append(resp.List, types.ProjectMemberItem{
+			UserId:    int64(r.UserId),
+			Username:  r.Username,
+			Email:     r.Email,
+			Avatar:    r.Avatar,
+			Role:      r.Role,
+			CreatedAt: r.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return resp, nil
+}
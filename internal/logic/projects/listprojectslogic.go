@@ -7,12 +7,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type ListProjectsLogic struct {
@@ -29,7 +31,7 @@ func NewListProjectsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *List
 	}
 }
 
-func (l *ListProjectsLogic) ListProjects(req *types.PageReq) (resp *types.ProjectListResp, err error) {
+func (l *ListProjectsLogic) ListProjects(req *types.ListProjectsReq) (resp *types.ProjectListResp, err error) {
 	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
 	if !ok {
 		return nil, errors.New("unauthorized")
@@ -49,18 +51,30 @@ func (l *ListProjectsLogic) ListProjects(req *types.PageReq) (resp *types.Projec
 	}
 	offset := (page - 1) * size
 
+	// 默认只列出未归档的项目
+	status := strings.TrimSpace(req.Status)
+	if status == "" {
+		status = model.ProjectStatusActive
+	}
+	if status != "all" && status != model.ProjectStatusActive && status != model.ProjectStatusArchived {
+		return nil, model.InputParamInvalid
+	}
+	query := func() *gorm.DB {
+		q := l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).
+			Joins("JOIN project_members ON project_members.project_id = projects.id").
+			Where("project_members.user_id = ?", userId)
+		if status != "all" {
+			q = q.Where("projects.status = ?", status)
+		}
+		return q
+	}
+
 	var list []model.Projects
-	if err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).
-		Joins("JOIN project_members ON project_members.project_id = projects.id").
-		Where("project_members.user_id = ?", userId).
-		Offset(offset).Limit(size).Order("projects.id desc").Find(&list).Error; err != nil {
+	if err = query().Offset(offset).Limit(size).Order("projects.id desc").Find(&list).Error; err != nil {
 		return nil, err
 	}
 	var total int64
-	if err = l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).
-		Joins("JOIN project_members ON project_members.project_id = projects.id").
-		Where("project_members.user_id = ?", userId).
-		Count(&total).Error; err != nil {
+	if err = query().Count(&total).Error; err != nil {
 		return nil, err
 	}
 	items := make([]types.ProjectResp, 0, len(list))
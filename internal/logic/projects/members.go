@@ -0,0 +1,60 @@
+package projects
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMemberNotFound = errorx.New(http.StatusNotFound, "member_not_found", "member not found")
+	ErrMemberExists   = errorx.New(http.StatusConflict, "member_exists", "user is already a member of the project")
+	ErrLastOwner      = errorx.New(http.StatusConflict, "last_owner", "project must keep at least one owner, transfer ownership first")
+)
+
+// lockOwners 按 id 顺序锁定项目的全部 owner 成员行，避免并发降级后项目没有 owner；需先于 lockMember 调用以保持加锁顺序
+func lockOwners(tx *gorm.DB, projectId uint64) ([]model.ProjectMembers, error) {
+	var owners []model.ProjectMembers
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("project_id = ? AND role = ?", projectId, permission.RoleOwner).
+		Order("id").Find(&owners).Error
+	return owners, err
+}
+
+// lockMember 锁定项目成员行
+func lockMember(tx *gorm.DB, projectId uint64, userId uint64) (*model.ProjectMembers, error) {
+	var member model.ProjectMembers
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("project_id = ? AND user_id = ?", projectId, userId).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// releaseOwner 成员即将不再是 owner：owners 为已锁定的 owner 列表，至少要剩一个 owner，
+// projects.owner_id 指向该成员时改为最早的其余 owner
+func releaseOwner(tx *gorm.DB, projectId uint64, owners []model.ProjectMembers, userId uint64) error {
+	var next uint64
+	for _, o := range owners {
+		if o.UserId != userId {
+			next = o.UserId
+			break
+		}
+	}
+	if next == 0 {
+		return ErrLastOwner
+	}
+	return tx.Model(&model.Projects{}).
+		Where("id = ? AND owner_id = ?", projectId, userId).
+		Update("owner_id", next).Error
+}
@@ -0,0 +1,166 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestUpdateProjectRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: false,
+		permission.RoleViewer:    false,
+	}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svcCtx := &svc.ServiceContext{DB: db, ProjectsModel: model.NewProjectsModel(db, nil)}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewUpdateProjectLogic(ctx, svcCtx).UpdateProject(&types.UpdateProjectReq{Id: 1, Name: "renamed"})
+		return err
+	})
+}
+
+func TestInviteOwnerRequiresOwner(t *testing.T) {
+	for _, c := range []struct {
+		role   string
+		denied bool
+	}{
+		{permission.RoleOwner, false},
+		{permission.RoleAdmin, true},
+	} {
+		db, err := permissiontest.OpenDB(c.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svcCtx := &svc.ServiceContext{DB: db, ProjectMembersModel: model.NewProjectMembersModel(db, nil)}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewInviteMemberLogic(ctx, svcCtx).InviteMember(&types.InviteMemberReq{
+			ProjectId:     1,
+			InvitedUserId: 8,
+			Role:          permission.RoleOwner,
+		})
+		if permission.IsDenied(err) != c.denied {
+			t.Errorf("%s: denied=%v, got %v", c.role, c.denied, err)
+		}
+	}
+}
+
+func TestUpdateProjectMemberRoles(t *testing.T) {
+	for _, c := range []struct {
+		role   string
+		target string
+		denied bool
+	}{
+		{permission.RoleViewer, permission.RoleDeveloper, true},
+		{permission.RoleDeveloper, permission.RoleViewer, true},
+		{permission.RoleAdmin, permission.RoleViewer, false},
+		{permission.RoleOwner, permission.RoleAdmin, false},
+	} {
+		db, err := permissiontest.OpenDB(c.role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewUpdateProjectMemberLogic(ctx, &svc.ServiceContext{DB: db}).UpdateProjectMember(&types.UpdateProjectMemberReq{
+			ProjectId: 1,
+			UserId:    8,
+			Role:      c.target,
+		})
+		// 假数据库无法返回目标成员，允许的请求会在之后的查询中失败
+		if permission.IsDenied(err) != c.denied {
+			t.Errorf("%s -> %s: denied=%v, got %v", c.role, c.target, c.denied, err)
+		}
+	}
+}
+
+func TestRemoveProjectMemberRoles(t *testing.T) {
+	allowed := map[string]bool{permission.RoleOwner: true, permission.RoleAdmin: true}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewRemoveProjectMemberLogic(ctx, &svc.ServiceContext{DB: db}).RemoveProjectMember(&types.RemoveProjectMemberReq{ProjectId: 1, UserId: 8})
+		return err
+	})
+}
+
+func TestReleaseOwnerKeepsLastOwner(t *testing.T) {
+	owners := []model.ProjectMembers{{UserId: 7, Role: permission.RoleOwner}}
+	if err := releaseOwner(nil, 1, owners, 7); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("last owner: got %v", err)
+	}
+	if err := releaseOwner(nil, 1, nil, 7); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("no owners: got %v", err)
+	}
+}
+
+func TestTransferOwnershipRoles(t *testing.T) {
+	allowed := map[string]bool{permission.RoleOwner: true}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewTransferOwnershipLogic(ctx, &svc.ServiceContext{DB: db}).TransferOwnership(&types.TransferOwnershipReq{
+			ProjectId:  1,
+			NewOwnerId: 8,
+		})
+		return err
+	})
+}
+
+func TestTransferOwnershipRejectsOwnerRole(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+	_, err := NewTransferOwnershipLogic(ctx, &svc.ServiceContext{}).TransferOwnership(&types.TransferOwnershipReq{
+		ProjectId:         1,
+		NewOwnerId:        8,
+		PreviousOwnerRole: permission.RoleOwner,
+	})
+	if err != permission.ErrInvalidOwnerRole {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestUpdateArchivedProject(t *testing.T) {
+	db, err := permissiontest.OpenProjectDB(permission.RoleAdmin, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx := &svc.ServiceContext{DB: db, ProjectsModel: model.NewProjectsModel(db, nil)}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+
+	_, err = NewUpdateProjectLogic(ctx, svcCtx).UpdateProject(&types.UpdateProjectReq{Id: 1, Name: "renamed"})
+	if !errors.Is(err, permission.ErrProjectArchived) {
+		t.Errorf("rename archived: got %v", err)
+	}
+	// 取消归档不受只读限制，假数据库在之后的更新中失败
+	_, err = NewUpdateProjectLogic(ctx, svcCtx).UpdateProject(&types.UpdateProjectReq{Id: 1, Status: model.ProjectStatusActive})
+	if err == nil || errors.Is(err, permission.ErrProjectArchived) || permission.IsDenied(err) {
+		t.Errorf("unarchive: got %v", err)
+	}
+
+	db, err = permissiontest.OpenProjectDB(permission.RoleDeveloper, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx = &svc.ServiceContext{DB: db, ProjectsModel: model.NewProjectsModel(db, nil)}
+	_, err = NewUpdateProjectLogic(ctx, svcCtx).UpdateProject(&types.UpdateProjectReq{Id: 1, Status: model.ProjectStatusActive})
+	if !permission.IsDenied(err) {
+		t.Errorf("developer unarchive: got %v", err)
+	}
+}
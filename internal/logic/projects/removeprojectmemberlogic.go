@@ -0,0 +1,76 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type RemoveProjectMemberLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRemoveProjectMemberLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RemoveProjectMemberLogic {
+	return &RemoveProjectMemberLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RemoveProjectMemberLogic) RemoveProjectMember(req *types.RemoveProjectMemberReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.UserId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := permission.Authorize(l.ctx, tx, projectId, userId, permission.MemberManage)
+		if err != nil {
+			return err
+		}
+		owners, err := lockOwners(tx, projectId)
+		if err != nil {
+			return err
+		}
+		member, err := lockMember(tx, projectId, uint64(req.UserId))
+		if err != nil {
+			return err
+		}
+		// 移除 owner 只能由 owner 操作
+		if member.Role == permission.RoleOwner {
+			if err := permission.Check(role, permission.OwnerGrant); err != nil {
+				return err
+			}
+			if err := releaseOwner(tx, projectId, owners, member.UserId); err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", member.Id).Delete(&model.ProjectMembers{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[RemoveProjectMember] UserId=%d removed user %d from project %d", userId, req.UserId, req.ProjectId)
+	return &types.BaseResp{Code: 0, Msg: "ok"}, nil
+}
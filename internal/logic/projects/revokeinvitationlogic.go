@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokeInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeInvitationLogic {
+	return &RevokeInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeInvitationLogic) RevokeInvitation(req *types.RevokeInvitationReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.InvitationId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	projectId := uint64(req.ProjectId)
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := permission.Authorize(l.ctx, tx, projectId, userId, permission.ProjectView)
+		if err != nil {
+			return err
+		}
+		var inv model.ProjectInvitations
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND project_id = ?", req.InvitationId, projectId).
+			First(&inv).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvitationNotFound
+			}
+			return err
+		}
+		// 邀请者可以撤销自己发出的邀请，撤销他人的邀请需要成员管理权限
+		if inv.InvitedBy != uint64(userId) {
+			if err := permission.Check(role, permission.MemberManage); err != nil {
+				return err
+			}
+		}
+		if inv.Status != model.InvitationStatusPending {
+			return ErrInvitationNotPending
+		}
+		return tx.Model(&model.ProjectInvitations{}).Where("id = ?", inv.Id).
+			Updates(map[string]any{"status": model.InvitationStatusRevoked, "responded_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[RevokeInvitation] UserId=%d revoked invitation %d of project %d", userId, req.InvitationId, req.ProjectId)
+	return &types.BaseResp{Code: 0, Msg: "ok"}, nil
+}
@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type TransferOwnershipLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTransferOwnershipLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TransferOwnershipLogic {
+	return &TransferOwnershipLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TransferOwnershipLogic) TransferOwnership(req *types.TransferOwnershipReq) (resp *types.TransferOwnershipResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.NewOwnerId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	previousRole := strings.TrimSpace(req.PreviousOwnerRole)
+	if previousRole == "" {
+		previousRole = permission.RoleAdmin
+	}
+	if !permission.ValidPreviousOwnerRole(previousRole) {
+		return nil, permission.ErrInvalidOwnerRole
+	}
+	projectId := uint64(req.ProjectId)
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := permission.Authorize(l.ctx, tx, projectId, userId, permission.OwnerGrant); err != nil {
+			return err
+		}
+		// 只有 owner_id 对应的用户可以转让；其他情况（如原 owner 已离职）由管理员处理
+		var ownerId uint64
+		if err := tx.Model(&model.Projects{}).Select("owner_id").Where("id = ?", projectId).Scan(&ownerId).Error; err != nil {
+			return err
+		}
+		if ownerId != uint64(userId) {
+			return permission.ErrPermissionDenied.WithDetails(map[string]any{"action": "project.transfer", "reason": "only the project owner can transfer ownership"})
+		}
+		_, err := permission.TransferOwnership(tx, projectId, uint64(req.NewOwnerId), previousRole, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[TransferOwnership] UserId=%d transferred project %d to user %d, previous owner role %s", userId, req.ProjectId, req.NewOwnerId, previousRole)
+	return &types.TransferOwnershipResp{
+		ProjectId:         req.ProjectId,
+		OwnerId:           req.NewOwnerId,
+		PreviousOwnerId:   userId,
+		PreviousOwnerRole: previousRole,
+	}, nil
+}
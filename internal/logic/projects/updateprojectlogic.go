@@ -10,6 +10,7 @@ import (
 	"errors"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -41,13 +42,19 @@ func (l *UpdateProjectLogic) UpdateProject(req *types.UpdateProjectReq) (resp *t
 		return nil, errors.New("id required")
 	}
 
-	// Check ownership
-	var count int64
-	if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.Projects{}).Where("id = ? AND owner_id = ?", req.Id, userId).Count(&count).Error; err != nil {
+	if req.Status != "" && req.Status != model.ProjectStatusActive && req.Status != model.ProjectStatusArchived {
+		return nil, model.InputParamInvalid
+	}
+
+	// owner 与 admin 可修改项目信息、归档与取消归档
+	if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.Id), userId, permission.ProjectUpdate); err != nil {
 		return nil, err
 	}
-	if count == 0 {
-		return nil, errors.New("project not found or permission denied")
+	// 归档项目只读，只接受取消归档的修改
+	if req.Status != model.ProjectStatusActive {
+		if err := permission.CheckWritable(l.ctx, l.svcCtx.DB, uint64(req.Id)); err != nil {
+			return nil, err
+		}
 	}
 
 	data := &model.Projects{
@@ -0,0 +1,84 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type UpdateProjectMemberLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateProjectMemberLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateProjectMemberLogic {
+	return &UpdateProjectMemberLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateProjectMemberLogic) UpdateProjectMember(req *types.UpdateProjectMemberReq) (resp *types.BaseResp, err error) {
+	userIdNumber, ok := l.ctx.Value("userId").(json.Number)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	userId, _ := userIdNumber.Int64()
+
+	if req == nil || req.ProjectId <= 0 || req.UserId <= 0 {
+		return nil, model.InputParamInvalid
+	}
+	if !permission.ValidRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+	projectId := uint64(req.ProjectId)
+
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := permission.Authorize(l.ctx, tx, projectId, userId, permission.MemberManage)
+		if err != nil {
+			return err
+		}
+		owners, err := lockOwners(tx, projectId)
+		if err != nil {
+			return err
+		}
+		member, err := lockMember(tx, projectId, uint64(req.UserId))
+		if err != nil {
+			return err
+		}
+		if member.Role == req.Role {
+			return nil
+		}
+		// 授予或收回 owner 只能由 owner 操作
+		if member.Role == permission.RoleOwner || req.Role == permission.RoleOwner {
+			if err := permission.Check(role, permission.OwnerGrant); err != nil {
+				return err
+			}
+		}
+		if member.Role == permission.RoleOwner {
+			if err := releaseOwner(tx, projectId, owners, member.UserId); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.ProjectMembers{}).Where("id = ?", member.Id).Update("role", req.Role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("[UpdateProjectMember] UserId=%d set role of user %d in project %d to %s", userId, req.UserId, req.ProjectId, req.Role)
+	return &types.BaseResp{Code: 0, Msg: "ok"}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -54,16 +55,13 @@ func (l *CreateSoftwareLogic) CreateSoftware(req *types.CreateSoftwareReq) (resp
 		return nil, errors.New("db not configured")
 	}
 
+	// 管理员不受角色限制，但归档项目同样只读
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-			Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-			Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.SoftwareWrite); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
-		}
+	} else if err := permission.CheckWritable(l.ctx, l.svcCtx.DB, uint64(req.ProjectId)); err != nil {
+		return nil, err
 	}
 
 	status := strings.TrimSpace(req.Status)
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -64,16 +65,13 @@ func (l *CreateSoftwareManifestLogic) CreateSoftwareManifest(req *types.CreateSo
 		return nil, model.InputParamInvalid
 	}
 
+	// 管理员不受角色限制，但归档项目同样只读
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-			Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-			Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.SoftwareWrite); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
-		}
+	} else if err := permission.CheckWritable(l.ctx, l.svcCtx.DB, uint64(req.ProjectId)); err != nil {
+		return nil, err
 	}
 
 	tx := l.svcCtx.DB.WithContext(l.ctx).Begin()
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -53,15 +54,9 @@ func (l *ListLatestSoftwareManifestsLogic) ListLatestSoftwareManifests(req *type
 	}
 
 	if !isAdmin {
-		var count int64
-		if err := l.svcCtx.DB.WithContext(l.ctx).Model(&model.ProjectMembers{}).
-			Where("project_id = ? AND user_id = ?", req.ProjectId, userId).
-			Count(&count).Error; err != nil {
+		if _, err := permission.Authorize(l.ctx, l.svcCtx.DB, uint64(req.ProjectId), userId, permission.ProjectView); err != nil {
 			return nil, err
 		}
-		if count == 0 {
-			return nil, errors.New("project not found or permission denied")
-		}
 	}
 
 	subQuery := l.svcCtx.DB.WithContext(l.ctx).
@@ -0,0 +1,63 @@
+package softwares
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestCreateSoftwareRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: true,
+		permission.RoleViewer:    false,
+	}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewCreateSoftwareLogic(ctx, &svc.ServiceContext{DB: db}).CreateSoftware(&types.CreateSoftwareReq{
+			ProjectId: 1,
+			Name:      "game",
+		})
+		return err
+	})
+}
+
+func TestCreateSoftwareManifestRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: true,
+		permission.RoleViewer:    false,
+	}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		// 软件 2 属于项目 1
+		db, err := permissiontest.Open(permissiontest.Options{
+			Role: role,
+			Tables: map[string]permissiontest.Table{
+				"softwares": {Columns: []string{"id", "project_id"}, Rows: [][]driver.Value{{int64(2), int64(1)}}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewCreateSoftwareManifestLogic(ctx, &svc.ServiceContext{DB: db}).CreateSoftwareManifest(&types.CreateSoftwareManifestReq{
+			ProjectId:             1,
+			SoftwareId:            2,
+			ManifestFileId:        1,
+			ManifestFileVersionId: 1,
+		})
+		return err
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -29,7 +30,7 @@ func NewCreateCanvasLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Crea
 }
 
 func (l *CreateCanvasLogic) CreateCanvas(req *types.CreateCanvasReq) (resp *types.CreateCanvasResp, err error) {
-	userId, err := ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId)
+	userId, err := ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId, permission.CanvasWrite)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -37,7 +38,7 @@ func (l *DeleteLayerLogic) DeleteLayer(req *types.DeleteLayerReq) (resp *types.D
 	if err != nil {
 		return nil, err
 	}
-	userId, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId))
+	userId, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId), permission.CanvasWrite)
 	if err != nil {
 		return nil, err
 	}
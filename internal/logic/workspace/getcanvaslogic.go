@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -29,7 +30,7 @@ func NewGetCanvasLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCanv
 }
 
 func (l *GetCanvasLogic) GetCanvas(req *types.GetCanvasReq) (resp *types.CanvasWithLayersResp, err error) {
-	_, err = ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId)
+	_, err = ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId, permission.ProjectView)
 	if err != nil {
 		return nil, err
 	}
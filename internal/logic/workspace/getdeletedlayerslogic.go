@@ -6,6 +6,7 @@ package workspace
 import (
 	"context"
 
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -31,7 +32,7 @@ func (l *GetDeletedLayersLogic) GetDeletedLayers(req *types.GetDeletedLayersReq)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId)); err != nil {
+	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId), permission.ProjectView); err != nil {
 		return nil, err
 	}
 
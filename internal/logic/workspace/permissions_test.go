@@ -0,0 +1,45 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/anil-wu/spark-x/internal/types"
+)
+
+func TestSyncLayersRoles(t *testing.T) {
+	allowed := map[string]bool{
+		permission.RoleOwner:     true,
+		permission.RoleAdmin:     true,
+		permission.RoleDeveloper: true,
+		permission.RoleViewer:    false,
+	}
+	permissiontest.CheckRoles(t, allowed, func(role string) error {
+		db, err := permissiontest.OpenDB(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svcCtx := &svc.ServiceContext{DB: db, WorkspaceCanvasModel: model.NewWorkspaceCanvasModel(db, nil)}
+		ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+		_, err = NewSyncLayersLogic(ctx, svcCtx).SyncLayers(&types.SyncLayersReq{ProjectId: 1})
+		return err
+	})
+}
+
+func TestSyncLayersArchivedProject(t *testing.T) {
+	db, err := permissiontest.OpenProjectDB(permission.RoleOwner, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx := &svc.ServiceContext{DB: db, WorkspaceCanvasModel: model.NewWorkspaceCanvasModel(db, nil)}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("7"))
+	if _, err := NewSyncLayersLogic(ctx, svcCtx).SyncLayers(&types.SyncLayersReq{ProjectId: 1}); !errors.Is(err, permission.ErrProjectArchived) {
+		t.Errorf("got %v", err)
+	}
+}
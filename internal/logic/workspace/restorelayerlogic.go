@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -37,7 +38,7 @@ func (l *RestoreLayerLogic) RestoreLayer(req *types.RestoreLayerReq) (resp *type
 	if err != nil {
 		return nil, err
 	}
-	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId)); err != nil {
+	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId), permission.CanvasWrite); err != nil {
 		return nil, err
 	}
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -31,7 +32,7 @@ func NewSyncLayersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SyncLa
 }
 
 func (l *SyncLayersLogic) SyncLayers(req *types.SyncLayersReq) (resp *types.SyncLayersResp, err error) {
-	userId, err := ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId)
+	userId, err := ensureProjectMember(l.ctx, l.svcCtx, req.ProjectId, permission.CanvasWrite)
 	if err != nil {
 		return nil, err
 	}
@@ -174,7 +175,7 @@ func isSuperFromContext(ctx context.Context) bool {
 	return ok && v
 }
 
-func ensureProjectMember(ctx context.Context, svcCtx *svc.ServiceContext, projectId int64) (int64, error) {
+func ensureProjectMember(ctx context.Context, svcCtx *svc.ServiceContext, projectId int64, action permission.Action) (int64, error) {
 	userIdNumber, ok := ctx.Value("userId").(json.Number)
 	if !ok {
 		return 0, errors.New("unauthorized")
@@ -184,17 +185,19 @@ func ensureProjectMember(ctx context.Context, svcCtx *svc.ServiceContext, projec
 		return 0, errors.New("unauthorized")
 	}
 	if isSuperFromContext(ctx) {
+		// 超级用户不受角色限制，但归档项目同样只读
+		if permission.Writes(action) && projectId > 0 {
+			if err := permission.CheckWritable(ctx, svcCtx.DB, uint64(projectId)); err != nil {
+				return 0, err
+			}
+		}
 		return userId, nil
 	}
 	if projectId <= 0 {
 		return 0, model.InputParamInvalid
 	}
-	var count int64
-	if err := svcCtx.DB.WithContext(ctx).Model(&model.ProjectMembers{}).Where("project_id = ? AND user_id = ?", projectId, userId).Count(&count).Error; err != nil {
+	if _, err := permission.Authorize(ctx, svcCtx.DB, uint64(projectId), userId, action); err != nil {
 		return 0, err
 	}
-	if count == 0 {
-		return 0, errors.New("project not found or permission denied")
-	}
 	return userId, nil
 }
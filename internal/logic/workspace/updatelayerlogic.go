@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 
 	"github.com/anil-wu/spark-x/internal/model"
+	"github.com/anil-wu/spark-x/internal/permission"
 	"github.com/anil-wu/spark-x/internal/svc"
 	"github.com/anil-wu/spark-x/internal/types"
 
@@ -44,7 +45,7 @@ func (l *UpdateLayerLogic) UpdateLayer(req *types.UpdateLayerReq) (resp *types.B
 	if err != nil {
 		return nil, err
 	}
-	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId)); err != nil {
+	if _, err := ensureProjectMember(l.ctx, l.svcCtx, int64(canvas.ProjectId), permission.CanvasWrite); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	ChangesetStatusOpen       = "open"
+	ChangesetStatusCommitted  = "committed"
+	ChangesetStatusAborted    = "aborted"
+	ChangesetStatusRolledBack = "rolled_back"
+)
+
+// Changesets 多文件变更集：打开后预上传的版本不会立即成为当前版本，提交时在同一事务中统一切换
+type Changesets struct {
+	Id          uint64       `db:"id" gorm:"column:id;primaryKey"`
+	ProjectId   uint64       `db:"project_id" gorm:"column:project_id"`
+	Message     string       `db:"message" gorm:"column:message"`
+	Status      string       `db:"status" gorm:"column:status"`
+	CreatedBy   uint64       `db:"created_by" gorm:"column:created_by"`
+	CommittedAt sql.NullTime `db:"committed_at" gorm:"column:committed_at"`
+	CreatedAt   time.Time    `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time    `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Changesets) TableName() string { return "changesets" }
+
+// ChangesetFiles 变更集中的文件，每个文件只保留最后一次预上传的版本；
+// PreviousVersionId 为提交时被替换的当前版本，用于整体回滚，0 表示文件由该变更集新建
+type ChangesetFiles struct {
+	Id                uint64    `db:"id" gorm:"column:id;primaryKey"`
+	ChangesetId       uint64    `db:"changeset_id" gorm:"column:changeset_id"`
+	FileId            uint64    `db:"file_id" gorm:"column:file_id"`
+	VersionId         uint64    `db:"version_id" gorm:"column:version_id"`
+	PreviousVersionId uint64    `db:"previous_version_id" gorm:"column:previous_version_id"`
+	CreatedAt         time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt         time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (ChangesetFiles) TableName() string { return "changeset_files" }
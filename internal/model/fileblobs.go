@@ -0,0 +1,190 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FileBlobs 按内容 hash 去重的存储对象，RefCount 为引用该对象的 file_versions 数量
+type FileBlobs struct {
+	Id         uint64    `db:"id" gorm:"column:id;primaryKey"`
+	Hash       string    `db:"hash" gorm:"column:hash"`
+	StorageKey string    `db:"storage_key" gorm:"column:storage_key"`
+	SizeBytes  uint64    `db:"size_bytes" gorm:"column:size_bytes"`
+	RefCount   uint64    `db:"ref_count" gorm:"column:ref_count"`
+	Verified   bool      `db:"verified" gorm:"column:verified"`
+	CreatedAt  time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (FileBlobs) TableName() string { return "file_blobs" }
+
+// BlobStorageKey 按内容 sha256 生成存储路径，相同内容共用同一对象
+// 格式: blobs/sha256/hash前两位/完整hash
+func BlobStorageKey(fileHash string) string {
+	return fmt.Sprintf("blobs/sha256/%s/%s", fileHash[:2], fileHash)
+}
+
+// StagingKeyPrefix 尚未校验 hash 的临时对象，校验后内容归入 blobs/，临时对象随即删除
+const StagingKeyPrefix = "staging/"
+
+// StagingStorageKey 生成随机的临时对象路径
+func StagingStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return StagingKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// IsSha256Hex 校验 hash 是否为 64 位十六进制 sha256
+func IsSha256Hex(value string) bool {
+	if len(value) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// LockFileBlob 在事务中锁定 hash 对应的记录，不存在时返回 nil
+func LockFileBlob(tx *gorm.DB, hash string) (*FileBlobs, error) {
+	var blob FileBlobs
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(&blob).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// ProjectUsesStorageKey 项目中是否已有完成的版本引用该存储对象；只有这种情况下才能凭 hash 免上传，
+// 否则知道其它项目文件的 hash 与大小就能读到其内容
+func ProjectUsesStorageKey(tx *gorm.DB, projectId uint64, storageKey string) (bool, error) {
+	var count int64
+	err := tx.Model(&FileVersions{}).
+		Joins("JOIN project_files ON project_files.file_id = file_versions.file_id").
+		Where("project_files.project_id = ? AND file_versions.storage_key = ? AND file_versions.status = ?",
+			projectId, storageKey, FileVersionStatusReady).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// AcquireFileBlob 在事务中为 hash 增加一次引用，不存在时以 storageKey 创建。
+// 返回的记录已加行锁，调用方可据 Verified 判断对象是否已上传并校验过。
+func AcquireFileBlob(tx *gorm.DB, hash string, storageKey string, sizeBytes uint64) (*FileBlobs, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		var blob FileBlobs
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(&blob).Error
+		if err == nil {
+			if err := tx.Model(&FileBlobs{}).Where("id = ?", blob.Id).
+				Update("ref_count", gorm.Expr("ref_count + 1")).Error; err != nil {
+				return nil, err
+			}
+			blob.RefCount++
+			return &blob, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		blob = FileBlobs{
+			Hash:       hash,
+			StorageKey: storageKey,
+			SizeBytes:  sizeBytes,
+			RefCount:   1,
+		}
+		// 并发创建同一 hash 时唯一键冲突，重新加锁读取
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&blob).Error; err != nil {
+			return nil, err
+		}
+		if blob.Id > 0 {
+			return &blob, nil
+		}
+	}
+	return nil, errors.New("acquire file blob failed")
+}
+
+// ReleaseFileBlobs 在事务中释放一组版本对存储对象的引用，返回引用归零、可以删除的对象 key。
+// 去重之前的旧版本没有 file_blobs 记录，此时以其他版本是否仍使用相同 storage_key 判断。
+func ReleaseFileBlobs(tx *gorm.DB, versions []FileVersions) ([]string, error) {
+	releasedIds := make([]uint64, 0, len(versions))
+	for _, v := range versions {
+		releasedIds = append(releasedIds, v.Id)
+	}
+
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, v := range versions {
+		if v.StorageKey == "" {
+			continue
+		}
+		var blob FileBlobs
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("storage_key = ?", v.StorageKey).First(&blob).Error
+		if err == nil {
+			if blob.RefCount > 1 {
+				if err := tx.Model(&FileBlobs{}).Where("id = ?", blob.Id).
+					Update("ref_count", gorm.Expr("ref_count - 1")).Error; err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := tx.Where("id = ?", blob.Id).Delete(&FileBlobs{}).Error; err != nil {
+				return nil, err
+			}
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			if seen[v.StorageKey] {
+				continue
+			}
+			var others int64
+			if err := tx.Model(&FileVersions{}).
+				Where("storage_key = ? AND id NOT IN ?", v.StorageKey, releasedIds).
+				Count(&others).Error; err != nil {
+				return nil, err
+			}
+			if others > 0 {
+				continue
+			}
+		} else {
+			return nil, err
+		}
+		if !seen[v.StorageKey] {
+			seen[v.StorageKey] = true
+			keys = append(keys, v.StorageKey)
+		}
+	}
+	return keys, nil
+}
+
+// FailFileVersion 在事务中把未完成的版本置为 failed，释放它对存储对象的引用并清空 storage_key，
+// 返回可以删除的对象 key；仍保留 storage_key 的旧 failed 记录同样释放。其它状态的版本不做修改
+func FailFileVersion(tx *gorm.DB, versionId uint64) ([]string, error) {
+	var version FileVersions
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND (status = ? OR (status = ? AND storage_key <> ''))",
+			versionId, FileVersionStatusPending, FileVersionStatusFailed).
+		First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ReleaseFileBlobs(tx, []FileVersions{version})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&FileVersions{}).
+		Where("id = ?", version.Id).
+		Updates(map[string]any{"status": FileVersionStatusFailed, "storage_key": ""}).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"bytes"
+	"time"
+	"unicode/utf8"
+)
+
+// FileVersionContents 文本版本的内容索引（FULLTEXT，ngram 分词），在版本校验完成时写入；
+// 搜索只匹配文件的当前版本
+type FileVersionContents struct {
+	VersionId uint64    `db:"version_id" gorm:"column:version_id;primaryKey;autoIncrement:false"`
+	FileId    uint64    `db:"file_id" gorm:"column:file_id"`
+	Content   string    `db:"content" gorm:"column:content"`
+	CreatedAt time.Time `db:"created_at" gorm:"column:created_at"`
+}
+
+func (FileVersionContents) TableName() string { return "file_version_contents" }
+
+// IndexableText 内容为合法 UTF-8 且不含 NUL 时才建立索引，排除被标为文本的二进制内容
+func IndexableText(data []byte) (string, bool) {
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", false
+	}
+	return string(data), true
+}
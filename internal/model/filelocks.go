@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FileLocks 文件检出锁（建议锁）：持有期间其他成员不能上传新版本或回滚，过期后视为已释放
+type FileLocks struct {
+	Id        uint64    `db:"id" gorm:"column:id;primaryKey"`
+	FileId    uint64    `db:"file_id" gorm:"column:file_id"`
+	ProjectId uint64    `db:"project_id" gorm:"column:project_id"`
+	LockedBy  uint64    `db:"locked_by" gorm:"column:locked_by"`
+	Note      string    `db:"note" gorm:"column:note"`
+	ExpiresAt time.Time `db:"expires_at" gorm:"column:expires_at"`
+	CreatedAt time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (FileLocks) TableName() string { return "file_locks" }
+
+// Active 锁未过期
+func (l *FileLocks) Active(now time.Time) bool {
+	return l != nil && l.ExpiresAt.After(now)
+}
+
+// ActiveFileLock 返回文件当前有效的锁，没有锁或已过期时返回 nil
+func ActiveFileLock(db *gorm.DB, fileId uint64) (*FileLocks, error) {
+	var lock FileLocks
+	if err := db.Where("file_id = ?", fileId).First(&lock).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !lock.Active(time.Now()) {
+		return nil, nil
+	}
+	return &lock, nil
+}
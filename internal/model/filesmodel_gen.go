@@ -47,9 +47,12 @@ type (
 		Name             string         `db:"name" gorm:"column:name"`
 		FileCategory     string         `db:"file_category" gorm:"column:file_category"`
 		FileFormat       string         `db:"file_format" gorm:"column:file_format"`
+		FolderId         uint64         `db:"folder_id" gorm:"column:folder_id"`
 		CurrentVersionId uint64         `db:"current_version_id" gorm:"column:current_version_id"`
 		CreatedAt        time.Time      `db:"created_at" gorm:"column:created_at"`
 		DeletedAt        gorm.DeletedAt `db:"deleted_at" gorm:"column:deleted_at;index"`
+		DeletedBy        uint64         `db:"deleted_by" gorm:"column:deleted_by"`
+		DeletedPath      string         `db:"deleted_path" gorm:"column:deleted_path"`
 	}
 )
 
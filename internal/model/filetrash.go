@@ -0,0 +1,43 @@
+package model
+
+import "gorm.io/gorm"
+
+// PurgeFiles 彻底删除文件（含回收站中的文件）：释放内容引用，删除版本、标签、锁与项目关联，
+// 返回已无引用、可从存储删除的对象
+func PurgeFiles(tx *gorm.DB, fileIds []uint64) ([]string, error) {
+	if len(fileIds) == 0 {
+		return nil, nil
+	}
+	var versions []FileVersions
+	if err := tx.Where("file_id IN ?", fileIds).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	keys, err := ReleaseFileBlobs(tx, versions)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("file_id IN ?", fileIds).Delete(&FileVersionLabels{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("file_id IN ?", fileIds).Delete(&FileVersionContents{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("file_id IN ?", fileIds).Delete(&FileVersions{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("file_id IN ?", fileIds).Delete(&FileLocks{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&MultipartUploads{}).
+		Where("file_id IN ? AND status = ?", fileIds, MultipartStatusUploading).
+		Update("status", MultipartStatusAborted).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("file_id IN ?", fileIds).Delete(&ProjectFiles{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Where("id IN ?", fileIds).Delete(&Files{}).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
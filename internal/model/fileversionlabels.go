@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxVersionLabels 单个版本的标签数量上限
+const MaxVersionLabels = 20
+
+// MaxVersionMessageLength 版本说明的长度上限（字符）
+const MaxVersionMessageLength = 1024
+
+var versionLabelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._\-]{0,63}$`)
+
+// FileVersionLabels 版本标签，如 approved、release-1.2；同一标签可打在同一文件的多个版本上，按标签解析时取最新版本
+type FileVersionLabels struct {
+	Id        uint64    `db:"id" gorm:"column:id;primaryKey"`
+	FileId    uint64    `db:"file_id" gorm:"column:file_id"`
+	VersionId uint64    `db:"version_id" gorm:"column:version_id"`
+	Label     string    `db:"label" gorm:"column:label"`
+	CreatedBy uint64    `db:"created_by" gorm:"column:created_by"`
+	CreatedAt time.Time `db:"created_at" gorm:"column:created_at"`
+}
+
+func (FileVersionLabels) TableName() string { return "file_version_labels" }
+
+// NormalizeVersionLabels 去空白、去重并排序；标签只允许字母数字与 . _ -，不超过 64 个字符
+func NormalizeVersionLabels(labels []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(labels))
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if !versionLabelPattern.MatchString(label) {
+			return nil, errors.New("invalid label: " + label)
+		}
+		if _, ok := seen[label]; ok {
+			continue
+		}
+		seen[label] = struct{}{}
+		result = append(result, label)
+	}
+	if len(result) > MaxVersionLabels {
+		return nil, errors.New("too many labels")
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// NormalizeVersionMessage 去首尾空白并校验长度
+func NormalizeVersionMessage(message string) (string, error) {
+	message = strings.TrimSpace(message)
+	if len([]rune(message)) > MaxVersionMessageLength {
+		return "", errors.New("message too long")
+	}
+	return message, nil
+}
+
+// SetVersionLabels 在事务中将版本的标签替换为 labels（需已规范化）
+func SetVersionLabels(tx *gorm.DB, fileId uint64, versionId uint64, labels []string, userId uint64) error {
+	if err := tx.Where("version_id = ?", versionId).Delete(&FileVersionLabels{}).Error; err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	rows := make([]FileVersionLabels, 0, len(labels))
+	for _, label := range labels {
+		rows = append(rows, FileVersionLabels{FileId: fileId, VersionId: versionId, Label: label, CreatedBy: userId})
+	}
+	return tx.Create(&rows).Error
+}
+
+// VersionLabels 批量读取版本标签，按版本 ID 分组
+func VersionLabels(db *gorm.DB, versionIds []uint64) (map[uint64][]string, error) {
+	result := make(map[uint64][]string, len(versionIds))
+	if len(versionIds) == 0 {
+		return result, nil
+	}
+	var rows []FileVersionLabels
+	if err := db.Where("version_id IN ?", versionIds).Order("label ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.VersionId] = append(result[row.VersionId], row.Label)
+	}
+	return result, nil
+}
@@ -5,6 +5,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// 版本上传状态：pending 已预上传未校验，ready 校验通过，failed 校验失败或超时
+const (
+	FileVersionStatusPending = "pending"
+	FileVersionStatusReady   = "ready"
+	FileVersionStatusFailed  = "failed"
+)
+
 var _ FileVersionsModel = (*customFileVersionsModel)(nil)
 
 type (
@@ -53,6 +53,10 @@ type (
 		CreatedAt     time.Time `db:"created_at" gorm:"column:created_at"`
 		UpdatedAt     time.Time `db:"updated_at" gorm:"column:updated_at"`
 		CreatedBy     uint64    `db:"created_by" gorm:"column:created_by"`
+		Status        string    `db:"status" gorm:"column:status"`
+		Message       string    `db:"message" gorm:"column:message"`
+		ChangesetId   uint64    `db:"changeset_id" gorm:"column:changeset_id"`
+		BaseVersionId uint64    `db:"base_version_id" gorm:"column:base_version_id"`
 	}
 )
 
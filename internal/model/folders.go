@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Folders 项目内的目录，ParentId 为 0 表示位于项目根目录；同一目录下名称唯一
+type Folders struct {
+	Id        uint64    `db:"id" gorm:"column:id;primaryKey"`
+	ProjectId uint64    `db:"project_id" gorm:"column:project_id"`
+	ParentId  uint64    `db:"parent_id" gorm:"column:parent_id"`
+	Name      string    `db:"name" gorm:"column:name"`
+	CreatedBy uint64    `db:"created_by" gorm:"column:created_by"`
+	CreatedAt time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Folders) TableName() string { return "folders" }
+
+// FolderPaths 返回项目内所有目录的完整路径（不含结尾 /）
+func FolderPaths(db *gorm.DB, projectId uint64) (map[uint64]string, error) {
+	var folders []Folders
+	if err := db.Where("project_id = ?", projectId).Find(&folders).Error; err != nil {
+		return nil, err
+	}
+	byId := make(map[uint64]*Folders, len(folders))
+	for i := range folders {
+		byId[folders[i].Id] = &folders[i]
+	}
+	paths := make(map[uint64]string, len(folders))
+	var build func(id uint64, depth int) string
+	build = func(id uint64, depth int) string {
+		if p, ok := paths[id]; ok {
+			return p
+		}
+		f, ok := byId[id]
+		if !ok || depth > len(folders) {
+			return ""
+		}
+		p := f.Name
+		if f.ParentId != 0 {
+			if parent := build(f.ParentId, depth+1); parent != "" {
+				p = parent + "/" + f.Name
+			}
+		}
+		paths[id] = p
+		return p
+	}
+	for _, f := range folders {
+		build(f.Id, 0)
+	}
+	return paths, nil
+}
+
+// JoinFolderPath 拼接目录路径与名称
+func JoinFolderPath(dir string, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 邀请状态
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// ErrInvalidInvitationToken 令牌格式错误、签名不符或已过期
+var ErrInvalidInvitationToken = errors.New("invalid or expired invitation token")
+
+// ProjectInvitations 按邮箱发出的项目邀请，接受后才成为成员；令牌只能由邀请对应的用户使用
+type ProjectInvitations struct {
+	Id          uint64       `db:"id" gorm:"column:id;primaryKey"`
+	ProjectId   uint64       `db:"project_id" gorm:"column:project_id"`
+	Email       string       `db:"email" gorm:"column:email"`
+	Role        string       `db:"role" gorm:"column:role"`
+	Token       string       `db:"token" gorm:"column:token"`
+	InvitedBy   uint64       `db:"invited_by" gorm:"column:invited_by"`
+	InviteeId   uint64       `db:"invitee_id" gorm:"column:invitee_id"`
+	Status      string       `db:"status" gorm:"column:status"`
+	ExpiresAt   time.Time    `db:"expires_at" gorm:"column:expires_at"`
+	RespondedAt sql.NullTime `db:"responded_at" gorm:"column:responded_at"`
+	CreatedAt   time.Time    `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time    `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (ProjectInvitations) TableName() string { return "project_invitations" }
+
+// NormalizeEmail 邮箱比较不区分大小写
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SignInvitationToken 生成邀请令牌：<邀请id>.<过期时间戳>.<随机数>.<签名>
+func SignInvitationToken(secret string, invitationId uint64, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	payload := fmt.Sprintf("%d.%d.%s", invitationId, expiresAt.Unix(), hex.EncodeToString(nonce))
+	return payload + "." + invitationSignature(secret, payload), nil
+}
+
+// ParseInvitationToken 校验签名与过期时间，返回邀请 id
+func ParseInvitationToken(secret string, token string, now time.Time) (uint64, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 4 {
+		return 0, ErrInvalidInvitationToken
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(invitationSignature(secret, payload)), []byte(parts[3])) {
+		return 0, ErrInvalidInvitationToken
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidInvitationToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return 0, ErrInvalidInvitationToken
+	}
+	return id, nil
+}
+
+func invitationSignature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AttachInvitations 把发给该邮箱、尚未关联用户的待处理邀请关联到用户，在用户登录时调用
+func AttachInvitations(db *gorm.DB, userId uint64, email string) (int64, error) {
+	email = NormalizeEmail(email)
+	if userId == 0 || email == "" {
+		return 0, nil
+	}
+	result := db.Model(&ProjectInvitations{}).
+		Where("email = ? AND invitee_id = 0 AND status = ?", email, InvitationStatusPending).
+		Update("invitee_id", userId)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvitationToken(t *testing.T) {
+	now := time.Now()
+	token, err := SignInvitationToken("secret", 42, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := ParseInvitationToken("secret", token, now); err != nil || id != 42 {
+		t.Fatalf("ParseInvitationToken = %d, %v", id, err)
+	}
+	if _, err := ParseInvitationToken("other", token, now); err != ErrInvalidInvitationToken {
+		t.Fatalf("wrong secret: got %v", err)
+	}
+	if _, err := ParseInvitationToken("secret", token, now.Add(2*time.Hour)); err != ErrInvalidInvitationToken {
+		t.Fatalf("expired: got %v", err)
+	}
+	tampered := "43" + strings.TrimPrefix(token, "42")
+	if _, err := ParseInvitationToken("secret", tampered, now); err != ErrInvalidInvitationToken {
+		t.Fatalf("tampered: got %v", err)
+	}
+	for _, bad := range []string{"", "a.b.c", "x.1.2.3.4"} {
+		if _, err := ParseInvitationToken("secret", bad, now); err != ErrInvalidInvitationToken {
+			t.Fatalf("%q: got %v", bad, err)
+		}
+	}
+	other, _ := SignInvitationToken("secret", 42, now.Add(time.Hour))
+	if token == other {
+		t.Fatal("tokens for the same invitation must differ")
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := NormalizeEmail("  Alice@Example.COM "); got != "alice@example.com" {
+		t.Fatalf("NormalizeEmail = %q", got)
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	MultipartStatusUploading = "uploading"
+	MultipartStatusCompleted = "completed"
+	MultipartStatusAborted   = "aborted"
+)
+
+// MultipartUploads 分片上传会话，记录对象存储的 uploadId 与对应的文件版本
+type MultipartUploads struct {
+	Id            uint64    `db:"id" gorm:"column:id;primaryKey"`
+	UploadId      string    `db:"upload_id" gorm:"column:upload_id"`
+	ProjectId     uint64    `db:"project_id" gorm:"column:project_id"`
+	FileId        uint64    `db:"file_id" gorm:"column:file_id"`
+	FileVersionId uint64    `db:"file_version_id" gorm:"column:file_version_id"`
+	StorageKey    string    `db:"storage_key" gorm:"column:storage_key"`
+	ContentType   string    `db:"content_type" gorm:"column:content_type"`
+	SizeBytes     uint64    `db:"size_bytes" gorm:"column:size_bytes"`
+	PartSize      uint64    `db:"part_size" gorm:"column:part_size"`
+	Status        string    `db:"status" gorm:"column:status"`
+	CreatedBy     uint64    `db:"created_by" gorm:"column:created_by"`
+	CreatedAt     time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (MultipartUploads) TableName() string { return "multipart_uploads" }
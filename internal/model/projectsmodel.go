@@ -7,6 +7,12 @@ import (
 
 var _ ProjectsModel = (*customProjectsModel)(nil)
 
+// 项目状态，归档项目只读
+const (
+	ProjectStatusActive   = "active"
+	ProjectStatusArchived = "archived"
+)
+
 type (
 	// ProjectsModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customProjectsModel.
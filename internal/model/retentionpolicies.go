@@ -0,0 +1,150 @@
+package model
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxRetentionPatternLength 路径匹配模式的最大长度
+const MaxRetentionPatternLength = 255
+
+// VersionRetentionPolicies 项目的版本保留策略。PathPattern 为空时是项目默认策略；
+// 不含 / 的模式匹配文件名（如 *.log），含 / 的模式匹配项目内完整路径，以 /** 结尾时匹配目录下的所有文件。
+// 保留最近 KeepLast 个版本以及 KeepDays 天内的版本，0 表示不按该条件保留
+type VersionRetentionPolicies struct {
+	Id          uint64    `db:"id" gorm:"column:id;primaryKey"`
+	ProjectId   uint64    `db:"project_id" gorm:"column:project_id"`
+	PathPattern string    `db:"path_pattern" gorm:"column:path_pattern"`
+	KeepLast    uint64    `db:"keep_last" gorm:"column:keep_last"`
+	KeepDays    uint64    `db:"keep_days" gorm:"column:keep_days"`
+	CreatedBy   uint64    `db:"created_by" gorm:"column:created_by"`
+	CreatedAt   time.Time `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time `db:"updated_at" gorm:"column:updated_at"`
+}
+
+func (VersionRetentionPolicies) TableName() string { return "version_retention_policies" }
+
+// NormalizeRetentionPattern 去掉首尾空白与开头的 /，并校验模式语法
+func NormalizeRetentionPattern(pattern string) (string, error) {
+	pattern = strings.TrimLeft(strings.TrimSpace(pattern), "/")
+	if len(pattern) > MaxRetentionPatternLength {
+		return "", errors.New("path pattern too long")
+	}
+	if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+		return "", errors.New("invalid path pattern: " + pattern)
+	}
+	return pattern, nil
+}
+
+// Matches 判断策略是否适用于项目内路径 filePath
+func (p *VersionRetentionPolicies) Matches(filePath string) bool {
+	pattern := p.PathPattern
+	if pattern == "" {
+		return true
+	}
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		for i := 0; i < len(filePath); i++ {
+			if filePath[i] != '/' {
+				continue
+			}
+			if matched, _ := path.Match(dir, filePath[:i]); matched {
+				return true
+			}
+		}
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+	matched, _ := path.Match(pattern, filePath)
+	return matched
+}
+
+// MatchRetentionPolicy 返回适用于文件的策略：多条匹配时取模式最长（最具体）的一条，项目默认策略最后生效；没有适用策略时返回 nil
+func MatchRetentionPolicy(policies []VersionRetentionPolicies, filePath string) *VersionRetentionPolicies {
+	var best *VersionRetentionPolicies
+	for i := range policies {
+		p := &policies[i]
+		if !p.Matches(filePath) {
+			continue
+		}
+		if best == nil || len(p.PathPattern) > len(best.PathPattern) ||
+			(len(p.PathPattern) == len(best.PathPattern) && p.Id < best.Id) {
+			best = p
+		}
+	}
+	return best
+}
+
+// PrunableVersions 按策略选出可以清理的版本。versions 为文件的已完成版本，keep 中的版本（当前版本、被引用的版本）始终保留
+func (p *VersionRetentionPolicies) PrunableVersions(versions []FileVersions, keep map[uint64]bool, now time.Time) []FileVersions {
+	if p.KeepLast == 0 && p.KeepDays == 0 {
+		return nil
+	}
+	newest := make(map[uint64]bool, p.KeepLast)
+	if p.KeepLast > 0 {
+		numbers := make([]uint64, 0, len(versions))
+		for _, v := range versions {
+			numbers = append(numbers, v.VersionNumber)
+		}
+		for _, n := range topNumbers(numbers, int(p.KeepLast)) {
+			newest[n] = true
+		}
+	}
+	cutoff := now.AddDate(0, 0, -int(p.KeepDays))
+	prunable := make([]FileVersions, 0)
+	for _, v := range versions {
+		if keep[v.Id] || newest[v.VersionNumber] {
+			continue
+		}
+		if p.KeepDays > 0 && v.CreatedAt.After(cutoff) {
+			continue
+		}
+		prunable = append(prunable, v)
+	}
+	return prunable
+}
+
+// topNumbers 返回最大的 n 个版本号
+func topNumbers(numbers []uint64, n int) []uint64 {
+	sorted := append([]uint64(nil), numbers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// ReferencedVersionIds 返回 versionIds 中必须保留的版本：带标签的，以及被软件清单、构建版本、发布或变更集引用的
+func ReferencedVersionIds(db *gorm.DB, versionIds []uint64) (map[uint64]bool, error) {
+	referenced := make(map[uint64]bool)
+	if len(versionIds) == 0 {
+		return referenced, nil
+	}
+	queries := []struct {
+		model  any
+		column string
+	}{
+		{&FileVersionLabels{}, "version_id"},
+		{&SoftwareManifests{}, "manifest_file_version_id"},
+		{&BuildVersions{}, "build_version_file_version_id"},
+		{&Releases{}, "release_manifest_file_version_id"},
+		{&ChangesetFiles{}, "version_id"},
+		{&ChangesetFiles{}, "previous_version_id"},
+	}
+	for _, q := range queries {
+		var ids []uint64
+		if err := db.Model(q.model).Distinct().Where(q.column+" IN ?", versionIds).Pluck(q.column, &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			referenced[id] = true
+		}
+	}
+	return referenced, nil
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyMatches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"", "a/b.txt", true},
+		{"*.log", "logs/2024/run.log", true},
+		{"*.log", "run.txt", false},
+		{"builds/*.zip", "builds/app.zip", true},
+		{"builds/*.zip", "builds/web/app.zip", false},
+		{"builds/**", "builds/web/app.zip", true},
+		{"builds/**", "builds.zip", false},
+		{"*/out/**", "game/out/x/y.bin", true},
+	}
+	for _, c := range cases {
+		p := &VersionRetentionPolicies{PathPattern: c.pattern}
+		if got := p.Matches(c.path); got != c.want {
+			t.Fatalf("Matches(%q, %q) = %v, want %v", c.pattern, c.path, got, c.want)
+		}
+	}
+}
+
+func TestMatchRetentionPolicyPrefersSpecific(t *testing.T) {
+	policies := []VersionRetentionPolicies{
+		{Id: 1, PathPattern: ""},
+		{Id: 2, PathPattern: "*.log"},
+		{Id: 3, PathPattern: "logs/**"},
+	}
+	if got := MatchRetentionPolicy(policies, "logs/run.log"); got == nil || got.Id != 3 {
+		t.Fatalf("expected policy 3, got %+v", got)
+	}
+	if got := MatchRetentionPolicy(policies, "src/main.go"); got == nil || got.Id != 1 {
+		t.Fatalf("expected default policy, got %+v", got)
+	}
+	if got := MatchRetentionPolicy(policies[1:], "src/main.go"); got != nil {
+		t.Fatalf("expected no policy, got %+v", got)
+	}
+}
+
+func TestPrunableVersions(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	versions := make([]FileVersions, 0, 6)
+	for i := 1; i <= 6; i++ {
+		versions = append(versions, FileVersions{
+			Id:            uint64(100 + i),
+			VersionNumber: uint64(i),
+			CreatedAt:     now.AddDate(0, 0, -(7-i)*5),
+		})
+	}
+	ids := func(vs []FileVersions) []uint64 {
+		out := make([]uint64, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, v.VersionNumber)
+		}
+		return out
+	}
+
+	p := &VersionRetentionPolicies{KeepLast: 2}
+	if got := ids(p.PrunableVersions(versions, map[uint64]bool{101: true}, now)); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Fatalf("keep last 2 with v1 protected: got %v", got)
+	}
+
+	// 版本 i 创建于 (7-i)*5 天前：12 天内只有 v5、v6
+	p = &VersionRetentionPolicies{KeepDays: 12}
+	if got := ids(p.PrunableVersions(versions, nil, now)); len(got) != 4 || got[3] != 4 {
+		t.Fatalf("keep 12 days: got %v", got)
+	}
+
+	p = &VersionRetentionPolicies{KeepLast: 1, KeepDays: 12}
+	if got := ids(p.PrunableVersions(versions, nil, now)); len(got) != 4 {
+		t.Fatalf("keep last 1 or 12 days: got %v", got)
+	}
+
+	p = &VersionRetentionPolicies{}
+	if got := p.PrunableVersions(versions, nil, now); len(got) != 0 {
+		t.Fatalf("empty policy must keep everything, got %v", ids(got))
+	}
+}
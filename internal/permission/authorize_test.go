@@ -0,0 +1,62 @@
+package permission_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+	"github.com/anil-wu/spark-x/internal/permission/permissiontest"
+)
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	db, err := permissiontest.OpenDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := permission.Authorize(ctx, db, 1, 7, permission.ProjectView); !errors.Is(err, permission.ErrNotMember) {
+		t.Errorf("non-member: got %v", err)
+	}
+
+	db, err = permissiontest.OpenDB(permission.RoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role, err := permission.Authorize(ctx, db, 1, 7, permission.ProjectView); err != nil || role != permission.RoleViewer {
+		t.Errorf("viewer view: role=%q err=%v", role, err)
+	}
+	if role, err := permission.Authorize(ctx, db, 1, 7, permission.FileWrite); !permission.IsDenied(err) || role != permission.RoleViewer {
+		t.Errorf("viewer write: role=%q err=%v", role, err)
+	}
+}
+
+func TestAuthorizeArchivedProject(t *testing.T) {
+	ctx := context.Background()
+	db, err := permissiontest.OpenProjectDB(permission.RoleOwner, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for action := range permission.Matrix {
+		_, err := permission.Authorize(ctx, db, 1, 7, action)
+		if permission.Writes(action) {
+			if !errors.Is(err, permission.ErrProjectArchived) {
+				t.Errorf("%s: want ErrProjectArchived, got %v", action, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: unexpected %v", action, err)
+		}
+	}
+
+	// 角色不允许时优先返回权限错误
+	db, err = permissiontest.OpenProjectDB(permission.RoleViewer, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := permission.Authorize(ctx, db, 1, 7, permission.FileWrite); !permission.IsDenied(err) {
+		t.Errorf("viewer on archived project: got %v", err)
+	}
+	if err := permission.CheckWritable(ctx, db, 0); err != nil {
+		t.Errorf("project 0: got %v", err)
+	}
+}
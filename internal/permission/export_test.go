@@ -0,0 +1,4 @@
+package permission
+
+// Matrix 供外部测试包遍历全部操作
+var Matrix = matrix
@@ -0,0 +1,90 @@
+package permission
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleNone 转让后原 owner 不再保留成员身份
+const RoleNone = "none"
+
+var (
+	ErrProjectNotFound   = errorx.New(http.StatusNotFound, "project_not_found", "project not found")
+	ErrNewOwnerNotMember = errorx.New(http.StatusConflict, "new_owner_not_member", "new owner must be a member of the project")
+	ErrAlreadyOwner      = errorx.New(http.StatusConflict, "already_owner", "user is already the project owner")
+	ErrInvalidOwnerRole  = errorx.New(http.StatusBadRequest, "invalid_previous_owner_role", "previous owner role must be admin, developer, viewer or none")
+)
+
+// ValidPreviousOwnerRole 原 owner 转让后的角色：非 owner 的成员角色，或 none 表示移出项目
+func ValidPreviousOwnerRole(role string) bool {
+	return role == RoleNone || (ValidRole(role) && role != RoleOwner)
+}
+
+// TransferOwnership 在事务中把 projects.owner_id 改为 newOwnerId，新 owner 的成员角色升为 owner，
+// 原 owner 改为 previousRole（none 时移出项目）；addMember 为 true 时新 owner 不是成员也直接加入。
+// 返回原 owner 的用户 id
+func TransferOwnership(tx *gorm.DB, projectId uint64, newOwnerId uint64, previousRole string, addMember bool) (uint64, error) {
+	if !ValidPreviousOwnerRole(previousRole) {
+		return 0, ErrInvalidOwnerRole
+	}
+	var project model.Projects
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", projectId).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrProjectNotFound
+		}
+		return 0, err
+	}
+	previousOwnerId := project.OwnerId
+	if previousOwnerId == newOwnerId {
+		return 0, ErrAlreadyOwner
+	}
+
+	var member model.ProjectMembers
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("project_id = ? AND user_id = ?", projectId, newOwnerId).
+		First(&member).Error
+	switch {
+	case err == nil:
+		if err := tx.Model(&model.ProjectMembers{}).Where("id = ?", member.Id).Update("role", RoleOwner).Error; err != nil {
+			return 0, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound) && addMember:
+		now := time.Now()
+		if err := tx.Create(&model.ProjectMembers{
+			ProjectId: projectId,
+			UserId:    newOwnerId,
+			Role:      RoleOwner,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}).Error; err != nil {
+			return 0, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 0, ErrNewOwnerNotMember
+	default:
+		return 0, err
+	}
+
+	// 原 owner 可能已不是成员（如已离职被移除），此时只更新 owner_id
+	previous := tx.Where("project_id = ? AND user_id = ?", projectId, previousOwnerId)
+	if previousRole == RoleNone {
+		err = previous.Delete(&model.ProjectMembers{}).Error
+	} else {
+		err = previous.Model(&model.ProjectMembers{}).Update("role", previousRole).Error
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Model(&model.Projects{}).Where("id = ?", projectId).Update("owner_id", newOwnerId).Error; err != nil {
+		return 0, err
+	}
+	return previousOwnerId, nil
+}
@@ -0,0 +1,170 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 项目成员角色
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleDeveloper = "developer"
+	RoleViewer    = "viewer"
+)
+
+// Roles 按权限从高到低排列
+var Roles = []string{RoleOwner, RoleAdmin, RoleDeveloper, RoleViewer}
+
+// Action 项目内的操作，能否执行由角色决定
+type Action string
+
+const (
+	ProjectView       Action = "project.view"        // 查看项目、文件、构建、软件、画布等
+	ProjectUpdate     Action = "project.update"      // 修改项目信息
+	ProjectDelete     Action = "project.delete"      // 删除项目
+	MemberManage      Action = "member.manage"       // 邀请成员、修改成员角色
+	OwnerGrant        Action = "member.grant_owner"  // 授予 owner 角色
+	FileWrite         Action = "file.write"          // 上传、目录操作、标签、文件锁、变更集、恢复与回滚
+	FileDelete        Action = "file.delete"         // 删除文件与目录、移出项目、彻底删除
+	FileLockOverride  Action = "file.lock.override"  // 接管或释放他人的文件锁
+	ChangesetOverride Action = "changeset.override"  // 放弃他人的变更集
+	RetentionManage   Action = "retention.manage"    // 管理版本保留策略与清理
+	SoftwareWrite     Action = "software.write"      // 创建软件与软件清单
+	BuildWrite        Action = "build.write"         // 创建、更新构建版本
+	ReleaseCreate     Action = "release.create"      // 创建 dev / qa / beta 发布
+	ReleaseCreateProd Action = "release.create.prod" // 创建 prod 发布
+	CanvasWrite       Action = "canvas.write"        // 创建画布、同步与修改图层
+)
+
+var (
+	readers    = []string{RoleOwner, RoleAdmin, RoleDeveloper, RoleViewer}
+	writers    = []string{RoleOwner, RoleAdmin, RoleDeveloper}
+	managers   = []string{RoleOwner, RoleAdmin}
+	ownersOnly = []string{RoleOwner}
+)
+
+// matrix 操作 × 角色权限表，未列出的操作任何角色都不允许
+var matrix = map[Action][]string{
+	ProjectView:       readers,
+	ProjectUpdate:     managers,
+	ProjectDelete:     ownersOnly,
+	MemberManage:      managers,
+	OwnerGrant:        ownersOnly,
+	FileWrite:         writers,
+	FileDelete:        managers,
+	FileLockOverride:  managers,
+	ChangesetOverride: managers,
+	RetentionManage:   managers,
+	SoftwareWrite:     writers,
+	BuildWrite:        writers,
+	ReleaseCreate:     writers,
+	ReleaseCreateProd: managers,
+	CanvasWrite:       writers,
+}
+
+// writes 修改项目内容的操作，归档项目上一律拒绝；成员管理、修改项目信息与删除项目不受影响
+var writes = map[Action]bool{
+	FileWrite:         true,
+	FileDelete:        true,
+	FileLockOverride:  true,
+	ChangesetOverride: true,
+	RetentionManage:   true,
+	SoftwareWrite:     true,
+	BuildWrite:        true,
+	ReleaseCreate:     true,
+	ReleaseCreateProd: true,
+	CanvasWrite:       true,
+}
+
+var (
+	// ErrNotMember 非成员与项目不存在不做区分
+	ErrNotMember = errors.New("project not found or permission denied")
+	// ErrPermissionDenied 是成员但角色不允许该操作
+	ErrPermissionDenied = errorx.New(http.StatusForbidden, "permission_denied", "permission denied")
+	// ErrProjectArchived 归档项目只读
+	ErrProjectArchived = errorx.New(http.StatusConflict, "project_archived", "project is archived and read-only")
+)
+
+// ValidRole 判断是否为合法的成员角色
+func ValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed 判断角色能否执行操作
+func Allowed(role string, action Action) bool {
+	for _, r := range matrix[action] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 角色不允许执行操作时返回 ErrPermissionDenied，details 中带上操作与角色
+func Check(role string, action Action) error {
+	if Allowed(role, action) {
+		return nil
+	}
+	return ErrPermissionDenied.WithDetails(map[string]any{"action": string(action), "role": role})
+}
+
+// Writes 判断操作是否修改项目内容
+func Writes(action Action) bool {
+	return writes[action]
+}
+
+// CheckWritable 项目已归档时返回 ErrProjectArchived；projectId 为 0（管理员上传的模板等）时不检查
+func CheckWritable(ctx context.Context, db *gorm.DB, projectId uint64) error {
+	if projectId == 0 {
+		return nil
+	}
+	var status string
+	if err := db.WithContext(ctx).Model(&model.Projects{}).Select("status").Where("id = ?", projectId).Scan(&status).Error; err != nil {
+		return err
+	}
+	if status == model.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+	return nil
+}
+
+// IsDenied 判断错误是否为角色权限不足
+func IsDenied(err error) bool {
+	var ce *errorx.CodeError
+	return errors.As(err, &ce) && ce.Code == ErrPermissionDenied.Code
+}
+
+// Authorize 查询用户在项目中的角色并校验操作权限，返回角色；非成员返回 ErrNotMember，
+// 修改内容的操作在归档项目上返回 ErrProjectArchived
+func Authorize(ctx context.Context, db *gorm.DB, projectId uint64, userId int64, action Action) (string, error) {
+	var member model.ProjectMembers
+	if err := db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectId, userId).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrNotMember
+		}
+		return "", err
+	}
+	if err := Check(member.Role, action); err != nil {
+		return member.Role, err
+	}
+	if Writes(action) {
+		if err := CheckWritable(ctx, db, projectId); err != nil {
+			return member.Role, err
+		}
+	}
+	return member.Role, nil
+}
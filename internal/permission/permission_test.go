@@ -0,0 +1,187 @@
+package permission
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+)
+
+// endpoints 项目内接口与所需操作的对照，只校验操作到角色的映射；逻辑层的角色校验见各 logic 包的 permissions_test
+var endpoints = []struct {
+	endpoint string
+	action   Action
+}{
+	{"GET /projects/:id", ProjectView},
+	{"PUT /projects/:id", ProjectUpdate},
+	{"DELETE /projects/:id", ProjectDelete},
+	{"POST /projects/:id/invite", MemberManage},
+	{"POST /projects/:id/invite (owner role)", OwnerGrant},
+	{"GET /projects/:id/members", ProjectView},
+	{"PUT /projects/:id/members/:userId", MemberManage},
+	{"PUT /projects/:id/members/:userId (grant or revoke owner)", OwnerGrant},
+	{"DELETE /projects/:id/members/:userId", MemberManage},
+	{"DELETE /projects/:id/members/:userId (owner)", OwnerGrant},
+	{"POST /projects/:id/transfer-ownership", OwnerGrant},
+	{"POST /projects/:id/invitations", MemberManage},
+	{"POST /projects/:id/invitations (owner role)", OwnerGrant},
+	{"GET /projects/:id/invitations", MemberManage},
+	{"DELETE /projects/:id/invitations/:invitationId (own invitation)", ProjectView},
+	{"DELETE /projects/:id/invitations/:invitationId", MemberManage},
+	{"GET /projects/:projectId/export", ProjectView},
+	{"POST /files/preupload", FileWrite},
+	{"POST /files/multipart/initiate", FileWrite},
+	{"POST /files/multipart/:id/parts/:partNumber/presign", FileWrite},
+	{"GET /files/multipart/:id/parts", ProjectView},
+	{"POST /files/multipart/:id/complete", FileWrite},
+	{"DELETE /files/multipart/:id", FileWrite},
+	{"POST /files/:id/versions/:versionId/complete", FileWrite},
+	{"GET /files/:id/download", ProjectView},
+	{"GET /files/:id/content", ProjectView},
+	{"GET /files/:id/thumbnail", ProjectView},
+	{"GET /files/:id/diff", ProjectView},
+	{"GET /files/:id/versions", ProjectView},
+	{"PUT /files/:id/versions/:versionId/labels", FileWrite},
+	{"POST /files/:id/rollback", FileWrite},
+	{"DELETE /files/:id", FileDelete},
+	{"POST /files/:id/copy", FileWrite},
+	{"POST /files/:id/move", FileDelete},
+	{"POST /files/:id/lock", FileWrite},
+	{"PUT /files/:id/lock", FileWrite},
+	{"DELETE /files/:id/lock", ProjectView},
+	{"POST /files/:id/lock?force=true", FileLockOverride},
+	{"DELETE /files/:id/lock?force=true", FileLockOverride},
+	{"GET /projects/:projectId/locks", ProjectView},
+	{"GET /projects/:projectId/files", ProjectView},
+	{"GET /projects/:projectId/files/by-path", ProjectView},
+	{"GET /projects/:projectId/files/search", ProjectView},
+	{"POST /projects/:projectId/folders", FileWrite},
+	{"PUT /projects/:projectId/folders/:folderId", FileWrite},
+	{"POST /projects/:projectId/folders/:folderId/move", FileWrite},
+	{"DELETE /projects/:projectId/folders/:folderId", FileDelete},
+	{"GET /projects/:projectId/folders/:folderId/children", ProjectView},
+	{"GET /projects/:projectId/trash", ProjectView},
+	{"POST /projects/:projectId/trash/:fileId/restore", FileWrite},
+	{"DELETE /projects/:projectId/trash/:fileId", FileDelete},
+	{"GET /projects/:projectId/retention-policies", ProjectView},
+	{"POST /projects/:projectId/retention-policies", RetentionManage},
+	{"PUT /projects/:projectId/retention-policies/:policyId", RetentionManage},
+	{"DELETE /projects/:projectId/retention-policies/:policyId", RetentionManage},
+	{"POST /projects/:projectId/retention-policies/prune", RetentionManage},
+	{"POST /projects/:projectId/changesets", FileWrite},
+	{"GET /projects/:projectId/changesets", ProjectView},
+	{"GET /changesets/:id", ProjectView},
+	{"POST /changesets/:id/commit", FileWrite},
+	{"POST /changesets/:id/abort", FileWrite},
+	{"POST /changesets/:id/abort (others)", ChangesetOverride},
+	{"POST /changesets/:id/rollback", FileWrite},
+	{"GET /projects/:projectId/softwares", ProjectView},
+	{"POST /projects/:projectId/softwares", SoftwareWrite},
+	{"GET /projects/:projectId/software_manifests", ProjectView},
+	{"POST /software-manifests", SoftwareWrite},
+	{"POST /build-versions", BuildWrite},
+	{"POST /build-versions/draft", BuildWrite},
+	{"PUT /build-versions/:id", BuildWrite},
+	{"GET /projects/:projectId/build-versions", ProjectView},
+	{"POST /releases", ReleaseCreate},
+	{"POST /releases (prod)", ReleaseCreateProd},
+	{"POST /previews/builds/:buildVersionId/preupload", ProjectView},
+	{"GET /projects/:projectId/canvas", ProjectView},
+	{"POST /projects/:projectId/canvas", CanvasWrite},
+	{"POST /projects/:projectId/layers/sync", CanvasWrite},
+	{"PUT /layers/:id", CanvasWrite},
+	{"DELETE /layers/:id", CanvasWrite},
+	{"POST /layers/:id/restore", CanvasWrite},
+	{"GET /layers/deleted", ProjectView},
+}
+
+// expected 每类操作允许的角色
+var expected = map[Action]map[string]bool{
+	ProjectView:       {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true, RoleViewer: true},
+	ProjectUpdate:     {RoleOwner: true, RoleAdmin: true},
+	ProjectDelete:     {RoleOwner: true},
+	MemberManage:      {RoleOwner: true, RoleAdmin: true},
+	OwnerGrant:        {RoleOwner: true},
+	FileWrite:         {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true},
+	FileDelete:        {RoleOwner: true, RoleAdmin: true},
+	FileLockOverride:  {RoleOwner: true, RoleAdmin: true},
+	ChangesetOverride: {RoleOwner: true, RoleAdmin: true},
+	RetentionManage:   {RoleOwner: true, RoleAdmin: true},
+	SoftwareWrite:     {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true},
+	BuildWrite:        {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true},
+	ReleaseCreate:     {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true},
+	ReleaseCreateProd: {RoleOwner: true, RoleAdmin: true},
+	CanvasWrite:       {RoleOwner: true, RoleAdmin: true, RoleDeveloper: true},
+}
+
+func TestEndpointRoles(t *testing.T) {
+	for _, ep := range endpoints {
+		want, ok := expected[ep.action]
+		if !ok {
+			t.Fatalf("%s: no expectation for action %s", ep.endpoint, ep.action)
+		}
+		for _, role := range Roles {
+			if got := Allowed(role, ep.action); got != want[role] {
+				t.Errorf("%s as %s: allowed = %v, want %v", ep.endpoint, role, got, want[role])
+			}
+		}
+	}
+}
+
+func TestMatrixCoversExpected(t *testing.T) {
+	if len(matrix) != len(expected) {
+		t.Fatalf("matrix has %d actions, expected %d", len(matrix), len(expected))
+	}
+	for action := range matrix {
+		if _, ok := expected[action]; !ok {
+			t.Errorf("action %s has no expectation", action)
+		}
+	}
+}
+
+func TestUnknownRoleAndAction(t *testing.T) {
+	if Allowed("guest", ProjectView) {
+		t.Error("unknown role must not be allowed")
+	}
+	if Allowed(RoleOwner, Action("unknown")) {
+		t.Error("unknown action must not be allowed")
+	}
+	if ValidRole("guest") || !ValidRole(RoleViewer) {
+		t.Error("ValidRole mismatch")
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	if err := Check(RoleDeveloper, FileWrite); err != nil {
+		t.Fatalf("developer file.write: %v", err)
+	}
+	err := Check(RoleViewer, FileWrite)
+	var ce *errorx.CodeError
+	if !errors.As(err, &ce) {
+		t.Fatalf("want CodeError, got %v", err)
+	}
+	if ce.Status != http.StatusForbidden || ce.Code != "permission_denied" {
+		t.Errorf("unexpected error %+v", ce)
+	}
+	details, _ := ce.Details.(map[string]any)
+	if details["action"] != string(FileWrite) || details["role"] != RoleViewer {
+		t.Errorf("unexpected details %v", ce.Details)
+	}
+	if !IsDenied(err) || IsDenied(ErrNotMember) {
+		t.Error("IsDenied mismatch")
+	}
+}
+
+func TestValidPreviousOwnerRole(t *testing.T) {
+	for _, role := range []string{RoleAdmin, RoleDeveloper, RoleViewer, RoleNone} {
+		if !ValidPreviousOwnerRole(role) {
+			t.Errorf("%s should be valid", role)
+		}
+	}
+	for _, role := range []string{RoleOwner, "", "guest"} {
+		if ValidPreviousOwnerRole(role) {
+			t.Errorf("%q should be invalid", role)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package permissiontest 为权限相关测试提供只认识成员与项目状态查询（以及测试指定的表）的假数据库，以及按角色校验接口的辅助函数
+package permissiontest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/permission"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const driverName = "permissiontest"
+
+// ErrUnexpectedQuery 未列出的表上的任何 SQL 都返回该错误，表示请求已通过权限校验
+var ErrUnexpectedQuery = errors.New("permissiontest: unexpected query")
+
+// Table 查询该表时返回的结果，不区分查询条件；Rows 为空表示查不到记录
+type Table struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// Options 假数据库的内容
+type Options struct {
+	Role   string           // 查询 project_members 时返回的角色，为空表示不是成员
+	Status string           // 查询 projects 时返回的项目状态，为空表示未归档
+	Tables map[string]Table // 其它表的查询结果；对这些表的写入一律成功
+}
+
+var (
+	registerOnce sync.Once
+	databases    sync.Map
+	nextDSN      atomic.Int64
+)
+
+// OpenDB 返回一个 gorm 连接：查询 project_members 时返回角色为 role 的成员，role 为空表示不是成员；
+// 查询 projects 时返回未归档的项目
+func OpenDB(role string) (*gorm.DB, error) {
+	return Open(Options{Role: role})
+}
+
+// OpenProjectDB 同 OpenDB，查询 projects 时返回状态为 status 的项目
+func OpenProjectDB(role string, status string) (*gorm.DB, error) {
+	return Open(Options{Role: role, Status: status})
+}
+
+// Open 按 opts 返回一个 gorm 连接
+func Open(opts Options) (*gorm.DB, error) {
+	registerOnce.Do(func() {
+		sql.Register(driverName, fakeDriver{})
+	})
+	if opts.Status == "" {
+		opts.Status = "active"
+	}
+	dsn := strconv.FormatInt(nextDSN.Add(1), 10)
+	databases.Store(dsn, opts)
+	return gorm.Open(mysql.New(mysql.Config{
+		DriverName:                driverName,
+		DSN:                       dsn,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+}
+
+// CheckRoles 以每个角色及非成员（role 为空）调用 call 校验接口的角色限制：非成员须返回 permission.ErrNotMember，
+// allowed 中的角色不能返回权限错误（之后的失败不影响结果），其余角色须返回权限错误
+func CheckRoles(t testing.TB, allowed map[string]bool, call func(role string) error) {
+	t.Helper()
+	for _, role := range append(append([]string{}, permission.Roles...), "") {
+		err := call(role)
+		switch {
+		case role == "":
+			if !errors.Is(err, permission.ErrNotMember) {
+				t.Errorf("non-member: got %v", err)
+			}
+		case permission.IsDenied(err) == allowed[role]:
+			t.Errorf("%s: allowed=%v, got %v", role, allowed[role], err)
+		}
+	}
+}
+
+// statementTable 取出语句操作的主表
+var statementTable = regexp.MustCompile("(?i)\\b(?:FROM|UPDATE|INTO)\\s+`(\\w+)`")
+
+func tableOf(query string) string {
+	m := statementTable.FindStringSubmatch(query)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	opts, ok := databases.Load(name)
+	if !ok {
+		return nil, errors.New("permissiontest: unknown database " + name)
+	}
+	return &fakeConn{opts: opts.(Options)}, nil
+}
+
+type fakeConn struct {
+	opts Options
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, ErrUnexpectedQuery
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+// Begin 事务内的查询与事务外相同，提交和回滚都是空操作
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, ok := c.opts.Tables[tableOf(query)]; ok {
+		return fakeResult{}, nil
+	}
+	return nil, ErrUnexpectedQuery
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	table := tableOf(query)
+	switch table {
+	case "projects":
+		return &fakeRows{columns: []string{"status"}, values: [][]driver.Value{{c.opts.Status}}}, nil
+	case "project_members":
+		rows := &fakeRows{columns: []string{"project_id", "user_id", "role"}}
+		if c.opts.Role != "" && len(args) >= 2 {
+			rows.values = [][]driver.Value{{args[0].Value, args[1].Value, c.opts.Role}}
+		}
+		return rows, nil
+	}
+	if t, ok := c.opts.Tables[table]; ok {
+		return &fakeRows{columns: t.Columns, values: t.Rows}, nil
+	}
+	return nil, ErrUnexpectedQuery
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error { return nil }
+
+func (fakeTx) Rollback() error { return nil }
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) { return 1, nil }
+
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,136 @@
+package projectarchive
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ManifestName 导出包中描述项目结构的清单文件
+const ManifestName = "manifest.json"
+
+// FormatVersion 清单格式版本，导入时用于兼容判断
+const FormatVersion = 1
+
+const (
+	filesDir    = "files/"
+	versionsDir = "versions/"
+)
+
+// Manifest 项目导出清单；ID 均为导出时源库中的 ID，导入时需要重新映射
+type Manifest struct {
+	FormatVersion     int                `json:"formatVersion"`
+	ExportedAt        time.Time          `json:"exportedAt"`
+	Project           Project            `json:"project"`
+	Folders           []Folder           `json:"folders"`
+	Files             []File             `json:"files"`
+	Softwares         []Software         `json:"softwares"`
+	SoftwareManifests []SoftwareManifest `json:"softwareManifests"`
+	BuildVersions     []BuildVersion     `json:"buildVersions"`
+	Canvas            *Canvas            `json:"canvas,omitempty"`
+	Layers            []Layer            `json:"layers"`
+}
+
+type Project struct {
+	Id          uint64 `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CoverFileId uint64 `json:"coverFileId"`
+}
+
+type Folder struct {
+	Id       uint64 `json:"id"`
+	ParentId uint64 `json:"parentId"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+}
+
+type File struct {
+	Id               uint64    `json:"id"`
+	FolderId         uint64    `json:"folderId"`
+	Name             string    `json:"name"`
+	Path             string    `json:"path"`
+	FileCategory     string    `json:"fileCategory"`
+	FileFormat       string    `json:"fileFormat"`
+	CurrentVersionId uint64    `json:"currentVersionId"`
+	Entry            string    `json:"entry,omitempty"` // 当前版本在压缩包中的路径
+	Versions         []Version `json:"versions"`
+}
+
+type Version struct {
+	Id            uint64    `json:"id"`
+	VersionNumber uint64    `json:"versionNumber"`
+	SizeBytes     uint64    `json:"sizeBytes"`
+	Hash          string    `json:"hash"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Message       string    `json:"message,omitempty"`
+	Labels        []string  `json:"labels,omitempty"`
+	Entry         string    `json:"entry,omitempty"` // 仅导出全部版本时存在
+}
+
+type Software struct {
+	Id              uint64 `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	TemplateId      uint64 `json:"templateId"`
+	TechnologyStack string `json:"technologyStack"`
+	Status          string `json:"status"`
+}
+
+type SoftwareManifest struct {
+	Id                    uint64 `json:"id"`
+	SoftwareId            uint64 `json:"softwareId"`
+	ManifestFileId        uint64 `json:"manifestFileId"`
+	ManifestFileVersionId uint64 `json:"manifestFileVersionId"`
+	VersionNumber         uint32 `json:"versionNumber"`
+	VersionDescription    string `json:"versionDescription"`
+}
+
+type BuildVersion struct {
+	Id                        uint64 `json:"id"`
+	SoftwareManifestId        uint64 `json:"softwareManifestId"`
+	VersionNumber             uint32 `json:"versionNumber"`
+	Description               string `json:"description"`
+	BuildVersionFileId        uint64 `json:"buildVersionFileId"`
+	BuildVersionFileVersionId uint64 `json:"buildVersionFileVersionId"`
+	EntryPath                 string `json:"entryPath"`
+}
+
+type Canvas struct {
+	Id              uint64 `json:"id"`
+	Name            string `json:"name"`
+	BackgroundColor string `json:"backgroundColor"`
+	Metadata        string `json:"metadata"`
+}
+
+type Layer struct {
+	Id         uint64  `json:"id"`
+	LayerType  string  `json:"layerType"`
+	Name       string  `json:"name"`
+	ZIndex     int32   `json:"zIndex"`
+	PositionX  float64 `json:"positionX"`
+	PositionY  float64 `json:"positionY"`
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Rotation   float64 `json:"rotation"`
+	Visible    bool    `json:"visible"`
+	Locked     bool    `json:"locked"`
+	Properties string  `json:"properties"`
+	FileId     uint64  `json:"fileId"`
+}
+
+// CurrentEntry 文件当前版本在压缩包中的路径: files/<项目内路径>
+func CurrentEntry(filePath string) string {
+	return filesDir + filePath
+}
+
+// VersionEntry 历史版本在压缩包中的路径: versions/<目录>/<文件名>.v<版本号><扩展名>
+func VersionEntry(filePath string, versionNumber uint64) string {
+	ext := path.Ext(filePath)
+	stem := strings.TrimSuffix(filePath, ext)
+	if stem == "" || strings.HasSuffix(stem, "/") {
+		stem, ext = filePath, ""
+	}
+	return versionsDir + stem + ".v" + strconv.FormatUint(versionNumber, 10) + ext
+}
@@ -0,0 +1,63 @@
+package projectarchive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxManifestBytes manifest.json 的大小上限
+const maxManifestBytes = 64 << 20
+
+// Archive 已打开的导出包
+type Archive struct {
+	Manifest *Manifest
+	entries  map[string]*zip.File
+}
+
+// OpenArchive 读取 ZIP 目录与 manifest.json；没有清单的压缩包无法还原项目结构
+func OpenArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	arc := &Archive{entries: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		arc.entries[f.Name] = f
+	}
+	mf, ok := arc.entries[ManifestName]
+	if !ok {
+		return nil, errors.New("manifest.json not found in archive, export with manifest=true")
+	}
+	rc, err := mf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	var manifest Manifest
+	if err := json.NewDecoder(io.LimitReader(rc, maxManifestBytes)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest.json: %w", err)
+	}
+	if manifest.FormatVersion <= 0 || manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported manifest formatVersion %d", manifest.FormatVersion)
+	}
+	arc.Manifest = &manifest
+	return arc, nil
+}
+
+// Has 判断压缩包中是否存在条目
+func (a *Archive) Has(name string) bool {
+	_, ok := a.entries[name]
+	return ok
+}
+
+// Open 打开条目内容
+func (a *Archive) Open(name string) (io.ReadCloser, error) {
+	f, ok := a.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("archive entry %s not found", name)
+	}
+	return f.Open()
+}
@@ -0,0 +1,78 @@
+package projectarchive
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// RemapFileRefs 将清单类 JSON 文件（软件清单、构建版本清单）中的 fileId / versionId 按导入时的映射改写。
+// 内容不是 JSON 或没有需要改写的引用时返回 changed=false，调用方保留原内容
+func RemapFileRefs(data []byte, fileIds map[uint64]uint64, versionIds map[uint64]uint64) (out []byte, changed bool, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return data, false, nil
+	}
+	if !remapValue(doc, fileIds, versionIds) {
+		return data, false, nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+func remapValue(v any, fileIds map[uint64]uint64, versionIds map[uint64]uint64) bool {
+	changed := false
+	switch node := v.(type) {
+	case map[string]any:
+		for key, child := range node {
+			switch key {
+			case "fileId":
+				if id, ok := remapNumber(child, fileIds); ok {
+					node[key] = id
+					changed = true
+					continue
+				}
+			case "versionId":
+				if id, ok := remapNumber(child, versionIds); ok {
+					node[key] = id
+					changed = true
+					continue
+				}
+			}
+			if remapValue(child, fileIds, versionIds) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range node {
+			if remapValue(child, fileIds, versionIds) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func remapNumber(v any, ids map[uint64]uint64) (json.Number, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return "", false
+	}
+	old, err := n.Int64()
+	if err != nil || old <= 0 {
+		return "", false
+	}
+	id, ok := ids[uint64(old)]
+	if !ok || id == uint64(old) {
+		return "", false
+	}
+	b, _ := json.Marshal(id)
+	return json.Number(b), true
+}
@@ -0,0 +1,35 @@
+package projectarchive
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRemapFileRefs(t *testing.T) {
+	in := []byte(`{"entry":"index.html","files":[{"path":"index.html","fileId":10,"versionId":100,"versionNumber":2},{"path":"a.js","fileId":11,"versionId":999}]}`)
+	out, changed, err := RemapFileRefs(in, map[uint64]uint64{10: 20, 11: 21}, map[uint64]uint64{100: 200})
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	var doc struct {
+		Entry string `json:"entry"`
+		Files []struct {
+			FileId        uint64 `json:"fileId"`
+			VersionId     uint64 `json:"versionId"`
+			VersionNumber uint64 `json:"versionNumber"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Entry != "index.html" || doc.Files[0].FileId != 20 || doc.Files[0].VersionId != 200 || doc.Files[0].VersionNumber != 2 {
+		t.Fatalf("unexpected first entry %+v", doc)
+	}
+	if doc.Files[1].FileId != 21 || doc.Files[1].VersionId != 999 {
+		t.Fatalf("unmapped versionId should be kept: %+v", doc.Files[1])
+	}
+
+	if _, changed, _ := RemapFileRefs([]byte("not json"), nil, nil); changed {
+		t.Fatal("non-JSON content must be left unchanged")
+	}
+}
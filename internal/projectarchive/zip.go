@@ -0,0 +1,73 @@
+package projectarchive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/storage"
+)
+
+// Entry 压缩包中的一个对象条目
+type Entry struct {
+	Name       string
+	StorageKey string
+	Modified   time.Time
+}
+
+// Export 一次导出的内容；Manifest 为 nil 时不写入 manifest.json
+type Export struct {
+	FileName string
+	Entries  []Entry
+	Manifest *Manifest
+}
+
+// WriteZip 按条目逐个从存储读取对象并写入 ZIP，不落盘、不整体缓存；调用方负责设置响应头
+func WriteZip(ctx context.Context, w io.Writer, store storage.ObjectStore, export *Export) error {
+	zw := zip.NewWriter(w)
+	if export.Manifest != nil {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     ManifestName,
+			Method:   zip.Deflate,
+			Modified: export.Manifest.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(export.Manifest); err != nil {
+			return err
+		}
+	}
+	for _, entry := range export.Entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeEntry(ctx, zw, store, entry); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name, err)
+		}
+	}
+	return zw.Close()
+}
+
+func writeEntry(ctx context.Context, zw *zip.Writer, store storage.ObjectStore, entry Entry) error {
+	reader, err := store.GetObject(ctx, entry.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     entry.Name,
+		Method:   zip.Deflate,
+		Modified: entry.Modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, reader)
+	return err
+}
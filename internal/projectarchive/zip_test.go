@@ -0,0 +1,69 @@
+package projectarchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/storage"
+)
+
+func TestVersionEntry(t *testing.T) {
+	cases := map[string]string{
+		"src/main.go": "versions/src/main.v3.go",
+		"README":      "versions/README.v3",
+		"a/.env":      "versions/a/.env.v3",
+	}
+	for in, want := range cases {
+		if got := VersionEntry(in, 3); got != want {
+			t.Fatalf("VersionEntry(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost:8890", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := store.PutObject(ctx, "blobs/sha256/ab/abc", strings.NewReader("package main")); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+
+	var buf bytes.Buffer
+	export := &Export{
+		Entries:  []Entry{{Name: CurrentEntry("src/main.go"), StorageKey: "blobs/sha256/ab/abc", Modified: time.Now()}},
+		Manifest: &Manifest{FormatVersion: FormatVersion, Project: Project{Id: 1, Name: "demo"}},
+	}
+	if err := WriteZip(ctx, &buf, store, export); err != nil {
+		t.Fatalf("WriteZip: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		contents[f.Name] = string(data)
+	}
+	if contents["files/src/main.go"] != "package main" {
+		t.Fatalf("unexpected entry content %q", contents["files/src/main.go"])
+	}
+	var manifest Manifest
+	if err := json.Unmarshal([]byte(contents[ManifestName]), &manifest); err != nil || manifest.Project.Name != "demo" {
+		t.Fatalf("unexpected manifest %q: %v", contents[ManifestName], err)
+	}
+}
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrLocalMultipartNotFound = errors.New("local multipart upload not found")
+
+// localMultipartDir 分片暂存目录，位于 rootDir 下，每个 uploadId 一个子目录
+const localMultipartDir = ".multipart"
+
+func (s *LocalStore) uploadDir(uploadId string) (string, error) {
+	id := strings.TrimSpace(uploadId)
+	if id == "" {
+		return "", ErrLocalMultipartNotFound
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return "", ErrLocalMultipartNotFound
+	}
+	return filepath.Join(s.rootDir, localMultipartDir, id), nil
+}
+
+// openUpload 校验 uploadId 存在且属于该对象 key
+func (s *LocalStore) openUpload(objectKey string, uploadId string) (string, error) {
+	if _, err := s.objectPath(objectKey); err != nil {
+		return "", err
+	}
+	dir, err := s.uploadDir(uploadId)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "key"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrLocalMultipartNotFound
+		}
+		return "", err
+	}
+	if string(b) != objectKey {
+		return "", ErrLocalMultipartNotFound
+	}
+	return dir, nil
+}
+
+func localPartName(partNumber int) string {
+	return fmt.Sprintf("part-%05d", partNumber)
+}
+
+// signPart 分片上传签名，在对象签名串后附加 uploadId 与分片号
+func (s *LocalStore) signPart(objectKey string, uploadId string, partNumber int, expires int64) string {
+	stringToSign := "PUT\n" +
+		objectKey + "\n" +
+		strconv.FormatInt(expires, 10) + "\n" +
+		"\n" +
+		uploadId + "\n" +
+		strconv.Itoa(partNumber)
+	return hex.EncodeToString(hmacSha256([]byte(s.signSecret), stringToSign))
+}
+
+// VerifyPartSignature 校验分片上传预签名 URL
+func (s *LocalStore) VerifyPartSignature(objectKey string, uploadId string, partNumber int, expires int64, signature string) error {
+	if expires <= 0 || partNumber <= 0 || strings.TrimSpace(signature) == "" {
+		return ErrLocalSignatureInvalid
+	}
+	expected := s.signPart(objectKey, uploadId, partNumber, expires)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimSpace(signature)))) {
+		return ErrLocalSignatureInvalid
+	}
+	if time.Now().Unix() > expires {
+		return ErrLocalSignatureExpired
+	}
+	return nil
+}
+
+func (s *LocalStore) CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error) {
+	_ = ctx
+	_ = contentType
+	if _, err := s.objectPath(objectKey); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	uploadId := hex.EncodeToString(buf)
+	dir, err := s.uploadDir(uploadId)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "key"), []byte(objectKey), 0o644); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return uploadId, nil
+}
+
+func (s *LocalStore) PresignUploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, expiry time.Duration) (string, error) {
+	_ = ctx
+	if partNumber <= 0 {
+		return "", errors.New("invalid multipart upload part")
+	}
+	if _, err := s.openUpload(objectKey, uploadId); err != nil {
+		return "", err
+	}
+	expireSeconds := int64(expiry.Seconds())
+	if expireSeconds <= 0 {
+		expireSeconds = s.expireSeconds
+	}
+	if expireSeconds <= 0 {
+		expireSeconds = 1800
+	}
+	expires := time.Now().Unix() + expireSeconds
+
+	query := url.Values{}
+	query.Set("key", objectKey)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("uploadId", uploadId)
+	query.Set("partNumber", strconv.Itoa(partNumber))
+	query.Set("signature", s.signPart(objectKey, uploadId, partNumber, expires))
+	return s.baseURL + LocalObjectRoutePath + "?" + query.Encode(), nil
+}
+
+// PutPart 写入一个分片，同一分片号重复上传时覆盖，返回分片 ETag（内容 md5）
+func (s *LocalStore) PutPart(ctx context.Context, objectKey string, uploadId string, partNumber int, reader io.Reader) (*UploadedPart, error) {
+	_ = ctx
+	if partNumber <= 0 {
+		return nil, errors.New("invalid multipart upload part")
+	}
+	dir, err := s.openUpload(objectKey, uploadId)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, ".part-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmp.Name()
+	h := md5.New()
+	n, copyErr := io.Copy(io.MultiWriter(tmp, h), reader)
+	closeErr := tmp.Close()
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(tmpPath)
+		if copyErr != nil {
+			return nil, copyErr
+		}
+		return nil, closeErr
+	}
+	etag := hex.EncodeToString(h.Sum(nil))
+	name := localPartName(partNumber)
+	if err := os.WriteFile(filepath.Join(dir, name+".etag"), []byte(etag), 0o644); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, name)); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	return &UploadedPart{PartNumber: partNumber, ETag: etag, SizeBytes: n}, nil
+}
+
+func (s *LocalStore) ListUploadedParts(ctx context.Context, objectKey string, uploadId string) ([]UploadedPart, error) {
+	_ = ctx
+	dir, err := s.openUpload(objectKey, uploadId)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]UploadedPart, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "part-") || strings.HasSuffix(name, ".etag") {
+			continue
+		}
+		partNumber, err := strconv.Atoi(strings.TrimPrefix(name, "part-"))
+		if err != nil || partNumber <= 0 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		etag, err := os.ReadFile(filepath.Join(dir, name+".etag"))
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, UploadedPart{
+			PartNumber: partNumber,
+			ETag:       strings.TrimSpace(string(etag)),
+			SizeBytes:  info.Size(),
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// CompleteMultipartUpload 按分片号顺序合并分片，ETag 不一致时拒绝合并
+func (s *LocalStore) CompleteMultipartUpload(ctx context.Context, objectKey string, uploadId string, parts []UploadedPart) error {
+	if len(parts) == 0 {
+		return errors.New("no parts to complete")
+	}
+	dir, err := s.openUpload(objectKey, uploadId)
+	if err != nil {
+		return err
+	}
+	uploaded, err := s.ListUploadedParts(ctx, objectKey, uploadId)
+	if err != nil {
+		return err
+	}
+	etags := make(map[int]string, len(uploaded))
+	for _, p := range uploaded {
+		etags[p.PartNumber] = p.ETag
+	}
+
+	ordered := append([]UploadedPart(nil), parts...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].PartNumber < ordered[j].PartNumber })
+	for i, p := range ordered {
+		if i > 0 && ordered[i-1].PartNumber == p.PartNumber {
+			return fmt.Errorf("duplicate part %d", p.PartNumber)
+		}
+		etag, ok := etags[p.PartNumber]
+		if !ok {
+			return fmt.Errorf("part %d not uploaded", p.PartNumber)
+		}
+		if want := strings.Trim(strings.TrimSpace(p.ETag), `"`); want != "" && !strings.EqualFold(want, etag) {
+			return fmt.Errorf("part %d etag mismatch", p.PartNumber)
+		}
+	}
+
+	readers := make([]io.Reader, 0, len(ordered))
+	files := make([]*os.File, 0, len(ordered))
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for _, p := range ordered {
+		f, err := os.Open(filepath.Join(dir, localPartName(p.PartNumber)))
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+	if _, err := s.PutObject(ctx, objectKey, io.MultiReader(readers...)); err != nil {
+		return err
+	}
+	for _, f := range files {
+		_ = f.Close()
+	}
+	files = nil
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalStore) AbortMultipartUpload(ctx context.Context, objectKey string, uploadId string) error {
+	_ = ctx
+	dir, err := s.openUpload(objectKey, uploadId)
+	if err != nil {
+		if errors.Is(err, ErrLocalMultipartNotFound) {
+			return nil
+		}
+		return err
+	}
+	return os.RemoveAll(dir)
+}
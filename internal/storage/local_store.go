@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLocalSignatureInvalid = errors.New("local storage signature invalid")
+	ErrLocalSignatureExpired = errors.New("local storage signature expired")
+	ErrLocalObjectKeyInvalid = errors.New("local storage object key invalid")
+)
+
+// LocalObjectRoutePath 本地存储读写接口在 API 服务上的路径
+const LocalObjectRoutePath = "/api/v1/storage/local/object"
+
+// LocalStore 将对象保存在本地目录下，预签名 URL 由 API 服务自身的 HMAC 校验接口提供读写。
+type LocalStore struct {
+	rootDir       string
+	baseURL       string
+	signSecret    string
+	expireSeconds int64
+}
+
+func NewLocalStore(rootDir string, baseURL string, signSecret string, expireSeconds int64) (*LocalStore, error) {
+	root := strings.TrimSpace(rootDir)
+	if root == "" {
+		return nil, errors.New("empty local storage root dir")
+	}
+	if strings.TrimSpace(signSecret) == "" {
+		return nil, errors.New("empty local storage sign secret")
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absRoot, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{
+		rootDir:       absRoot,
+		baseURL:       strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		signSecret:    signSecret,
+		expireSeconds: expireSeconds,
+	}, nil
+}
+
+// objectPath 将对象 key 映射为 rootDir 下的文件路径，拒绝越界访问
+func (s *LocalStore) objectPath(objectKey string) (string, error) {
+	key := strings.TrimSpace(objectKey)
+	if key == "" || strings.Contains(key, "\\") || strings.HasPrefix(key, "/") {
+		return "", ErrLocalObjectKeyInvalid
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "." || seg == ".." {
+			return "", ErrLocalObjectKeyInvalid
+		}
+	}
+	// 分片暂存目录不允许作为对象访问
+	if strings.SplitN(key, "/", 2)[0] == localMultipartDir {
+		return "", ErrLocalObjectKeyInvalid
+	}
+	p := filepath.Join(s.rootDir, filepath.FromSlash(key))
+	if p != s.rootDir && !strings.HasPrefix(p, s.rootDir+string(os.PathSeparator)) {
+		return "", ErrLocalObjectKeyInvalid
+	}
+	return p, nil
+}
+
+func (s *LocalStore) sign(method string, objectKey string, expires int64, contentType string) string {
+	stringToSign := strings.ToUpper(method) + "\n" +
+		objectKey + "\n" +
+		strconv.FormatInt(expires, 10) + "\n" +
+		strings.TrimSpace(contentType)
+	return hex.EncodeToString(hmacSha256([]byte(s.signSecret), stringToSign))
+}
+
+func (s *LocalStore) presignURL(method string, objectKey string, contentType string, expiry time.Duration) (string, error) {
+	if _, err := s.objectPath(objectKey); err != nil {
+		return "", err
+	}
+	expireSeconds := int64(expiry.Seconds())
+	if expireSeconds <= 0 {
+		expireSeconds = s.expireSeconds
+	}
+	if expireSeconds <= 0 {
+		expireSeconds = 1800
+	}
+	expires := time.Now().Unix() + expireSeconds
+
+	query := url.Values{}
+	query.Set("key", objectKey)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if strings.EqualFold(method, "PUT") && strings.TrimSpace(contentType) != "" {
+		query.Set("contentType", strings.TrimSpace(contentType))
+	}
+	query.Set("signature", s.sign(method, objectKey, expires, contentType))
+	return s.baseURL + LocalObjectRoutePath + "?" + query.Encode(), nil
+}
+
+// VerifySignature 校验预签名 URL 的方法、过期时间与签名
+func (s *LocalStore) VerifySignature(method string, objectKey string, expires int64, contentType string, signature string) error {
+	if expires <= 0 || strings.TrimSpace(signature) == "" {
+		return ErrLocalSignatureInvalid
+	}
+	if !strings.EqualFold(method, "PUT") {
+		contentType = ""
+	}
+	expected := s.sign(method, objectKey, expires, contentType)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimSpace(signature)))) {
+		return ErrLocalSignatureInvalid
+	}
+	if time.Now().Unix() > expires {
+		return ErrLocalSignatureExpired
+	}
+	return nil
+}
+
+func (s *LocalStore) PresignPutObject(ctx context.Context, objectKey string, contentType string, expiry time.Duration) (string, error) {
+	_ = ctx
+	return s.presignURL("PUT", objectKey, contentType, expiry)
+}
+
+func (s *LocalStore) PresignGetObject(ctx context.Context, objectKey string, expiry time.Duration) (string, error) {
+	_ = ctx
+	return s.presignURL("GET", objectKey, "", expiry)
+}
+
+func (s *LocalStore) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	_ = ctx
+	p, err := s.objectPath(objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (s *LocalStore) GetObjectRange(ctx context.Context, objectKey string, offset int64, length int64) (io.ReadCloser, error) {
+	_ = ctx
+	p, err := s.objectPath(objectKey)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return limitReadCloser(f, length), nil
+}
+
+// PutObject 写入对象，先写临时文件再重命名，避免读到写了一半的内容
+func (s *LocalStore) PutObject(ctx context.Context, objectKey string, reader io.Reader) (int64, error) {
+	_ = ctx
+	p, err := s.objectPath(objectKey)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := tmp.Name()
+	n, copyErr := io.Copy(tmp, reader)
+	closeErr := tmp.Close()
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(tmpPath)
+		if copyErr != nil {
+			return 0, copyErr
+		}
+		return 0, closeErr
+	}
+	if err := os.Rename(tmpPath, p); err != nil {
+		_ = os.Remove(tmpPath)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *LocalStore) CopyObject(ctx context.Context, srcKey string, dstKey string) error {
+	src, err := s.GetObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	_, err = s.PutObject(ctx, dstKey, src)
+	return err
+}
+
+func (s *LocalStore) DeleteObject(ctx context.Context, objectKey string) error {
+	_ = ctx
+	p, err := s.objectPath(objectKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) StatObject(ctx context.Context, objectKey string) (*ObjectStat, error) {
+	_ = ctx
+	p, err := s.objectPath(objectKey)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, os.ErrNotExist
+	}
+	return &ObjectStat{
+		ContentType:  mime.TypeByExtension(path.Ext(objectKey)),
+		SizeBytes:    info.Size(),
+		ETag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// ListObjects 遍历本地目录，跳过分片暂存目录与写入中的临时文件
+func (s *LocalStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	err := filepath.WalkDir(s.rootDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		rel, err := filepath.Rel(s.rootDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if d.IsDir() {
+			if key == localMultipartDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".tmp") {
+			return nil
+		}
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			SizeBytes:    info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
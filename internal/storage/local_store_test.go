@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStore_PutGetStatDelete(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "http://localhost:8890", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "1/2/assets/abcd_wxyz.txt"
+
+	n, err := s.PutObject(ctx, key, strings.NewReader("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("PutObject n=%d err=%v", n, err)
+	}
+
+	stat, err := s.StatObject(ctx, key)
+	if err != nil {
+		t.Fatalf("StatObject: %v", err)
+	}
+	if stat.SizeBytes != 5 || !strings.HasPrefix(stat.ContentType, "text/plain") {
+		t.Fatalf("unexpected stat %+v", stat)
+	}
+
+	reader, err := s.GetObject(ctx, key)
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	b, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(b) != "hello" {
+		t.Fatalf("unexpected content %q", string(b))
+	}
+
+	if err := s.CopyObject(ctx, key, "blobs/sha256/aa/copy"); err != nil {
+		t.Fatalf("CopyObject: %v", err)
+	}
+	if stat, err := s.StatObject(ctx, "blobs/sha256/aa/copy"); err != nil || stat.SizeBytes != 5 {
+		t.Fatalf("copied stat=%+v err=%v", stat, err)
+	}
+
+	if err := s.DeleteObject(ctx, key); err != nil {
+		t.Fatalf("DeleteObject: %v", err)
+	}
+	if err := s.DeleteObject(ctx, key); err != nil {
+		t.Fatalf("DeleteObject on missing object: %v", err)
+	}
+	if _, err := s.StatObject(ctx, key); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a/./b", `a\b`} {
+		if _, err := s.PutObject(context.Background(), key, strings.NewReader("x")); !errors.Is(err, ErrLocalObjectKeyInvalid) {
+			t.Fatalf("key %q: expected ErrLocalObjectKeyInvalid, got %v", key, err)
+		}
+	}
+}
+
+func TestLocalStore_PresignAndVerify(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "http://localhost:8890/", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+
+	raw, err := s.PresignPutObject(ctx, "a/b c.png", "image/png", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPutObject: %v", err)
+	}
+	if !strings.HasPrefix(raw, "http://localhost:8890"+LocalObjectRoutePath+"?") {
+		t.Fatalf("unexpected url %s", raw)
+	}
+	u, _ := url.Parse(raw)
+	q := u.Query()
+	expires, _ := strconv.ParseInt(q.Get("expires"), 10, 64)
+
+	if err := s.VerifySignature("PUT", q.Get("key"), expires, q.Get("contentType"), q.Get("signature")); err != nil {
+		t.Fatalf("expected valid PUT signature, got %v", err)
+	}
+	if err := s.VerifySignature("GET", q.Get("key"), expires, "", q.Get("signature")); !errors.Is(err, ErrLocalSignatureInvalid) {
+		t.Fatalf("PUT signature must not authorize GET, got %v", err)
+	}
+	if err := s.VerifySignature("PUT", q.Get("key"), expires, "text/html", q.Get("signature")); !errors.Is(err, ErrLocalSignatureInvalid) {
+		t.Fatalf("content type must be bound to signature, got %v", err)
+	}
+	if err := s.VerifySignature("PUT", "a/other.png", expires, q.Get("contentType"), q.Get("signature")); !errors.Is(err, ErrLocalSignatureInvalid) {
+		t.Fatalf("key must be bound to signature, got %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute).Unix()
+	sig := s.sign("GET", "a/b.png", past, "")
+	if err := s.VerifySignature("GET", "a/b.png", past, "", sig); !errors.Is(err, ErrLocalSignatureExpired) {
+		t.Fatalf("expected expired, got %v", err)
+	}
+}
+
+func TestLocalStore_Multipart(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "http://localhost:8890", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "1/2/assets/big.bin"
+
+	uploadId, err := s.CreateMultipartUpload(ctx, key, "application/octet-stream")
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload: %v", err)
+	}
+	if _, err := s.PutPart(ctx, "1/2/assets/other.bin", uploadId, 1, strings.NewReader("x")); !errors.Is(err, ErrLocalMultipartNotFound) {
+		t.Fatalf("uploadId must be bound to key, got %v", err)
+	}
+	if _, err := s.PutPart(ctx, key, uploadId, 2, strings.NewReader("world")); err != nil {
+		t.Fatalf("PutPart 2: %v", err)
+	}
+	if _, err := s.PutPart(ctx, key, uploadId, 1, strings.NewReader("hello ")); err != nil {
+		t.Fatalf("PutPart 1: %v", err)
+	}
+
+	parts, err := s.ListUploadedParts(ctx, key, uploadId)
+	if err != nil || len(parts) != 2 || parts[0].PartNumber != 1 || parts[1].SizeBytes != 5 {
+		t.Fatalf("ListUploadedParts parts=%+v err=%v", parts, err)
+	}
+	if err := s.CompleteMultipartUpload(ctx, key, uploadId, []UploadedPart{{PartNumber: 1, ETag: "bad"}, parts[1]}); err == nil {
+		t.Fatalf("expected etag mismatch")
+	}
+	if err := s.CompleteMultipartUpload(ctx, key, uploadId, parts); err != nil {
+		t.Fatalf("CompleteMultipartUpload: %v", err)
+	}
+
+	reader, err := s.GetObject(ctx, key)
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	b, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(b) != "hello world" {
+		t.Fatalf("unexpected content %q", string(b))
+	}
+	if _, err := s.ListUploadedParts(ctx, key, uploadId); !errors.Is(err, ErrLocalMultipartNotFound) {
+		t.Fatalf("upload should be cleaned up, got %v", err)
+	}
+	if _, err := s.GetObject(ctx, localMultipartDir+"/"+uploadId+"/key"); !errors.Is(err, ErrLocalObjectKeyInvalid) {
+		t.Fatalf("staging dir must not be addressable, got %v", err)
+	}
+}
+
+func TestLocalStore_ListObjects(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"blobs/sha256/ab/abcd", "previews/1/2/3/index.html", "previews/1/2/3/js/app.js"} {
+		if _, err := s.PutObject(ctx, key, strings.NewReader(key)); err != nil {
+			t.Fatalf("PutObject %s: %v", key, err)
+		}
+	}
+	if _, err := s.CreateMultipartUpload(ctx, "blobs/sha256/cd/cdef", ""); err != nil {
+		t.Fatalf("CreateMultipartUpload: %v", err)
+	}
+
+	all, err := s.ListObjects(ctx, "")
+	if err != nil || len(all) != 3 {
+		t.Fatalf("ListObjects all=%+v err=%v", all, err)
+	}
+	previews, err := s.ListObjects(ctx, "previews/1/2/3/")
+	if err != nil || len(previews) != 2 {
+		t.Fatalf("ListObjects previews=%+v err=%v", previews, err)
+	}
+	if previews[0].Key != "previews/1/2/3/index.html" || previews[0].SizeBytes != int64(len(previews[0].Key)) {
+		t.Fatalf("unexpected object %+v", previews[0])
+	}
+}
+
+func TestServeObject_RangeAndConditional(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "blobs/sha256/ab/abcd"
+	if _, err := s.PutObject(ctx, key, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	content := &ObjectContent{
+		Key:          key,
+		ContentType:  "text/plain",
+		SizeBytes:    10,
+		ETag:         `"abcd"`,
+		LastModified: time.Now().Add(-time.Hour),
+	}
+	serve := func(header map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/content", nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		ServeObject(w, r, s, content)
+		return w
+	}
+
+	w := serve(nil)
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" || w.Header().Get("ETag") != `"abcd"` || w.Header().Get("Accept-Ranges") != "bytes" {
+		t.Fatalf("full response code=%d body=%q header=%v", w.Code, w.Body.String(), w.Header())
+	}
+	w = serve(map[string]string{"Range": "bytes=2-5"})
+	if w.Code != http.StatusPartialContent || w.Body.String() != "2345" || w.Header().Get("Content-Range") != "bytes 2-5/10" {
+		t.Fatalf("range response code=%d body=%q header=%v", w.Code, w.Body.String(), w.Header())
+	}
+	w = serve(map[string]string{"Range": "bytes=-3"})
+	if w.Code != http.StatusPartialContent || w.Body.String() != "789" {
+		t.Fatalf("suffix range code=%d body=%q", w.Code, w.Body.String())
+	}
+	w = serve(map[string]string{"Range": "bytes=20-"})
+	if w.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("expected 416, got %d", w.Code)
+	}
+	w = serve(map[string]string{"If-None-Match": `"abcd"`})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected 304, got %d", w.Code)
+	}
+	w = serve(map[string]string{"If-Modified-Since": time.Now().UTC().Format(http.TimeFormat)})
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for If-Modified-Since, got %d", w.Code)
+	}
+	w = serve(map[string]string{"Range": "bytes=0-1", "If-Range": `"other"`})
+	if w.Code != http.StatusOK || w.Body.Len() != 10 {
+		t.Fatalf("stale If-Range must return full content, got %d", w.Code)
+	}
+}
@@ -7,15 +7,45 @@ import (
 )
 
 type ObjectStat struct {
-	ContentType string
-	SizeBytes   int64
+	ContentType  string
+	SizeBytes    int64
+	ETag         string // 存储返回的 ETag，带引号
+	LastModified time.Time
+}
+
+// ObjectInfo 列举对象时返回的对象信息
+type ObjectInfo struct {
+	Key          string
+	SizeBytes    int64
+	LastModified time.Time
+}
+
+// UploadedPart 分片上传中已上传的分片
+type UploadedPart struct {
+	PartNumber int
+	ETag       string
+	SizeBytes  int64
 }
 
 type ObjectStore interface {
 	PresignPutObject(ctx context.Context, objectKey string, contentType string, expiry time.Duration) (string, error)
 	PresignGetObject(ctx context.Context, objectKey string, expiry time.Duration) (string, error)
 	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, error)
+	// GetObjectRange 读取从 offset 开始的 length 字节，length < 0 表示读到对象末尾
+	GetObjectRange(ctx context.Context, objectKey string, offset int64, length int64) (io.ReadCloser, error)
+	// PutObject 由服务端直接写入对象（如缩略图），Content-Type 按 key 扩展名推断
+	PutObject(ctx context.Context, objectKey string, reader io.Reader) (int64, error)
+	// CopyObject 在存储内部复制对象，内容不经过 API 服务
+	CopyObject(ctx context.Context, srcKey string, dstKey string) error
 	DeleteObject(ctx context.Context, objectKey string) error
 	StatObject(ctx context.Context, objectKey string) (*ObjectStat, error)
-}
+	// ListObjects 列举指定前缀下的全部对象，prefix 为空时列举整个 bucket
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
 
+	// 分片上传：初始化后客户端按分片号获取预签名 URL 直传，完成时按分片号顺序合并
+	CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error)
+	PresignUploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, expiry time.Duration) (string, error)
+	ListUploadedParts(ctx context.Context, objectKey string, uploadId string) ([]UploadedPart, error)
+	CompleteMultipartUpload(ctx context.Context, objectKey string, uploadId string, parts []UploadedPart) error
+	AbortMultipartUpload(ctx context.Context, objectKey string, uploadId string) error
+}
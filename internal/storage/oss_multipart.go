@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func (s *OSSStore) multipartResult(objectKey string, uploadId string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{
+		Bucket:   s.bucket,
+		Key:      objectKey,
+		UploadID: uploadId,
+	}
+}
+
+func (s *OSSStore) CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error) {
+	_ = ctx
+	if s.bucketClient == nil {
+		return "", errors.New("OSS not configured")
+	}
+	options := make([]oss.Option, 0, 1)
+	if strings.TrimSpace(contentType) != "" {
+		options = append(options, oss.ContentType(strings.TrimSpace(contentType)))
+	}
+	imur, err := s.bucketClient.InitiateMultipartUpload(objectKey, options...)
+	if err != nil {
+		return "", err
+	}
+	return imur.UploadID, nil
+}
+
+func (s *OSSStore) PresignUploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, expiry time.Duration) (string, error) {
+	_ = ctx
+	if s.bucketClient == nil {
+		return "", errors.New("OSS not configured")
+	}
+	if strings.TrimSpace(uploadId) == "" || partNumber <= 0 {
+		return "", errors.New("invalid multipart upload part")
+	}
+	expireSeconds := int64(expiry.Seconds())
+	if expireSeconds <= 0 {
+		expireSeconds = s.expireSeconds
+	}
+	return s.bucketClient.SignURL(objectKey, oss.HTTPPut, expireSeconds,
+		oss.AddParam("partNumber", strconv.Itoa(partNumber)),
+		oss.AddParam("uploadId", uploadId),
+	)
+}
+
+func (s *OSSStore) uploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, body []byte) (UploadedPart, error) {
+	_ = ctx
+	part, err := s.bucketClient.UploadPart(s.multipartResult(objectKey, uploadId), bytes.NewReader(body), int64(len(body)), partNumber)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	return UploadedPart{PartNumber: part.PartNumber, ETag: strings.Trim(part.ETag, `"`), SizeBytes: int64(len(body))}, nil
+}
+
+func (s *OSSStore) uploadPartCopy(ctx context.Context, srcKey string, objectKey string, uploadId string, partNumber int, offset int64, length int64) (UploadedPart, error) {
+	_ = ctx
+	part, err := s.bucketClient.UploadPartCopy(s.multipartResult(objectKey, uploadId), s.bucket, srcKey, offset, length, partNumber)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	return UploadedPart{PartNumber: part.PartNumber, ETag: strings.Trim(part.ETag, `"`), SizeBytes: length}, nil
+}
+
+func (s *OSSStore) ListUploadedParts(ctx context.Context, objectKey string, uploadId string) ([]UploadedPart, error) {
+	_ = ctx
+	if s.bucketClient == nil {
+		return nil, errors.New("OSS not configured")
+	}
+	imur := s.multipartResult(objectKey, uploadId)
+	parts := make([]UploadedPart, 0)
+	marker := 0
+	for {
+		result, err := s.bucketClient.ListUploadedParts(imur, oss.MaxParts(1000), oss.PartNumberMarker(marker))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.UploadedParts {
+			parts = append(parts, UploadedPart{
+				PartNumber: p.PartNumber,
+				ETag:       strings.Trim(p.ETag, `"`),
+				SizeBytes:  int64(p.Size),
+			})
+		}
+		next, _ := strconv.Atoi(strings.TrimSpace(result.NextPartNumberMarker))
+		if !result.IsTruncated || next <= marker {
+			break
+		}
+		marker = next
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+func (s *OSSStore) CompleteMultipartUpload(ctx context.Context, objectKey string, uploadId string, parts []UploadedPart) error {
+	_ = ctx
+	if s.bucketClient == nil {
+		return errors.New("OSS not configured")
+	}
+	if len(parts) == 0 {
+		return errors.New("no parts to complete")
+	}
+	uploadParts := make([]oss.UploadPart, 0, len(parts))
+	for _, p := range parts {
+		uploadParts = append(uploadParts, oss.UploadPart{
+			PartNumber: p.PartNumber,
+			ETag:       `"` + strings.Trim(p.ETag, `"`) + `"`,
+		})
+	}
+	sort.Sort(oss.UploadParts(uploadParts))
+	_, err := s.bucketClient.CompleteMultipartUpload(s.multipartResult(objectKey, uploadId), uploadParts)
+	return err
+}
+
+func (s *OSSStore) AbortMultipartUpload(ctx context.Context, objectKey string, uploadId string) error {
+	_ = ctx
+	if s.bucketClient == nil {
+		return errors.New("OSS not configured")
+	}
+	return s.bucketClient.AbortMultipartUpload(s.multipartResult(objectKey, uploadId))
+}
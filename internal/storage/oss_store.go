@@ -1,13 +1,17 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
 	"errors"
 	"io"
+	"mime"
+	"net/http"
 	"net/url"
+	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -23,8 +27,15 @@ type OSSStore struct {
 	expireSeconds   int64
 
 	bucketClient *oss.Bucket
+
+	putPartSize  int64 // PutObject 超过该大小时分片上传
+	maxCopySize  int64 // 单次 CopyObject 的上限，超过时分片复制
+	copyPartSize int64
 }
 
+// ossMaxCopySize OSS 单次 CopyObject 最大 1GB
+const ossMaxCopySize = 1 << 30
+
 func NewOSSStore(bucketClient *oss.Bucket, endpoint, bucket, accessKeyId, accessKeySecret string, expireSeconds int64) *OSSStore {
 	return &OSSStore{
 		endpoint:        endpoint,
@@ -33,6 +44,9 @@ func NewOSSStore(bucketClient *oss.Bucket, endpoint, bucket, accessKeyId, access
 		accessKeySecret: accessKeySecret,
 		expireSeconds:   expireSeconds,
 		bucketClient:    bucketClient,
+		putPartSize:     defaultPutPartSize,
+		maxCopySize:     ossMaxCopySize,
+		copyPartSize:    defaultCopyPartSize,
 	}
 }
 
@@ -65,6 +79,47 @@ func (s *OSSStore) GetObject(ctx context.Context, objectKey string) (io.ReadClos
 	return s.bucketClient.GetObject(objectKey)
 }
 
+func (s *OSSStore) GetObjectRange(ctx context.Context, objectKey string, offset int64, length int64) (io.ReadCloser, error) {
+	_ = ctx
+	if s.bucketClient == nil {
+		return nil, errors.New("OSS not configured")
+	}
+	return s.bucketClient.GetObject(objectKey, oss.NormalizedRange(strings.TrimPrefix(httpRange(offset, length), "bytes=")))
+}
+
+// PutObject 边读边写入，内容超过一个分片时改用分片上传，单个对象不受 PutObject 5GB 的限制
+func (s *OSSStore) PutObject(ctx context.Context, objectKey string, reader io.Reader) (int64, error) {
+	if s.bucketClient == nil {
+		return 0, errors.New("OSS not configured")
+	}
+	return putInParts(ctx, s, objectKey, reader, s.putPartSize)
+}
+
+func (s *OSSStore) putSingle(ctx context.Context, objectKey string, body []byte) error {
+	_ = ctx
+	options := []oss.Option{}
+	if contentType := mime.TypeByExtension(path.Ext(objectKey)); contentType != "" {
+		options = append(options, oss.ContentType(contentType))
+	}
+	return s.bucketClient.PutObject(objectKey, bytes.NewReader(body), options...)
+}
+
+// CopyObject 超过单次复制上限（1GB）时分片复制
+func (s *OSSStore) CopyObject(ctx context.Context, srcKey string, dstKey string) error {
+	if s.bucketClient == nil {
+		return errors.New("OSS not configured")
+	}
+	stat, err := s.StatObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	if stat.SizeBytes > s.maxCopySize {
+		return copyInParts(ctx, s, srcKey, dstKey, stat.SizeBytes, s.copyPartSize)
+	}
+	_, err = s.bucketClient.CopyObject(srcKey, dstKey)
+	return err
+}
+
 func (s *OSSStore) DeleteObject(ctx context.Context, objectKey string) error {
 	_ = ctx
 	if s.bucketClient == nil {
@@ -87,6 +142,10 @@ func (s *OSSStore) StatObject(ctx context.Context, objectKey string) (*ObjectSta
 	}
 	stat := &ObjectStat{
 		ContentType: strings.TrimSpace(meta.Get("Content-Type")),
+		ETag:        strings.TrimSpace(meta.Get("ETag")),
+	}
+	if t, err := http.ParseTime(meta.Get("Last-Modified")); err == nil {
+		stat.LastModified = t
 	}
 	if rawLen := strings.TrimSpace(meta.Get("Content-Length")); rawLen != "" {
 		if n, parseErr := strconv.ParseInt(rawLen, 10, 64); parseErr == nil {
@@ -168,3 +227,33 @@ func presignOSSPutURL(endpoint, bucket, accessKeyId, accessKeySecret, objectKey,
 		"&Signature=" + url.QueryEscape(signature), nil
 }
 
+func (s *OSSStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	_ = ctx
+	if s.bucketClient == nil {
+		return nil, errors.New("OSS not configured")
+	}
+	objects := make([]ObjectInfo, 0)
+	token := ""
+	for {
+		options := []oss.Option{oss.Prefix(prefix), oss.MaxKeys(1000)}
+		if token != "" {
+			options = append(options, oss.ContinuationToken(token))
+		}
+		result, err := s.bucketClient.ListObjectsV2(options...)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range result.Objects {
+			objects = append(objects, ObjectInfo{
+				Key:          o.Key,
+				SizeBytes:    o.Size,
+				LastModified: o.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	return objects, nil
+}
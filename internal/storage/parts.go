@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+)
+
+const (
+	// defaultPutPartSize 服务端写入对象时的分片大小：内容不超过一个分片时单次 PUT，否则分片上传，内存中最多缓存一个分片
+	defaultPutPartSize = 16 << 20
+	// defaultCopyPartSize 超过单次复制上限的对象按该大小分片复制
+	defaultCopyPartSize = 512 << 20
+	// maxUploadParts S3 与 OSS 单个分片上传最多 10000 个分片
+	maxUploadParts = 10000
+)
+
+// partWriter 对象存储按分片写入对象的能力，S3 与 OSS 各自实现
+type partWriter interface {
+	CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error)
+	CompleteMultipartUpload(ctx context.Context, objectKey string, uploadId string, parts []UploadedPart) error
+	AbortMultipartUpload(ctx context.Context, objectKey string, uploadId string) error
+	// putSingle 单次请求写入整个对象
+	putSingle(ctx context.Context, objectKey string, body []byte) error
+	// uploadPart 上传一个分片
+	uploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, body []byte) (UploadedPart, error)
+	// uploadPartCopy 把 srcKey 从 offset 开始的 length 字节复制为一个分片
+	uploadPartCopy(ctx context.Context, srcKey string, objectKey string, uploadId string, partNumber int, offset int64, length int64) (UploadedPart, error)
+}
+
+// putInParts 流式写入 reader 的内容：不超过 partSize 时单次写入，否则边读边按分片上传
+func putInParts(ctx context.Context, w partWriter, objectKey string, reader io.Reader, partSize int64) (int64, error) {
+	buf := make([]byte, partSize)
+	n, err := io.ReadFull(reader, buf)
+	if err == nil {
+		// 恰好读满一个分片时再读一个字节，判断是否还有后续内容
+		var next [1]byte
+		m, peekErr := io.ReadFull(reader, next[:])
+		if peekErr != nil && !errors.Is(peekErr, io.EOF) {
+			return 0, peekErr
+		}
+		if m == 0 {
+			err = io.EOF
+		}
+		reader = io.MultiReader(bytes.NewReader(next[:m]), reader)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		if err := w.putSingle(ctx, objectKey, buf[:n]); err != nil {
+			return 0, err
+		}
+		return int64(n), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	uploadId, err := w.CreateMultipartUpload(ctx, objectKey, mime.TypeByExtension(path.Ext(objectKey)))
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	parts := make([]UploadedPart, 0)
+	err = func() error {
+		for partNumber := 1; n > 0; partNumber++ {
+			if partNumber > maxUploadParts {
+				return errors.New("object too large")
+			}
+			part, err := w.uploadPart(ctx, objectKey, uploadId, partNumber, buf[:n])
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+			total += int64(n)
+
+			n, err = io.ReadFull(reader, buf)
+			if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return err
+			}
+		}
+		return w.CompleteMultipartUpload(ctx, objectKey, uploadId, parts)
+	}()
+	if err != nil {
+		_ = w.AbortMultipartUpload(context.WithoutCancel(ctx), objectKey, uploadId)
+		return 0, err
+	}
+	return total, nil
+}
+
+// copyInParts 用分片复制在存储内部复制超过单次复制上限的对象
+func copyInParts(ctx context.Context, w partWriter, srcKey string, dstKey string, size int64, partSize int64) error {
+	if (size+partSize-1)/partSize > maxUploadParts {
+		return errors.New("object too large")
+	}
+	uploadId, err := w.CreateMultipartUpload(ctx, dstKey, mime.TypeByExtension(path.Ext(dstKey)))
+	if err != nil {
+		return err
+	}
+	parts := make([]UploadedPart, 0, (size+partSize-1)/partSize)
+	err = func() error {
+		for offset, partNumber := int64(0), 1; offset < size; offset, partNumber = offset+partSize, partNumber+1 {
+			length := min(partSize, size-offset)
+			part, err := w.uploadPartCopy(ctx, srcKey, dstKey, uploadId, partNumber, offset, length)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+		}
+		return w.CompleteMultipartUpload(ctx, dstKey, uploadId, parts)
+	}()
+	if err != nil {
+		_ = w.AbortMultipartUpload(context.WithoutCancel(ctx), dstKey, uploadId)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type s3InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	UploadId string   `xml:"UploadId"`
+}
+
+type s3ListPartsResult struct {
+	XMLName              xml.Name `xml:"ListPartsResult"`
+	IsTruncated          bool     `xml:"IsTruncated"`
+	NextPartNumberMarker int      `xml:"NextPartNumberMarker"`
+	Parts                []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+		Size       int64  `xml:"Size"`
+	} `xml:"Part"`
+}
+
+type s3CopyPartResult struct {
+	XMLName xml.Name `xml:"CopyPartResult"`
+	ETag    string   `xml:"ETag"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+// doSigned 以预签名 URL 的方式直接请求 S3，适用于服务端发起的管理类请求
+func (s *S3Store) doSigned(ctx context.Context, method string, objectKey string, contentType string, extraQuery map[string]string, body []byte) (*http.Response, error) {
+	u, err := s.presignURLWithQuery(ctx, method, objectKey, contentType, time.Duration(s.expireSeconds)*time.Second, extraQuery)
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(contentType) != "" {
+		req.Header.Set("Content-Type", strings.TrimSpace(contentType))
+	}
+	return s.httpClient.Do(req)
+}
+
+// doSignedHeaders 同 doSigned，额外带上参与签名的请求头（如 x-amz-copy-source），不带请求体
+func (s *S3Store) doSignedHeaders(ctx context.Context, method string, objectKey string, extraQuery map[string]string, headers map[string]string) (*http.Response, error) {
+	u, err := s.presignURLWithHeaders(ctx, method, objectKey, "", time.Duration(s.expireSeconds)*time.Second, extraQuery, headers)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return s.httpClient.Do(req)
+}
+
+func readS3Error(action string, resp *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("s3 %s failed: status=%d body=%s", action, resp.StatusCode, strings.TrimSpace(string(b)))
+}
+
+func (s *S3Store) CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error) {
+	resp, err := s.doSigned(ctx, http.MethodPost, objectKey, contentType, map[string]string{"uploads": ""}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", readS3Error("create multipart upload", resp)
+	}
+
+	var result s3InitiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(result.UploadId) == "" {
+		return "", errors.New("s3 create multipart upload returned empty uploadId")
+	}
+	return result.UploadId, nil
+}
+
+func (s *S3Store) PresignUploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, expiry time.Duration) (string, error) {
+	if strings.TrimSpace(uploadId) == "" || partNumber <= 0 {
+		return "", errors.New("invalid multipart upload part")
+	}
+	return s.presignURLWithQuery(ctx, "PUT", objectKey, "", expiry, map[string]string{
+		"partNumber": strconv.Itoa(partNumber),
+		"uploadId":   uploadId,
+	})
+}
+
+func (s *S3Store) uploadPart(ctx context.Context, objectKey string, uploadId string, partNumber int, body []byte) (UploadedPart, error) {
+	resp, err := s.doSigned(ctx, http.MethodPut, objectKey, "", map[string]string{
+		"partNumber": strconv.Itoa(partNumber),
+		"uploadId":   uploadId,
+	}, body)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return UploadedPart{}, readS3Error("upload part", resp)
+	}
+	return UploadedPart{
+		PartNumber: partNumber,
+		ETag:       strings.Trim(resp.Header.Get("ETag"), `"`),
+		SizeBytes:  int64(len(body)),
+	}, nil
+}
+
+func (s *S3Store) uploadPartCopy(ctx context.Context, srcKey string, objectKey string, uploadId string, partNumber int, offset int64, length int64) (UploadedPart, error) {
+	resp, err := s.doSignedHeaders(ctx, http.MethodPut, objectKey, map[string]string{
+		"partNumber": strconv.Itoa(partNumber),
+		"uploadId":   uploadId,
+	}, map[string]string{
+		"x-amz-copy-source":       s.copySource(srcKey),
+		"x-amz-copy-source-range": fmt.Sprintf("bytes=%d-%d", offset, offset+length-1),
+	})
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return UploadedPart{}, readS3Error("upload part copy", resp)
+	}
+	// 与 CopyObject 相同，复制失败时也可能返回 200，此时响应体中没有 CopyPartResult
+	var result s3CopyPartResult
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result); err != nil {
+		return UploadedPart{}, fmt.Errorf("s3 upload part copy failed: %w", err)
+	}
+	if result.ETag == "" {
+		return UploadedPart{}, errors.New("s3 upload part copy returned empty ETag")
+	}
+	return UploadedPart{
+		PartNumber: partNumber,
+		ETag:       strings.Trim(result.ETag, `"`),
+		SizeBytes:  length,
+	}, nil
+}
+
+func (s *S3Store) ListUploadedParts(ctx context.Context, objectKey string, uploadId string) ([]UploadedPart, error) {
+	parts := make([]UploadedPart, 0)
+	marker := 0
+	for {
+		query := map[string]string{"uploadId": uploadId}
+		if marker > 0 {
+			query["part-number-marker"] = strconv.Itoa(marker)
+		}
+		resp, err := s.doSigned(ctx, http.MethodGet, objectKey, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := readS3Error("list parts", resp)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		var result s3ListPartsResult
+		decodeErr := xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		for _, p := range result.Parts {
+			parts = append(parts, UploadedPart{
+				PartNumber: p.PartNumber,
+				ETag:       strings.Trim(p.ETag, `"`),
+				SizeBytes:  p.Size,
+			})
+		}
+		if !result.IsTruncated || result.NextPartNumberMarker <= marker {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+func (s *S3Store) CompleteMultipartUpload(ctx context.Context, objectKey string, uploadId string, parts []UploadedPart) error {
+	if len(parts) == 0 {
+		return errors.New("no parts to complete")
+	}
+	payload := s3CompleteMultipartUpload{Parts: make([]s3CompletedPart, 0, len(parts))}
+	for _, p := range parts {
+		payload.Parts = append(payload.Parts, s3CompletedPart{
+			PartNumber: p.PartNumber,
+			ETag:       `"` + strings.Trim(p.ETag, `"`) + `"`,
+		})
+	}
+	sort.Slice(payload.Parts, func(i, j int) bool { return payload.Parts[i].PartNumber < payload.Parts[j].PartNumber })
+	body, err := xml.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.doSigned(ctx, http.MethodPost, objectKey, "", map[string]string{"uploadId": uploadId}, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readS3Error("complete multipart upload", resp)
+	}
+	// S3 可能在 200 响应体中返回错误
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if bytes.Contains(b, []byte("<Error>")) {
+		return fmt.Errorf("s3 complete multipart upload failed: body=%s", strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+func (s *S3Store) AbortMultipartUpload(ctx context.Context, objectKey string, uploadId string) error {
+	resp, err := s.doSigned(ctx, http.MethodDelete, objectKey, "", map[string]string{"uploadId": uploadId}, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == 204 || resp.StatusCode == 200 || resp.StatusCode == 404 {
+		return nil
+	}
+	return readS3Error("abort multipart upload", resp)
+}
@@ -5,11 +5,14 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,8 +28,15 @@ type S3Store struct {
 	bucket        string
 	expireSeconds int64
 	httpClient    *http.Client
+
+	putPartSize  int64 // PutObject 超过该大小时分片上传
+	maxCopySize  int64 // 单次 CopyObject 的上限，超过时分片复制
+	copyPartSize int64
 }
 
+// s3MaxCopySize S3 单次 CopyObject 最大 5GB
+const s3MaxCopySize = 5 << 30
+
 func NewS3Store(endpoint string, useSSL bool, region string, accessKeyId string, accessKeySecret string, bucket string, expireSeconds int64) (*S3Store, error) {
 	scheme, host, err := normalizeS3Endpoint(endpoint, useSSL)
 	if err != nil {
@@ -41,6 +51,9 @@ func NewS3Store(endpoint string, useSSL bool, region string, accessKeyId string,
 		bucket:        strings.TrimSpace(bucket),
 		expireSeconds: expireSeconds,
 		httpClient:    http.DefaultClient,
+		putPartSize:   defaultPutPartSize,
+		maxCopySize:   s3MaxCopySize,
+		copyPartSize:  defaultCopyPartSize,
 	}, nil
 }
 
@@ -101,6 +114,16 @@ func hmacSha256(key []byte, data string) []byte {
 }
 
 func (s *S3Store) presignURL(ctx context.Context, method string, objectKey string, contentType string, expiry time.Duration) (string, error) {
+	return s.presignURLWithQuery(ctx, method, objectKey, contentType, expiry, nil)
+}
+
+// presignURLWithQuery 在签名中附带子资源参数，如分片上传的 uploadId / partNumber
+func (s *S3Store) presignURLWithQuery(ctx context.Context, method string, objectKey string, contentType string, expiry time.Duration, extraQuery map[string]string) (string, error) {
+	return s.presignURLWithHeaders(ctx, method, objectKey, contentType, expiry, extraQuery, nil)
+}
+
+// presignURLWithHeaders 额外对请求头签名（如 x-amz-copy-source），请求时必须带上相同的头
+func (s *S3Store) presignURLWithHeaders(ctx context.Context, method string, objectKey string, contentType string, expiry time.Duration, extraQuery map[string]string, extraHeaders map[string]string) (string, error) {
 	_ = ctx
 	if s.host == "" || s.bucket == "" || s.accessKeyId == "" || strings.TrimSpace(s.accessSecret) == "" {
 		return "", errors.New("S3 not configured")
@@ -129,13 +152,24 @@ func (s *S3Store) presignURL(ctx context.Context, method string, objectKey strin
 	escapedKey := escapeS3ObjectKeyPath(objectKey)
 	canonicalURI := "/" + awsQueryEscape(s.bucket) + "/" + escapedKey
 
-	signedHeaders := "host"
-	canonicalHeaders := "host:" + s.host + "\n"
+	headers := map[string]string{"host": s.host}
 	ct := strings.TrimSpace(contentType)
 	if strings.EqualFold(method, "PUT") && ct != "" {
-		signedHeaders = "content-type;host"
-		canonicalHeaders = "content-type:" + ct + "\n" + canonicalHeaders
+		headers["content-type"] = ct
+	}
+	for k, v := range extraHeaders {
+		headers[strings.ToLower(k)] = strings.TrimSpace(v)
+	}
+	headerNames := make([]string, 0, len(headers))
+	for k := range headers {
+		headerNames = append(headerNames, k)
+	}
+	sort.Strings(headerNames)
+	canonicalHeaders := ""
+	for _, k := range headerNames {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
 	}
+	signedHeaders := strings.Join(headerNames, ";")
 
 	credentialScope := dateStamp + "/" + region + "/s3/aws4_request"
 	credential := s.accessKeyId + "/" + credentialScope
@@ -147,6 +181,9 @@ func (s *S3Store) presignURL(ctx context.Context, method string, objectKey strin
 		"X-Amz-Expires":       strconv.FormatInt(expiresSeconds, 10),
 		"X-Amz-SignedHeaders": signedHeaders,
 	}
+	for k, v := range extraQuery {
+		query[k] = v
+	}
 
 	keys := make([]string, 0, len(query))
 	for k := range query {
@@ -210,6 +247,82 @@ func (s *S3Store) GetObject(ctx context.Context, objectKey string) (io.ReadClose
 	return resp.Body, nil
 }
 
+func (s *S3Store) GetObjectRange(ctx context.Context, objectKey string, offset int64, length int64) (io.ReadCloser, error) {
+	u, err := s.presignURL(ctx, "GET", objectKey, "", time.Duration(s.expireSeconds)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", httpRange(offset, length))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		return resp.Body, nil
+	case resp.StatusCode == http.StatusOK:
+		// 服务端忽略 Range 时自行跳过前面的内容
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		return limitReadCloser(resp.Body, length), nil
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, readS3Error("get range", resp)
+	}
+}
+
+// PutObject 边读边写入，内容超过一个分片时改用分片上传，不在内存中缓存整个对象
+func (s *S3Store) PutObject(ctx context.Context, objectKey string, reader io.Reader) (int64, error) {
+	return putInParts(ctx, s, objectKey, reader, s.putPartSize)
+}
+
+func (s *S3Store) putSingle(ctx context.Context, objectKey string, body []byte) error {
+	resp, err := s.doSigned(ctx, http.MethodPut, objectKey, mime.TypeByExtension(path.Ext(objectKey)), nil, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readS3Error("put object", resp)
+	}
+	return nil
+}
+
+// CopyObject 使用 S3 服务端复制，内容不经过 API 服务；超过单次复制上限时分片复制
+func (s *S3Store) CopyObject(ctx context.Context, srcKey string, dstKey string) error {
+	stat, err := s.StatObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	if stat.SizeBytes > s.maxCopySize {
+		return copyInParts(ctx, s, srcKey, dstKey, stat.SizeBytes, s.copyPartSize)
+	}
+	resp, err := s.doSignedHeaders(ctx, http.MethodPut, dstKey, nil, map[string]string{"x-amz-copy-source": s.copySource(srcKey)})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return readS3Error("copy object", resp)
+	}
+	// 复制失败时 S3 也可能返回 200 并在响应体中给出错误
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if strings.Contains(string(b), "<Error>") {
+		return fmt.Errorf("s3 copy object failed: body=%s", strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+func (s *S3Store) copySource(srcKey string) string {
+	return "/" + s.bucket + "/" + escapeS3ObjectKeyPath(srcKey)
+}
+
 func (s *S3Store) DeleteObject(ctx context.Context, objectKey string) error {
 	u, err := s.presignURL(ctx, "DELETE", objectKey, "", time.Duration(s.expireSeconds)*time.Second)
 	if err != nil {
@@ -256,8 +369,66 @@ func (s *S3Store) StatObject(ctx context.Context, objectKey string) (*ObjectStat
 			sizeBytes = n
 		}
 	}
-	return &ObjectStat{
+	stat := &ObjectStat{
 		ContentType: strings.TrimSpace(resp.Header.Get("Content-Type")),
 		SizeBytes:   sizeBytes,
-	}, nil
+		ETag:        strings.TrimSpace(resp.Header.Get("ETag")),
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		stat.LastModified = t
+	}
+	return stat, nil
+}
+
+type s3ListBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+func (s *S3Store) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	token := ""
+	for {
+		query := map[string]string{
+			"list-type": "2",
+			"max-keys":  "1000",
+			"prefix":    prefix,
+		}
+		if token != "" {
+			query["continuation-token"] = token
+		}
+		resp, err := s.doSigned(ctx, http.MethodGet, "", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := readS3Error("list objects", resp)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		var result s3ListBucketResult
+		decodeErr := xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		for _, c := range result.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          c.Key,
+				SizeBytes:    c.Size,
+				LastModified: c.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	return objects, nil
 }
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 只实现 PutObject / CopyObject 用到的对象与分片接口，不校验签名
+type fakeS3 struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	uploads   map[string]map[int][]byte
+	multipart int // 完成的分片上传次数
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	q := r.URL.Query()
+	source := func() []byte {
+		return f.objects[strings.TrimPrefix(r.Header.Get("x-amz-copy-source"), "/bucket/")]
+	}
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads) + 1)
+		f.uploads[id] = make(map[int][]byte)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		part, _ := io.ReadAll(r.Body)
+		if r.Header.Get("x-amz-copy-source") != "" {
+			var first, last int
+			fmt.Sscanf(r.Header.Get("x-amz-copy-source-range"), "bytes=%d-%d", &first, &last)
+			part = source()[first : last+1]
+			fmt.Fprintf(w, `<CopyPartResult><ETag>"p%d"</ETag></CopyPartResult>`, n)
+		} else {
+			w.Header().Set("ETag", fmt.Sprintf(`"p%d"`, n))
+		}
+		f.uploads[q.Get("uploadId")][n] = part
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var req s3CompleteMultipartUpload
+		_ = xml.NewDecoder(r.Body).Decode(&req)
+		var buf bytes.Buffer
+		for _, p := range req.Parts {
+			buf.Write(f.uploads[q.Get("uploadId")][p.PartNumber])
+		}
+		f.objects[key] = buf.Bytes()
+		f.multipart++
+		_, _ = io.WriteString(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		delete(f.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("x-amz-copy-source") != "" {
+			f.objects[key] = source()
+			_, _ = io.WriteString(w, "<CopyObjectResult></CopyObjectResult>")
+			return
+		}
+		f.objects[key], _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestS3Store_PutAndCopyInParts(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), uploads: make(map[string]map[int][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	s, err := NewS3Store(server.URL, false, "", "ak", "sk", "bucket", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.putPartSize = 4
+	s.maxCopySize = 4
+	s.copyPartSize = 3
+	ctx := context.Background()
+
+	// 不超过一个分片时单次 PUT
+	if n, err := s.PutObject(ctx, "small.txt", strings.NewReader("abcd")); err != nil || n != 4 {
+		t.Fatalf("put small: n=%d err=%v", n, err)
+	}
+	if fake.multipart != 0 || string(fake.objects["small.txt"]) != "abcd" {
+		t.Fatalf("put small: multipart=%d content=%q", fake.multipart, fake.objects["small.txt"])
+	}
+
+	// 超过一个分片时按分片流式上传
+	if n, err := s.PutObject(ctx, "big.txt", strings.NewReader("abcdefghij")); err != nil || n != 10 {
+		t.Fatalf("put big: n=%d err=%v", n, err)
+	}
+	if fake.multipart != 1 || string(fake.objects["big.txt"]) != "abcdefghij" {
+		t.Fatalf("put big: multipart=%d content=%q", fake.multipart, fake.objects["big.txt"])
+	}
+
+	if err := s.CopyObject(ctx, "small.txt", "small-copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.multipart != 1 || string(fake.objects["small-copy.txt"]) != "abcd" {
+		t.Fatalf("copy small: multipart=%d content=%q", fake.multipart, fake.objects["small-copy.txt"])
+	}
+	// 超过单次复制上限时分片复制
+	if err := s.CopyObject(ctx, "big.txt", "big-copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.multipart != 2 || string(fake.objects["big-copy.txt"]) != "abcdefghij" {
+		t.Fatalf("copy big: multipart=%d content=%q", fake.multipart, fake.objects["big-copy.txt"])
+	}
+}
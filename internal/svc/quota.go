@@ -0,0 +1,98 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/anil-wu/spark-x/internal/errorx"
+	"github.com/anil-wu/spark-x/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrStorageQuotaExceeded = errorx.New(http.StatusForbidden, "storage_quota_exceeded", "storage quota exceeded")
+
+// StorageQuotaExceeded 超出配额时返回的详细信息
+type StorageQuotaExceeded struct {
+	Scope          string `json:"scope"`
+	ScopeId        uint64 `json:"scopeId"`
+	LimitBytes     int64  `json:"limitBytes"`
+	UsedBytes      int64  `json:"usedBytes"`
+	RequestedBytes int64  `json:"requestedBytes"`
+}
+
+// DefaultStorageQuota 返回配置中的默认配额，0 表示不限制
+func (s *ServiceContext) DefaultStorageQuota(scope string) int64 {
+	if scope == model.StorageQuotaScopeProject {
+		return s.Config.Quota.DefaultProjectBytes
+	}
+	return s.Config.Quota.DefaultUserBytes
+}
+
+// StorageQuotaLimit 返回生效的配额，overridden 表示使用的是管理员设置的值
+func (s *ServiceContext) StorageQuotaLimit(ctx context.Context, scope string, scopeId uint64) (limit int64, overridden bool, err error) {
+	return s.storageQuotaLimit(s.DB.WithContext(ctx), scope, scopeId)
+}
+
+func (s *ServiceContext) storageQuotaLimit(db *gorm.DB, scope string, scopeId uint64) (int64, bool, error) {
+	var quota model.StorageQuotas
+	err := db.Where("scope = ? AND scope_id = ?", scope, scopeId).First(&quota).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.DefaultStorageQuota(scope), false, nil
+		}
+		return 0, false, err
+	}
+	return quota.LimitBytes, true, nil
+}
+
+// CheckStorageQuota 校验新增 addBytes 后项目与用户用量是否超出配额。需在写入版本或上传记录的事务 tx 中调用：
+// 先锁定项目行与用户行，同一项目或用户的并发写入在此串行，不会各自通过校验后合计超出配额
+func (s *ServiceContext) CheckStorageQuota(tx *gorm.DB, projectId uint64, userId uint64, addBytes int64) error {
+	if addBytes <= 0 {
+		return nil
+	}
+	scopes := []struct {
+		scope string
+		id    uint64
+		lock  any
+	}{
+		{model.StorageQuotaScopeProject, projectId, &model.Projects{}},
+		{model.StorageQuotaScopeUser, userId, &model.Users{}},
+	}
+	for _, sc := range scopes {
+		if sc.id == 0 {
+			continue
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", sc.id).Find(sc.lock).Error; err != nil {
+			return err
+		}
+	}
+	for _, sc := range scopes {
+		if sc.id == 0 {
+			continue
+		}
+		limit, _, err := s.storageQuotaLimit(tx, sc.scope, sc.id)
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			continue
+		}
+		used, err := model.StorageUsage(tx.Statement.Context, tx, sc.scope, sc.id)
+		if err != nil {
+			return err
+		}
+		if used+addBytes > limit {
+			return ErrStorageQuotaExceeded.WithDetails(&StorageQuotaExceeded{
+				Scope:          sc.scope,
+				ScopeId:        sc.id,
+				LimitBytes:     limit,
+				UsedBytes:      used,
+				RequestedBytes: addBytes,
+			})
+		}
+	}
+	return nil
+}
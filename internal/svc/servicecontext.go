@@ -8,15 +8,18 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/anil-wu/spark-x/internal/config"
 	"github.com/anil-wu/spark-x/internal/model"
 	"github.com/anil-wu/spark-x/internal/storage"
+	"github.com/anil-wu/spark-x/internal/thumbnail"
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 	"gorm.io/driver/mysql"
@@ -45,6 +48,10 @@ type ServiceContext struct {
 
 	OSSClient *oss.Client
 	OSSBucket *oss.Bucket
+
+	// thumbnailSlots 限制同时解码生成缩略图的数量，首次使用时按配置创建
+	thumbnailSlots     chan struct{}
+	thumbnailSlotsOnce sync.Once
 }
 
 func normalizeOSSEndpoint(rawEndpoint, bucket string) string {
@@ -114,6 +121,10 @@ func (s *ServiceContext) StorageExpireSeconds() int64 {
 		if s.Config.S3.ExpireSeconds > 0 {
 			return s.Config.S3.ExpireSeconds
 		}
+	case "local":
+		if s.Config.Local.ExpireSeconds > 0 {
+			return s.Config.Local.ExpireSeconds
+		}
 	default:
 		if s.Config.OSS.ExpireSeconds > 0 {
 			return s.Config.OSS.ExpireSeconds
@@ -128,6 +139,117 @@ func (s *ServiceContext) StorageExpireSeconds() int64 {
 	return 1800
 }
 
+// PendingVersionTTL 预上传版本在 complete 前的有效期，超时后由后台任务置为 failed
+func (s *ServiceContext) PendingVersionTTL() time.Duration {
+	if s == nil || s.Config.Upload.PendingTTLSeconds <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(s.Config.Upload.PendingTTLSeconds) * time.Second
+}
+
+// FileLockTTL 文件锁的有效期：未指定时取默认值，超过上限时取上限
+func (s *ServiceContext) FileLockTTL(seconds int64) time.Duration {
+	defaultTTL, maxTTL := int64(300), int64(3600)
+	if s != nil && s.Config.FileLock.DefaultTTLSeconds > 0 {
+		defaultTTL = s.Config.FileLock.DefaultTTLSeconds
+	}
+	if s != nil && s.Config.FileLock.MaxTTLSeconds > 0 {
+		maxTTL = s.Config.FileLock.MaxTTLSeconds
+	}
+	if seconds <= 0 {
+		seconds = defaultTTL
+	}
+	if seconds > maxTTL {
+		seconds = maxTTL
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// TrashRetention 文件在回收站中的保留时间，过期后由后台任务彻底删除
+func (s *ServiceContext) TrashRetention() time.Duration {
+	if s == nil || s.Config.Trash.RetentionSeconds <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(s.Config.Trash.RetentionSeconds) * time.Second
+}
+
+// SearchMaxIndexBytes 建立全文索引的文本版本大小上限，超过的版本只能按元数据搜索
+func (s *ServiceContext) SearchMaxIndexBytes() int64 {
+	if s == nil || s.Config.Search.MaxIndexBytes <= 0 {
+		return 1 << 20
+	}
+	return s.Config.Search.MaxIndexBytes
+}
+
+// ThumbnailMaxSourceBytes 生成缩略图的原图大小上限，超过的图片不生成缩略图
+func (s *ServiceContext) ThumbnailMaxSourceBytes() int64 {
+	if s == nil || s.Config.Thumbnail.MaxSourceBytes <= 0 {
+		return thumbnail.DefaultMaxSourceBytes
+	}
+	return s.Config.Thumbnail.MaxSourceBytes
+}
+
+// AcquireThumbnailSlot 等待生成缩略图的名额，返回释放函数；大图解码占用内存较多，
+// 并发数由 Thumbnail.MaxConcurrent 限制，未配置时为 2
+func (s *ServiceContext) AcquireThumbnailSlot(ctx context.Context) (func(), error) {
+	s.thumbnailSlotsOnce.Do(func() {
+		n := s.Config.Thumbnail.MaxConcurrent
+		if n <= 0 {
+			n = 2
+		}
+		s.thumbnailSlots = make(chan struct{}, n)
+	})
+	select {
+	case s.thumbnailSlots <- struct{}{}:
+		return func() { <-s.thumbnailSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InvitationSecret 邀请令牌的签名密钥，未配置时使用登录令牌的密钥
+func (s *ServiceContext) InvitationSecret() string {
+	if s == nil {
+		return ""
+	}
+	if strings.TrimSpace(s.Config.Invitation.Secret) != "" {
+		return s.Config.Invitation.Secret
+	}
+	return s.Config.Auth.AccessSecret
+}
+
+// InvitationTTL 邀请的有效期
+func (s *ServiceContext) InvitationTTL() time.Duration {
+	if s == nil || s.Config.Invitation.ExpireSeconds <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(s.Config.Invitation.ExpireSeconds) * time.Second
+}
+
+// StorageGCGrace 孤儿对象在被清理前的保留时间，避免误删刚上传尚未写入记录的对象
+func (s *ServiceContext) StorageGCGrace() time.Duration {
+	if s == nil || s.Config.StorageGC.GraceSeconds <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(s.Config.StorageGC.GraceSeconds) * time.Second
+}
+
+// LocalStoragePublicBaseURL 返回本地存储预签名 URL 的访问前缀，未配置时按监听地址推断
+func (s *ServiceContext) LocalStoragePublicBaseURL() string {
+	if baseURL := strings.TrimSpace(s.Config.Local.PublicBaseURL); baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	scheme := "http"
+	if strings.TrimSpace(s.Config.CertFile) != "" {
+		scheme = "https"
+	}
+	host := strings.TrimSpace(s.Config.Host)
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, host, s.Config.Port)
+}
+
 func NewServiceContext(c config.Config) *ServiceContext {
 	var db *gorm.DB
 	var err error
@@ -213,7 +335,18 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	var ossClient *oss.Client
 	var bucket *oss.Bucket
 
-	if provider == "s3" {
+	if provider == "local" {
+		signSecret := c.Local.SignSecret
+		if strings.TrimSpace(signSecret) == "" {
+			signSecret = c.Auth.AccessSecret
+		}
+		localStore, err := storage.NewLocalStore(c.Local.RootDir, ctx.LocalStoragePublicBaseURL(), signSecret, expireSeconds)
+		if err != nil {
+			logx.Errorf("local storage init failed: %v", err)
+		} else {
+			ctx.ObjectStore = localStore
+		}
+	} else if provider == "s3" {
 		if strings.TrimSpace(c.S3.Endpoint) != "" && strings.TrimSpace(c.S3.Bucket) != "" {
 			s3Store, err := storage.NewS3Store(
 				c.S3.Endpoint,
@@ -0,0 +1,287 @@
+package textdiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxEditDistance Myers 算法的编辑距离上限，超过后剩余部分整体视为替换，避免差异过大时耗尽内存
+const MaxEditDistance = 4000
+
+// 行类型
+const (
+	LineContext = "context"
+	LineAdd     = "add"
+	LineDelete  = "delete"
+)
+
+// Line 差异中的一行；OldLine / NewLine 为 1 起始的行号，不存在的一侧为 0
+type Line struct {
+	Type    string
+	Content string
+	OldLine int
+	NewLine int
+}
+
+// Hunk 统一差异格式中的一段
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// Header 返回 @@ -a,b +c,d @@ 形式的段头
+func (h *Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", rangeSpec(h.OldStart, h.OldLines), rangeSpec(h.NewStart, h.NewLines))
+}
+
+func rangeSpec(start int, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// IsBinary 含 NUL 字节或不是合法 UTF-8 的内容视为二进制
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data)
+}
+
+// SplitLines 按 \n 拆行（兼容 \r\n），末尾换行不产生空行
+func SplitLines(data []byte) []string {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if s == "" {
+		return nil
+	}
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+// op 编辑脚本中的一步
+type op struct {
+	kind byte // ' ' / '-' / '+'
+	a, b int  // 旧、新序列中的下标
+}
+
+// Diff 计算两组行的差异并按 context 行上下文分段
+func Diff(a, b []string, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+	return group(editScript(a, b), a, b, context)
+}
+
+// editScript 先去掉公共前后缀，再对中间部分运行 Myers 算法
+func editScript(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{' ', i, i})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, op{' ', len(a) - suffix + i, len(b) - suffix + i})
+	}
+	return ops
+}
+
+// myers 经典 O(ND) 算法，保存每一步的 V 用于回溯；offset 为两个序列在原始行中的起始下标
+func myers(a, b []string, offset int) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	limit := max
+	if limit > MaxEditDistance {
+		limit = MaxEditDistance
+	}
+
+	var trace [][]int
+	v := make([]int, 3)
+	v[1+1] = 0
+	found := -1
+	for d := 0; d <= limit && found < 0; d++ {
+		next := make([]int, 2*d+3)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && at(v, d-1, k-1) < at(v, d-1, k+1)) {
+				x = at(v, d-1, k+1)
+			} else {
+				x = at(v, d-1, k-1) + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			next[k+d+1] = x
+			if x >= n && y >= m {
+				found = d
+				break
+			}
+		}
+		trace = append(trace, next)
+		v = next
+	}
+	if found < 0 {
+		// 差异过大：整体删除后整体新增
+		ops := make([]op, 0, n+m)
+		for i := 0; i < n; i++ {
+			ops = append(ops, op{'-', offset + i, offset})
+		}
+		for j := 0; j < m; j++ {
+			ops = append(ops, op{'+', offset + n, offset + j})
+		}
+		return ops
+	}
+
+	// 从终点回溯
+	ops := make([]op, 0, n+m)
+	x, y := n, m
+	for d := found; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(prev, d-1, k-1) < at(prev, d-1, k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prev, d-1, prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{' ', offset + x, offset + y})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, op{'+', offset + x, offset + y})
+		} else {
+			x--
+			ops = append(ops, op{'-', offset + x, offset + y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, op{' ', offset + x, offset + y})
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// at 读取第 d 步 V[k]；第 d 步的 V 只保存 k ∈ [-d-1, d+1]
+func at(v []int, d int, k int) int {
+	idx := k + d + 1
+	if d < 0 {
+		idx = k + 1
+	}
+	if idx < 0 || idx >= len(v) {
+		return 0
+	}
+	return v[idx]
+}
+
+// group 将编辑脚本按上下文合并为段
+func group(ops []op, a, b []string, context int) []Hunk {
+	var hunks []Hunk
+	i := 0
+	for i < len(ops) {
+		// 找到下一处改动
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i >= len(ops) {
+			break
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// 向后扩展，直到连续公共行超过 2*context
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run >= len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		h := Hunk{OldStart: ops[start].a + 1, NewStart: ops[start].b + 1}
+		for _, o := range ops[start:end] {
+			switch o.kind {
+			case ' ':
+				h.Lines = append(h.Lines, Line{Type: LineContext, Content: a[o.a], OldLine: o.a + 1, NewLine: o.b + 1})
+				h.OldLines++
+				h.NewLines++
+			case '-':
+				h.Lines = append(h.Lines, Line{Type: LineDelete, Content: a[o.a], OldLine: o.a + 1})
+				h.OldLines++
+			case '+':
+				h.Lines = append(h.Lines, Line{Type: LineAdd, Content: b[o.b], NewLine: o.b + 1})
+				h.NewLines++
+			}
+		}
+		// 与 diff -u 一致：某侧为空时起始行号为改动位置的前一行
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
+
+// Unified 输出统一差异格式文本
+func Unified(fromName string, toName string, hunks []Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for i := range hunks {
+		sb.WriteString(hunks[i].Header())
+		sb.WriteByte('\n')
+		for _, l := range hunks[i].Lines {
+			switch l.Type {
+			case LineAdd:
+				sb.WriteByte('+')
+			case LineDelete:
+				sb.WriteByte('-')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(l.Content)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
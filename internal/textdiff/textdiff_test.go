@@ -0,0 +1,67 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffUnified(t *testing.T) {
+	a := SplitLines([]byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"))
+	b := SplitLines([]byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"))
+	hunks := Diff(a, b, 3)
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+	want := `--- v1
++++ v2
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if got := Unified("v1", "v2", hunks); got != want {
+		t.Fatalf("unexpected unified diff:\n%s", got)
+	}
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	if hunks := Diff(SplitLines([]byte("x\ny\n")), SplitLines([]byte("x\ny")), 3); len(hunks) != 0 {
+		t.Fatalf("identical lines should produce no hunks: %+v", hunks)
+	}
+	hunks := Diff(nil, []string{"one", "two"}, 3)
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -0,0 +1,2 @@" {
+		t.Fatalf("unexpected hunks for new content: %+v", hunks)
+	}
+
+	// 随机改动后，按差异重建的新内容应与目标一致
+	a := strings.Split("the quick brown fox jumps over the lazy dog again and again", " ")
+	b := strings.Split("a quick red fox jumped over the dog again and again and again", " ")
+	var rebuilt []string
+	for _, h := range Diff(a, b, 100) {
+		for _, l := range h.Lines {
+			if l.Type != LineDelete {
+				rebuilt = append(rebuilt, l.Content)
+			}
+		}
+	}
+	if strings.Join(rebuilt, " ") != strings.Join(b, " ") {
+		t.Fatalf("rebuilt %q", strings.Join(rebuilt, " "))
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if IsBinary([]byte("hello 世界\n")) {
+		t.Fatal("utf-8 text detected as binary")
+	}
+	if !IsBinary([]byte{'P', 'K', 3, 4, 0, 0}) || !IsBinary([]byte{0xff, 0xfe, 'a'}) {
+		t.Fatal("binary content not detected")
+	}
+}
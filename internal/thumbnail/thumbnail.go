@@ -0,0 +1,273 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/anil-wu/spark-x/internal/storage"
+)
+
+// Sizes 缩略图的最长边尺寸（像素）
+var Sizes = []int{128, 512, 1024}
+
+// DefaultSize 未指定尺寸时返回的缩略图
+const DefaultSize = 512
+
+// keyMarker 缩略图存放在原对象 key 之后的子路径中: <storageKey>.thumb/<size>.<ext>
+const keyMarker = ".thumb/"
+
+// maxSourcePixels 原图像素上限（约 2000 万像素，RGBA 解码后约 80MB），防止超大图片解码耗尽内存
+const maxSourcePixels = 20 << 20
+
+// DefaultMaxSourceBytes 未配置时原图大小上限
+const DefaultMaxSourceBytes = 32 << 20
+
+// headerBytes 读取图片尺寸时最多读取的头部字节数，JPEG 的 EXIF 段可能较大
+const headerBytes = 256 << 10
+
+var (
+	ErrUnsupportedFormat = errors.New("thumbnail not supported for this file format")
+	ErrSourceTooLarge    = errors.New("image too large for thumbnail")
+)
+
+// Supported 判断文件格式是否可以用标准库生成缩略图
+func Supported(fileFormat string) bool {
+	switch strings.ToLower(strings.TrimSpace(fileFormat)) {
+	case "png", "jpg", "jpeg", "gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeSize 将请求尺寸对齐到不小于它的预设尺寸，超过最大尺寸时取最大尺寸
+func NormalizeSize(size int) int {
+	if size <= 0 {
+		return DefaultSize
+	}
+	for _, s := range Sizes {
+		if size <= s {
+			return s
+		}
+	}
+	return Sizes[len(Sizes)-1]
+}
+
+// isJpeg 照片类输出 JPEG，其它格式输出 PNG 以保留透明通道
+func isJpeg(fileFormat string) bool {
+	f := strings.ToLower(strings.TrimSpace(fileFormat))
+	return f == "jpg" || f == "jpeg"
+}
+
+// Key 返回缩略图的存储 key
+func Key(storageKey string, size int, fileFormat string) string {
+	ext := "png"
+	if isJpeg(fileFormat) {
+		ext = "jpg"
+	}
+	return fmt.Sprintf("%s%s%d.%s", storageKey, keyMarker, size, ext)
+}
+
+// ContentType 返回缩略图的 Content-Type
+func ContentType(fileFormat string) string {
+	if isJpeg(fileFormat) {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// SourceKey 若 key 是缩略图则返回原对象 key
+func SourceKey(key string) (string, bool) {
+	idx := strings.LastIndex(key, keyMarker)
+	if idx <= 0 {
+		return "", false
+	}
+	return key[:idx], true
+}
+
+// Generate 读取原图并生成指定尺寸的缩略图，已存在的尺寸跳过。原图超过 maxSourceBytes（<= 0 时取默认值）
+// 或像素数超过上限时返回 ErrSourceTooLarge，原图不会整体读入内存
+func Generate(ctx context.Context, store storage.ObjectStore, storageKey string, fileFormat string, maxSourceBytes int64, sizes ...int) error {
+	if !Supported(fileFormat) {
+		return ErrUnsupportedFormat
+	}
+	if maxSourceBytes <= 0 {
+		maxSourceBytes = DefaultMaxSourceBytes
+	}
+	if len(sizes) == 0 {
+		sizes = Sizes
+	}
+	pending := make([]int, 0, len(sizes))
+	for _, size := range sizes {
+		if _, err := store.StatObject(ctx, Key(storageKey, size, fileFormat)); err == nil {
+			continue
+		}
+		pending = append(pending, size)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	stat, err := store.StatObject(ctx, storageKey)
+	if err != nil {
+		return err
+	}
+	if stat.SizeBytes > maxSourceBytes {
+		return ErrSourceTooLarge
+	}
+	reader, err := store.GetObject(ctx, storageKey)
+	if err != nil {
+		return err
+	}
+	src, err := decode(io.LimitReader(reader, maxSourceBytes))
+	_ = reader.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, size := range pending {
+		var buf bytes.Buffer
+		if err := encode(&buf, Resize(src, size), fileFormat); err != nil {
+			return err
+		}
+		if _, err := store.PutObject(ctx, Key(storageKey, size, fileFormat), &buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode 先从头部读出图片尺寸，像素数在上限内才解码全图
+func decode(r io.Reader) (image.Image, error) {
+	header := make([]byte, headerBytes)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	header = header[:n]
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(header))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrSourceTooLarge, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(io.MultiReader(bytes.NewReader(header), r))
+	return img, err
+}
+
+func encode(w io.Writer, img image.Image, fileFormat string) error {
+	if isJpeg(fileFormat) {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+	return png.Encode(w, img)
+}
+
+// Resize 按最长边等比缩小到 size，取每个目标像素覆盖区域的平均值；不放大小图
+func Resize(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+		return dst
+	}
+	dw, dh := size, size
+	if w >= h {
+		dh = max(1, h*size/w)
+	} else {
+		dw = max(1, w*size/h)
+	}
+
+	sum := newAreaSum(src)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0 := b.Min.Y + y*h/dh
+		sy1 := max(sy0+1, b.Min.Y+(y+1)*h/dh)
+		for x := 0; x < dw; x++ {
+			sx0 := b.Min.X + x*w/dw
+			sx1 := max(sx0+1, b.Min.X+(x+1)*w/dw)
+			r, g, bl, a, n := sum(sx0, sy0, sx1, sy1)
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(bl / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
+
+// areaSum 返回原图 [x0,x1)×[y0,y1) 区域内预乘 alpha 的 16 位颜色分量之和与像素数
+type areaSum func(x0, y0, x1, y1 int) (r, g, b, a, n uint64)
+
+// newAreaSum 解码器常见的输出类型直接读取像素数组，其它类型逐像素经 color.Color 转换
+func newAreaSum(src image.Image) areaSum {
+	switch s := src.(type) {
+	case *image.RGBA:
+		return func(x0, y0, x1, y1 int) (r, g, b, a, n uint64) {
+			for y := y0; y < y1; y++ {
+				p := s.Pix[s.PixOffset(x0, y):s.PixOffset(x1, y)]
+				for i := 0; i < len(p); i += 4 {
+					r += uint64(p[i+0])
+					g += uint64(p[i+1])
+					b += uint64(p[i+2])
+					a += uint64(p[i+3])
+				}
+			}
+			return r * 0x101, g * 0x101, b * 0x101, a * 0x101, uint64((x1 - x0) * (y1 - y0))
+		}
+	case *image.NRGBA:
+		return func(x0, y0, x1, y1 int) (r, g, b, a, n uint64) {
+			for y := y0; y < y1; y++ {
+				p := s.Pix[s.PixOffset(x0, y):s.PixOffset(x1, y)]
+				for i := 0; i < len(p); i += 4 {
+					pa := uint64(p[i+3])
+					r += uint64(p[i+0]) * pa
+					g += uint64(p[i+1]) * pa
+					b += uint64(p[i+2]) * pa
+					a += pa
+				}
+			}
+			// 8 位分量乘 8 位 alpha 后换算为 16 位预乘值：c*a*0x101*0x101/0xffff = c*a*0x101/0xff
+			return r * 0x101 / 0xff, g * 0x101 / 0xff, b * 0x101 / 0xff, a * 0x101, uint64((x1 - x0) * (y1 - y0))
+		}
+	case *image.YCbCr:
+		return func(x0, y0, x1, y1 int) (r, g, b, a, n uint64) {
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					ci := s.COffset(x, y)
+					cr, cg, cb := color.YCbCrToRGB(s.Y[s.YOffset(x, y)], s.Cb[ci], s.Cr[ci])
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+				}
+			}
+			n = uint64((x1 - x0) * (y1 - y0))
+			return r * 0x101, g * 0x101, b * 0x101, n * 0xffff, n
+		}
+	default:
+		return func(x0, y0, x1, y1 int) (r, g, b, a, n uint64) {
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := src.At(x, y).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+				}
+			}
+			return r, g, b, a, uint64((x1 - x0) * (y1 - y0))
+		}
+	}
+}
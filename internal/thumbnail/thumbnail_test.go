@@ -0,0 +1,149 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/anil-wu/spark-x/internal/storage"
+)
+
+func TestNormalizeSize(t *testing.T) {
+	cases := map[int]int{0: DefaultSize, 1: 128, 128: 128, 129: 512, 1000: 1024, 4096: 1024}
+	for in, want := range cases {
+		if got := NormalizeSize(in); got != want {
+			t.Fatalf("NormalizeSize(%d)=%d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	dst := Resize(src, 128)
+	if b := dst.Bounds(); b.Dx() != 128 || b.Dy() != 64 {
+		t.Fatalf("unexpected bounds %v", b)
+	}
+	if r, _, _, a := dst.At(10, 10).RGBA(); r>>8 != 200 || a>>8 != 255 {
+		t.Fatalf("unexpected color r=%d a=%d", r>>8, a>>8)
+	}
+	if b := Resize(src, 1024).Bounds(); b.Dx() != 400 || b.Dy() != 200 {
+		t.Fatalf("small images must not be upscaled, got %v", b)
+	}
+}
+
+// genericImage 隐藏具体类型，让 Resize 走逐像素转换的通用路径
+type genericImage struct{ image.Image }
+
+func TestResizeFastPaths(t *testing.T) {
+	rect := image.Rect(0, 0, 301, 157)
+	rgba := image.NewRGBA(rect)
+	nrgba := image.NewNRGBA(rect)
+	ycbcr := image.NewYCbCr(rect, image.YCbCrSubsampleRatio420)
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			c := color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: uint8(x + y)}
+			rgba.Set(x, y, c)
+			nrgba.SetNRGBA(x, y, c)
+			ycbcr.Y[ycbcr.YOffset(x, y)] = uint8(x + y)
+			ycbcr.Cb[ycbcr.COffset(x, y)] = uint8(x)
+			ycbcr.Cr[ycbcr.COffset(x, y)] = uint8(y)
+		}
+	}
+	for _, src := range []image.Image{rgba, nrgba, ycbcr} {
+		got, want := Resize(src, 128).(*image.RGBA), Resize(genericImage{src}, 128).(*image.RGBA)
+		if got.Bounds() != want.Bounds() {
+			t.Fatalf("%T: bounds %v, want %v", src, got.Bounds(), want.Bounds())
+		}
+		for i := range got.Pix {
+			if d := int(got.Pix[i]) - int(want.Pix[i]); d < -1 || d > 1 {
+				t.Fatalf("%T: pixel byte %d is %d, want %d", src, i, got.Pix[i], want.Pix[i])
+			}
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "blobs/sha256/ab/abcd"
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 600, 300))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	if _, err := s.PutObject(ctx, key, &buf); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	if err := Generate(ctx, s, key, "png", 0); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	thumbKey := Key(key, 512, "png")
+	reader, err := s.GetObject(ctx, thumbKey)
+	if err != nil {
+		t.Fatalf("GetObject: %v", err)
+	}
+	img, err := png.Decode(reader)
+	_ = reader.Close()
+	if err != nil || img.Bounds().Dx() != 512 || img.Bounds().Dy() != 256 {
+		t.Fatalf("unexpected thumbnail err=%v", err)
+	}
+	if source, ok := SourceKey(thumbKey); !ok || source != key {
+		t.Fatalf("SourceKey(%s)=%s,%v", thumbKey, source, ok)
+	}
+	if err := Generate(ctx, s, key, "webp", 0); err != ErrUnsupportedFormat {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestGenerateSourceLimits(t *testing.T) {
+	s, err := storage.NewLocalStore(t.TempDir(), "", "secret", 60)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	key := "blobs/sha256/cd/cdef"
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 300, 300))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	size := int64(buf.Len())
+	if _, err := s.PutObject(ctx, key, &buf); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	if err := Generate(ctx, s, key, "png", size-1, 128); !errors.Is(err, ErrSourceTooLarge) {
+		t.Fatalf("expected ErrSourceTooLarge for oversized object, got %v", err)
+	}
+	if _, err := s.StatObject(ctx, Key(key, 128, "png")); err == nil {
+		t.Fatalf("thumbnail must not be generated for oversized object")
+	}
+	if err := Generate(ctx, s, key, "png", size, 128); err != nil {
+		t.Fatalf("Generate within limit: %v", err)
+	}
+
+	// 头部声明的尺寸超过像素上限时不解码全图
+	header := bytes.NewBuffer(nil)
+	header.WriteString("\x89PNG\r\n\x1a\n")
+	ihdr := []byte{'I', 'H', 'D', 'R', 0, 1, 0, 0, 0, 1, 0, 0, 8, 6, 0, 0, 0}
+	_ = binary.Write(header, binary.BigEndian, uint32(len(ihdr)-4))
+	header.Write(ihdr)
+	_ = binary.Write(header, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	if _, err := decode(header); !errors.Is(err, ErrSourceTooLarge) {
+		t.Fatalf("expected ErrSourceTooLarge for huge dimensions, got %v", err)
+	}
+}
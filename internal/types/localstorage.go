@@ -0,0 +1,10 @@
+package types
+
+type LocalObjectReq struct {
+	Key         string `form:"key"`
+	Expires     int64  `form:"expires"`
+	ContentType string `form:"contentType,optional"`
+	Signature   string `form:"signature"`
+	UploadId    string `form:"uploadId,optional"`
+	PartNumber  int    `form:"partNumber,optional"`
+}
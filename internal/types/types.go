@@ -3,6 +3,26 @@
 
 package types
 
+type AbortChangesetReq struct {
+	Id int64 `path:"id"`
+}
+
+type AbortMultipartUploadReq struct {
+	Id int64 `path:"id"`
+}
+
+type AcceptInvitationResp struct {
+	ProjectId int64  `json:"projectId"`
+	Role      string `json:"role"`
+}
+
+type AcquireFileLockReq struct {
+	Id         int64  `path:"id"`
+	TtlSeconds int64  `json:"ttlSeconds,optional"` // 有效期（秒），默认与上限见 FileLock 配置
+	Note       string `json:"note,optional"`
+	Force      bool   `json:"force,optional"` // owner / admin 强制接管他人的锁
+}
+
 type AdminCreateProjectReq struct {
 	Name        string `json:"name"`
 	Description string `json:"description,optional"`
@@ -173,6 +193,61 @@ type CanvasWithLayersResp struct {
 	Layers []LayerResp `json:"layers"`
 }
 
+type ChangesetDetailResp struct {
+	Changeset ChangesetItem       `json:"changeset"`
+	Files     []ChangesetFileItem `json:"files"`
+}
+
+type ChangesetFileItem struct {
+	FileId            int64  `json:"fileId"`
+	Path              string `json:"path"`
+	VersionId         int64  `json:"versionId"`
+	VersionNumber     int64  `json:"versionNumber"`
+	VersionStatus     string `json:"versionStatus"`     // pending | ready | failed
+	PreviousVersionId int64  `json:"previousVersionId"` // 提交时被替换的当前版本，0 表示文件由该变更集新建
+}
+
+type ChangesetItem struct {
+	Id            int64  `json:"id"`
+	ProjectId     int64  `json:"projectId"`
+	Message       string `json:"message"`
+	Status        string `json:"status"` // open | committed | aborted | rolled_back
+	CreatedBy     int64  `json:"createdBy"`
+	CreatedByName string `json:"createdByName"`
+	FileCount     int64  `json:"fileCount"`
+	CommittedAt   string `json:"committedAt"`
+	CreatedAt     string `json:"createdAt"`
+	UpdatedAt     string `json:"updatedAt"`
+}
+
+type ChangesetListResp struct {
+	List []ChangesetItem `json:"list"`
+	Page PageResp        `json:"page"`
+}
+
+type CommitChangesetReq struct {
+	Id int64 `path:"id"`
+}
+
+type CompleteFileVersionReq struct {
+	Id        int64 `path:"id"`
+	VersionId int64 `path:"versionId"`
+}
+
+type CompleteMultipartUploadReq struct {
+	Id    int64              `path:"id"`
+	Parts []UploadedPartItem `json:"parts,optional"` // 为空时以对象存储中已上传的分片为准
+}
+
+type CopyFileReq struct {
+	Id              int64  `path:"id"`
+	TargetProjectId int64  `json:"targetProjectId"`
+	VersionId       int64  `json:"versionId,optional"`      // 默认复制当前版本
+	AllVersions     bool   `json:"allVersions,optional"`    // 复制全部已完成版本
+	Name            string `json:"name,optional"`           // 目标项目中的文件名，默认沿用原名
+	TargetFolderId  int64  `json:"targetFolderId,optional"` // 目标项目中的目录，默认根目录
+}
+
 type CreateAdminReq struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -226,6 +301,24 @@ type CreateCanvasResp struct {
 	CanvasId int64 `json:"canvasId"`
 }
 
+type CreateChangesetReq struct {
+	ProjectId int64  `path:"projectId"`
+	Message   string `json:"message,optional"`
+}
+
+type CreateFolderReq struct {
+	ProjectId int64  `path:"projectId"`
+	ParentId  int64  `json:"parentId,optional"` // 上级目录，默认根目录
+	Name      string `json:"name,optional"`     // 与 path 二选一
+	Path      string `json:"path,optional"`     // 相对 parentId 的多级路径，缺失的中间目录自动创建
+}
+
+type CreateInvitationReq struct {
+	ProjectId int64  `path:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"` // owner | admin | developer | viewer
+}
+
 type CreateLlmModelReq struct {
 	ProviderId       int64   `json:"providerId"`
 	ModelName        string  `json:"modelName"`
@@ -283,6 +376,13 @@ type CreateReleaseResp struct {
 	PublishedAt                  string `json:"publishedAt"`
 }
 
+type CreateRetentionPolicyReq struct {
+	ProjectId   int64  `path:"projectId"`
+	PathPattern string `json:"pathPattern,optional"` // 为空表示项目默认策略，如 *.log、builds/**
+	KeepLast    int64  `json:"keepLast,optional"`    // 保留最近 N 个版本
+	KeepDays    int64  `json:"keepDays,optional"`    // 保留 N 天内的版本
+}
+
 type CreateSoftwareManifestReq struct {
 	Id                    int64  `json:"id,optional"`
 	ProjectId             int64  `json:"projectId"`
@@ -347,6 +447,12 @@ type DeleteFileReq struct {
 	Id int64 `path:"id"`
 }
 
+type DeleteFolderReq struct {
+	ProjectId int64 `path:"projectId"`
+	FolderId  int64 `path:"folderId"`
+	Recursive bool  `form:"recursive,optional"` // 非空目录需要 recursive=true，连同子目录与文件一起删除
+}
+
 type DeleteLayerReq struct {
 	Id int64 `path:"id"`
 }
@@ -369,6 +475,11 @@ type DeleteProjectReq struct {
 	Id int64 `path:"id"`
 }
 
+type DeleteRetentionPolicyReq struct {
+	ProjectId int64 `path:"projectId"`
+	PolicyId  int64 `path:"policyId"`
+}
+
 type DeleteSoftwareTemplateReq struct {
 	Id int64 `path:"id"`
 }
@@ -387,9 +498,10 @@ type DeletedLayersResp struct {
 }
 
 type DownloadFileReq struct {
-	Id            int64 `path:"id"`
-	VersionId     int64 `form:"versionId,optional"`
-	VersionNumber int64 `form:"versionNumber,optional"`
+	Id            int64  `path:"id"`
+	VersionId     int64  `form:"versionId,optional"`
+	VersionNumber int64  `form:"versionNumber,optional"`
+	Label         string `form:"label,optional"` // 带该标签的最新版本
 }
 
 type DownloadFileResp struct {
@@ -397,16 +509,88 @@ type DownloadFileResp struct {
 	ExpiresAt   string `json:"expiresAt"`
 }
 
+type ExportProjectReq struct {
+	ProjectId   int64 `path:"projectId"`
+	AllVersions bool  `form:"allVersions,optional"` // 附带全部已完成的历史版本
+	Manifest    bool  `form:"manifest,optional"`    // 附带 manifest.json（目录、文件、版本、软件、清单、构建版本、画布）
+}
+
+type FileDiffHunk struct {
+	Header   string         `json:"header"` // @@ -a,b +c,d @@
+	OldStart int64          `json:"oldStart"`
+	OldLines int64          `json:"oldLines"`
+	NewStart int64          `json:"newStart"`
+	NewLines int64          `json:"newLines"`
+	Lines    []FileDiffLine `json:"lines"`
+}
+
+type FileDiffLine struct {
+	Type    string `json:"type"` // context | add | delete
+	Content string `json:"content"`
+	OldLine int64  `json:"oldLine"` // 旧版本中的行号，新增行为 0
+	NewLine int64  `json:"newLine"` // 新版本中的行号，删除行为 0
+}
+
+type FileDiffResp struct {
+	FileId        int64          `json:"fileId"`
+	FromVersionId int64          `json:"fromVersionId"`
+	FromVersion   int64          `json:"fromVersion"`
+	ToVersionId   int64          `json:"toVersionId"`
+	ToVersion     int64          `json:"toVersion"`
+	Identical     bool           `json:"identical"`
+	Additions     int64          `json:"additions"`
+	Deletions     int64          `json:"deletions"`
+	Unified       string         `json:"unified"`
+	Hunks         []FileDiffHunk `json:"hunks"`
+}
+
+type FileLockItem struct {
+	FileId       int64  `json:"fileId"`
+	ProjectId    int64  `json:"projectId"`
+	Path         string `json:"path"`
+	LockedBy     int64  `json:"lockedBy"`
+	LockedByName string `json:"lockedByName"`
+	Note         string `json:"note"`
+	ExpiresAt    string `json:"expiresAt"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type FileLockListResp struct {
+	List []FileLockItem `json:"list"`
+}
+
+type FileSearchItem struct {
+	File      ProjectFileItem   `json:"file"`
+	UpdatedAt string            `json:"updatedAt"` // 当前版本的上传时间
+	CreatedBy int64             `json:"createdBy"` // 当前版本的上传者
+	Matches   []FileSearchMatch `json:"matches"`   // 按内容搜索时命中的行
+}
+
+type FileSearchMatch struct {
+	Line int64  `json:"line"`
+	Text string `json:"text"`
+}
+
+type FileSearchResp struct {
+	List []FileSearchItem `json:"list"`
+	Page PageResp         `json:"page"`
+}
+
 type FileVersionItem struct {
-	Id            int64  `json:"id"`
-	FileId        int64  `json:"fileId"`
-	VersionNumber int64  `json:"versionNumber"`
-	SizeBytes     int64  `json:"sizeBytes"`
-	Hash          string `json:"hash"`
-	StorageKey    string `json:"storageKey"`
-	CreatedAt     string `json:"createdAt"`
-	UpdatedAt     string `json:"updatedAt"`
-	CreatedBy     int64  `json:"createdBy"`
+	Id            int64    `json:"id"`
+	FileId        int64    `json:"fileId"`
+	VersionNumber int64    `json:"versionNumber"`
+	SizeBytes     int64    `json:"sizeBytes"`
+	Hash          string   `json:"hash"`
+	StorageKey    string   `json:"storageKey"`
+	Status        string   `json:"status"` // pending | ready | failed
+	CreatedAt     string   `json:"createdAt"`
+	UpdatedAt     string   `json:"updatedAt"`
+	CreatedBy     int64    `json:"createdBy"`
+	CreatedByName string   `json:"createdByName"` // 上传者用户名
+	Message       string   `json:"message"`       // 版本说明
+	Labels        []string `json:"labels"`
+	ChangesetId   int64    `json:"changesetId"` // 所属变更集，0 表示单独上传
 }
 
 type FileVersionListResp struct {
@@ -414,6 +598,21 @@ type FileVersionListResp struct {
 	Page PageResp          `json:"page"`
 }
 
+type FolderChildrenResp struct {
+	Folder  FolderItem        `json:"folder"`
+	Folders []FolderItem      `json:"folders"`
+	Files   []ProjectFileItem `json:"files"`
+}
+
+type FolderItem struct {
+	Id        int64  `json:"id"`
+	ProjectId int64  `json:"projectId"`
+	ParentId  int64  `json:"parentId"` // 0 表示根目录
+	Name      string `json:"name"`
+	Path      string `json:"path"` // 项目内完整路径，如 src/utils
+	CreatedAt string `json:"createdAt"`
+}
+
 type GetAgentByNameReq struct {
 	Name string `path:"name"`
 }
@@ -430,15 +629,37 @@ type GetCanvasWithLayersReq struct {
 	ProjectId int64 `path:"projectId"`
 }
 
+type GetChangesetReq struct {
+	Id int64 `path:"id"`
+}
+
 type GetDeletedLayersReq struct {
 	CanvasId int64 `form:"canvasId"`
 	Limit    int64 `form:"limit,default=50"`
 }
 
+type GetFileByPathReq struct {
+	ProjectId int64  `path:"projectId"`
+	Path      string `form:"path"` // 如 src/main.go
+}
+
 type GetFileContentReq struct {
 	Id int64 `path:"id"`
 }
 
+type GetFileDiffReq struct {
+	Id      int64 `path:"id"`
+	From    int64 `form:"from,optional"`     // 旧版本号，默认 to 的上一个版本
+	To      int64 `form:"to,optional"`       // 新版本号，默认当前版本
+	Context int64 `form:"context,default=3"` // 每段上下文行数
+}
+
+type GetFileThumbnailReq struct {
+	Id        int64 `path:"id"`
+	Size      int64 `form:"size,optional"`
+	VersionId int64 `form:"versionId,optional"`
+}
+
 type GetLlmModelReq struct {
 	Id int64 `path:"id"`
 }
@@ -463,9 +684,70 @@ type GetUserByEmailReq struct {
 	Email string `path:"email"`
 }
 
+type ImportProjectReq struct {
+	StorageKey string `json:"storageKey,optional"` // 通过 PreUpload 上传过的导出包；也可用 multipart 的 file 字段直接上传
+	Name       string `json:"name,optional"`       // 新项目名称，默认沿用导出包中的名称
+}
+
+type ImportProjectResp struct {
+	Project           ProjectResp `json:"project"`
+	Folders           int64       `json:"folders"`
+	Files             int64       `json:"files"`
+	Versions          int64       `json:"versions"`
+	Softwares         int64       `json:"softwares"`
+	SoftwareManifests int64       `json:"softwareManifests"`
+	BuildVersions     int64       `json:"buildVersions"`
+	Layers            int64       `json:"layers"`
+}
+
+type InitiateMultipartUploadReq struct {
+	ProjectId                int64    `json:"projectId"`
+	Name                     string   `json:"name,optional"`     // 与 path 二选一
+	Path                     string   `json:"path,optional"`     // 项目内路径，如 src/main.go，缺失的目录自动创建
+	FolderId                 int64    `json:"folderId,optional"` // 所在目录（path 相对于该目录），默认根目录
+	FileCategory             string   `json:"fileCategory"`      // text | image | video | audio | binary | archive
+	FileFormat               string   `json:"fileFormat"`        // 文件格式，如 png, jpg, mp4, mp3, txt 等
+	SizeBytes                int64    `json:"sizeBytes"`
+	Hash                     string   `json:"hash"`
+	ContentType              string   `json:"contentType,optional"`              // 上传文件的 Content-Type
+	PartSize                 int64    `json:"partSize,optional"`                 // 分片大小（字节），默认 16MB
+	Message                  string   `json:"message,optional"`                  // 版本说明
+	Labels                   []string `json:"labels,optional"`                   // 版本标签
+	ChangesetId              int64    `json:"changesetId,optional"`              // 上传到打开的变更集
+	ExpectedCurrentVersionId int64    `json:"expectedCurrentVersionId,optional"` // 期望的文件当前版本
+	IfMatch                  string   `header:"If-Match,optional"`
+}
+
+type InitiateMultipartUploadResp struct {
+	UploadId      int64  `json:"uploadId"` // 上传会话ID，后续分片接口使用
+	FileId        int64  `json:"fileId"`
+	VersionId     int64  `json:"versionId"`
+	VersionNumber int64  `json:"versionNumber"`
+	ContentType   string `json:"contentType"`
+	PartSize      int64  `json:"partSize"`
+	PartCount     int64  `json:"partCount"`
+	SkipUpload    bool   `json:"skipUpload"` // 本项目中已有相同内容，无需上传，版本已生效
+}
+
+type InvitationItem struct {
+	Id          int64  `json:"id"`
+	ProjectId   int64  `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Status      string `json:"status"` // pending | accepted | declined | revoked | expired
+	InvitedBy   int64  `json:"invitedBy"`
+	Token       string `json:"token,omitempty"` // 仅返回给邀请者（创建时）和被邀请者
+	ExpiresAt   string `json:"expiresAt"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+type InvitationTokenReq struct {
+	Token string `path:"token"`
+}
+
 type InviteMemberReq struct {
-	UserId        int64  `json:"userId"`        // 发起者
-	InvitedUserId int64  `json:"invitedUserId"` // 被邀请者
+	InvitedUserId int64  `json:"invitedUserId"` // 被邀请者，发起者取自登录用户
 	ProjectId     int64  `path:"id"`
 	Role          string `json:"role"` // owner | admin | developer | viewer
 }
@@ -552,6 +834,10 @@ type LayerResp struct {
 	CreatedBy  int64            `json:"createdBy"`
 }
 
+type LeaveProjectReq struct {
+	ProjectId int64 `path:"id"`
+}
+
 type ListAdminsReq struct {
 	Page     int64 `form:"page,default=1"`
 	PageSize int64 `form:"pageSize,default=20"`
@@ -577,10 +863,31 @@ type ListBuildVersionsReq struct {
 	PageSize  int64 `form:"pageSize,default=20"`
 }
 
+type ListChangesetsReq struct {
+	ProjectId int64  `path:"projectId"`
+	Status    string `form:"status,optional"` // open | committed | aborted | rolled_back
+	Page      int64  `form:"page,default=1"`
+	PageSize  int64  `form:"pageSize,default=20"`
+}
+
+type ListFileLocksReq struct {
+	ProjectId int64 `path:"projectId"`
+}
+
 type ListFileVersionsReq struct {
-	Id       int64 `path:"id"`
-	Page     int64 `form:"page,default=1"`
-	PageSize int64 `form:"pageSize,default=20"`
+	Id       int64  `path:"id"`
+	Page     int64  `form:"page,default=1"`
+	PageSize int64  `form:"pageSize,default=20"`
+	Label    string `form:"label,optional"` // 只返回带该标签的版本
+}
+
+type ListFolderChildrenReq struct {
+	ProjectId int64 `path:"projectId"`
+	FolderId  int64 `path:"folderId"` // 0 表示根目录
+}
+
+type ListInvitationsResp struct {
+	List []InvitationItem `json:"list"`
 }
 
 type ListLatestSoftwareManifestsReq struct {
@@ -608,12 +915,51 @@ type ListProjectFilesReq struct {
 	PageSize  int64 `form:"pageSize,default=20"`
 }
 
+type ListProjectInvitationsReq struct {
+	ProjectId int64  `path:"id"`
+	Status    string `form:"status,optional"` // 默认 pending
+}
+
+type ListProjectMembersReq struct {
+	ProjectId int64 `path:"id"`
+}
+
+type ListProjectMembersResp struct {
+	List []ProjectMemberItem `json:"list"`
+}
+
 type ListProjectSoftwaresReq struct {
 	ProjectId int64 `path:"projectId"`
 	Page      int64 `form:"page,default=1"`
 	PageSize  int64 `form:"pageSize,default=20"`
 }
 
+type ListProjectsReq struct {
+	Page     int64  `form:"page,default=1"`
+	PageSize int64  `form:"pageSize,default=20"`
+	Status   string `form:"status,optional"` // active | archived | all，默认 active
+}
+
+type ListRetentionPoliciesReq struct {
+	ProjectId int64 `path:"projectId"`
+}
+
+type ListStorageQuotasReq struct {
+	Scope    string `form:"scope,optional"`
+	Page     int64  `form:"page,default=1"`
+	PageSize int64  `form:"pageSize,default=20"`
+}
+
+type ListTrashReq struct {
+	ProjectId int64 `path:"projectId"`
+	Page      int64 `form:"page,default=1"`
+	PageSize  int64 `form:"pageSize,default=20"`
+}
+
+type ListUploadedPartsReq struct {
+	Id int64 `path:"id"`
+}
+
 type LlmModelListResp struct {
 	List []LlmModelResp `json:"list"`
 	Page PageResp       `json:"page"`
@@ -679,6 +1025,19 @@ type LoginResp struct {
 	IsSuper bool   `json:"isSuper"`
 }
 
+type MoveFileReq struct {
+	Id              int64  `path:"id"`
+	TargetProjectId int64  `json:"targetProjectId"`
+	Name            string `json:"name,optional"`
+	TargetFolderId  int64  `json:"targetFolderId,optional"` // 目标项目中的目录，默认根目录
+}
+
+type MoveFolderReq struct {
+	ProjectId int64 `path:"projectId"`
+	FolderId  int64 `path:"folderId"`
+	ParentId  int64 `json:"parentId"` // 新的上级目录，0 表示根目录
+}
+
 type PageReq struct {
 	Page     int64 `form:"page,default=1"`
 	PageSize int64 `form:"pageSize,default=20"`
@@ -691,13 +1050,20 @@ type PageResp struct {
 }
 
 type PreUploadReq struct {
-	ProjectId    int64  `json:"projectId"`
-	Name         string `json:"name"`
-	FileCategory string `json:"fileCategory"` // text | image | video | audio | binary | archive
-	FileFormat   string `json:"fileFormat"`   // 文件格式，如 png, jpg, mp4, mp3, txt 等
-	SizeBytes    int64  `json:"sizeBytes"`
-	Hash         string `json:"hash"`
-	ContentType  string `json:"contentType,optional"` // 上传文件的 Content-Type，如 text/plain, image/png 等
+	ProjectId                int64    `json:"projectId"`
+	Name                     string   `json:"name,optional"`     // 与 path 二选一
+	Path                     string   `json:"path,optional"`     // 项目内路径，如 src/main.go，缺失的目录自动创建
+	FolderId                 int64    `json:"folderId,optional"` // 所在目录（path 相对于该目录），默认根目录
+	FileCategory             string   `json:"fileCategory"`      // text | image | video | audio | binary | archive
+	FileFormat               string   `json:"fileFormat"`        // 文件格式，如 png, jpg, mp4, mp3, txt 等
+	SizeBytes                int64    `json:"sizeBytes"`
+	Hash                     string   `json:"hash"`
+	ContentType              string   `json:"contentType,optional"`              // 上传文件的 Content-Type，如 text/plain, image/png 等
+	Message                  string   `json:"message,optional"`                  // 版本说明
+	Labels                   []string `json:"labels,optional"`                   // 版本标签，如 approved、release-1.2
+	ChangesetId              int64    `json:"changesetId,optional"`              // 上传到打开的变更集，提交后才成为当前版本
+	ExpectedCurrentVersionId int64    `json:"expectedCurrentVersionId,optional"` // 期望的文件当前版本，不一致时返回 409
+	IfMatch                  string   `header:"If-Match,optional"`               // 期望的当前版本内容 ETag（文件内容接口返回的 ETag），* 表示文件须已有当前版本
 }
 
 type PreUploadResp struct {
@@ -706,12 +1072,25 @@ type PreUploadResp struct {
 	VersionId     int64  `json:"versionId"`
 	VersionNumber int64  `json:"versionNumber"`
 	ContentType   string `json:"contentType"` // 上传时必须使用的 Content-Type
+	SkipUpload    bool   `json:"skipUpload"`  // 本项目中已有相同内容，无需上传，版本已生效
+}
+
+type PresignUploadPartReq struct {
+	Id         int64 `path:"id"`
+	PartNumber int64 `path:"partNumber"`
+}
+
+type PresignUploadPartResp struct {
+	UploadUrl  string `json:"uploadUrl"`
+	PartNumber int64  `json:"partNumber"`
 }
 
 type ProjectFileItem struct {
 	Id               int64  `json:"id"`
 	ProjectId        int64  `json:"projectId"`
 	Name             string `json:"name"`
+	FolderId         int64  `json:"folderId"` // 所在目录，0 表示根目录
+	Path             string `json:"path"`     // 项目内完整路径，如 src/main.go
 	FileCategory     string `json:"fileCategory"`
 	FileFormat       string `json:"fileFormat"`
 	CurrentVersionId int64  `json:"currentVersionId"`
@@ -733,6 +1112,15 @@ type ProjectListResp struct {
 	Page PageResp      `json:"page"`
 }
 
+type ProjectMemberItem struct {
+	UserId    int64  `json:"userId"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Avatar    string `json:"avatar"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
 type ProjectResp struct {
 	Id          int64  `json:"id"`
 	Name        string `json:"name"`
@@ -744,6 +1132,55 @@ type ProjectResp struct {
 	UpdatedAt   string `json:"updatedAt"`
 }
 
+type PruneVersionsReq struct {
+	ProjectId int64 `path:"projectId"`
+	DryRun    bool  `json:"dryRun,optional"` // 只生成报告，不删除
+}
+
+type PruneVersionsResp struct {
+	ProjectId      int64            `json:"projectId"`
+	DryRun         bool             `json:"dryRun"`
+	ScannedFiles   int64            `json:"scannedFiles"`
+	PrunedVersions int64            `json:"prunedVersions"`
+	PrunedBytes    int64            `json:"prunedBytes"`
+	DeletedObjects int64            `json:"deletedObjects"`
+	Files          []PrunedFileItem `json:"files"`
+}
+
+type PrunedFileItem struct {
+	FileId         int64   `json:"fileId"`
+	Path           string  `json:"path"`
+	PolicyId       int64   `json:"policyId"`
+	VersionNumbers []int64 `json:"versionNumbers"`
+	SizeBytes      int64   `json:"sizeBytes"`
+}
+
+type PurgeTrashFileReq struct {
+	ProjectId int64 `path:"projectId"`
+	FileId    int64 `path:"fileId"`
+}
+
+type ReleaseFileLockReq struct {
+	Id    int64 `path:"id"`
+	Force bool  `form:"force,optional"` // owner / admin 强制释放他人的锁
+}
+
+type RemoveProjectMemberReq struct {
+	ProjectId int64 `path:"id"`
+	UserId    int64 `path:"userId"`
+}
+
+type RenameFolderReq struct {
+	ProjectId int64  `path:"projectId"`
+	FolderId  int64  `path:"folderId"`
+	Name      string `json:"name"`
+}
+
+type RenewFileLockReq struct {
+	Id         int64 `path:"id"`
+	TtlSeconds int64 `json:"ttlSeconds,optional"`
+}
+
 type RestoreLayerReq struct {
 	Id int64 `path:"id"`
 }
@@ -754,11 +1191,62 @@ type RestoreLayerResp struct {
 	RestoredAt string `json:"restoredAt"`
 }
 
+type RestoreTrashFileReq struct {
+	ProjectId int64  `path:"projectId"`
+	FileId    int64  `path:"fileId"`
+	Name      string `json:"name,optional"` // 原位置已有同名文件时可以换名恢复
+}
+
+type RetentionPolicyItem struct {
+	Id          int64  `json:"id"`
+	ProjectId   int64  `json:"projectId"`
+	PathPattern string `json:"pathPattern"`
+	KeepLast    int64  `json:"keepLast"`
+	KeepDays    int64  `json:"keepDays"`
+	CreatedBy   int64  `json:"createdBy"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+type RetentionPolicyListResp struct {
+	List []RetentionPolicyItem `json:"list"`
+}
+
+type RevokeInvitationReq struct {
+	ProjectId    int64 `path:"id"`
+	InvitationId int64 `path:"invitationId"`
+}
+
+type RollbackChangesetReq struct {
+	Id int64 `path:"id"`
+}
+
 type RollbackVersionReq struct {
 	Id            int64 `path:"id"`
 	VersionNumber int64 `json:"versionNumber"`
 }
 
+type SearchProjectFilesReq struct {
+	ProjectId   int64  `path:"projectId"`
+	Q           string `form:"q,optional"`           // 在文本文件当前版本的内容中搜索
+	Name        string `form:"name,optional"`        // 文件名通配符，如 *.go
+	Category    string `form:"category,optional"`    // text / image / video / audio / binary / archive
+	Format      string `form:"format,optional"`      // 如 go、png
+	MinSize     int64  `form:"minSize,optional"`     // 当前版本大小下限（字节）
+	MaxSize     int64  `form:"maxSize,optional"`     // 当前版本大小上限（字节）
+	UpdatedFrom string `form:"updatedFrom,optional"` // 2006-01-02 或 2006-01-02 15:04:05
+	UpdatedTo   string `form:"updatedTo,optional"`
+	CreatedBy   int64  `form:"createdBy,optional"` // 当前版本的上传者
+	Page        int64  `form:"page,default=1"`
+	PageSize    int64  `form:"pageSize,default=20"`
+}
+
+type SetVersionLabelsReq struct {
+	Id        int64    `path:"id"`
+	VersionId int64    `path:"versionId"`
+	Labels    []string `json:"labels"`
+}
+
 type SoftwareItem struct {
 	Id              int64  `json:"id"`
 	ProjectId       int64  `json:"projectId"`
@@ -792,6 +1280,25 @@ type SoftwareTemplateResp struct {
 	UpdatedAt     string `json:"updatedAt"`
 }
 
+type StorageQuotaListResp struct {
+	List []StorageQuotaResp `json:"list"`
+	Page PageResp           `json:"page"`
+}
+
+type StorageQuotaReq struct {
+	Scope   string `path:"scope"`
+	ScopeId int64  `path:"scopeId"`
+}
+
+type StorageQuotaResp struct {
+	Scope             string `json:"scope"`
+	ScopeId           int64  `json:"scopeId"`
+	LimitBytes        int64  `json:"limitBytes"`
+	DefaultLimitBytes int64  `json:"defaultLimitBytes"`
+	Overridden        bool   `json:"overridden"`
+	UsedBytes         int64  `json:"usedBytes"`
+}
+
 type SyncLayersReq struct {
 	ProjectId int64        `path:"projectId"`
 	Layers    []LayerInput `json:"layers"`
@@ -804,6 +1311,38 @@ type SyncLayersResp struct {
 	LayerMapping map[string]int64 `json:"layerMapping"`
 }
 
+type TransferOwnershipReq struct {
+	ProjectId         int64  `path:"id"`
+	NewOwnerId        int64  `json:"newOwnerId"`
+	PreviousOwnerRole string `json:"previousOwnerRole,optional"` // admin | developer | viewer | none，默认 admin
+}
+
+type TransferOwnershipResp struct {
+	ProjectId         int64  `json:"projectId"`
+	OwnerId           int64  `json:"ownerId"`
+	PreviousOwnerId   int64  `json:"previousOwnerId"`
+	PreviousOwnerRole string `json:"previousOwnerRole"`
+}
+
+type TrashItem struct {
+	FileId        int64  `json:"fileId"`
+	Name          string `json:"name"`
+	Path          string `json:"path"` // 删除时的项目内路径
+	FileCategory  string `json:"fileCategory"`
+	FileFormat    string `json:"fileFormat"`
+	VersionCount  int64  `json:"versionCount"`
+	SizeBytes     int64  `json:"sizeBytes"` // 全部版本大小之和
+	DeletedBy     int64  `json:"deletedBy"`
+	DeletedByName string `json:"deletedByName"`
+	DeletedAt     string `json:"deletedAt"`
+	PurgeAt       string `json:"purgeAt"` // 预计彻底删除的时间
+}
+
+type TrashListResp struct {
+	List []TrashItem `json:"list"`
+	Page PageResp    `json:"page"`
+}
+
 type UpdateAdminReq struct {
 	Id       int64  `path:"id"`
 	Password string `json:"password,optional"`
@@ -862,6 +1401,12 @@ type UpdateLlmProviderReq struct {
 	Description string `json:"description,optional"`
 }
 
+type UpdateProjectMemberReq struct {
+	ProjectId int64  `path:"id"`
+	UserId    int64  `path:"userId"`
+	Role      string `json:"role"` // owner | admin | developer | viewer
+}
+
 type UpdateProjectReq struct {
 	Id          int64  `path:"id"`
 	Name        string `json:"name,optional"`
@@ -870,6 +1415,13 @@ type UpdateProjectReq struct {
 	Status      string `json:"status,optional"` // active | archived
 }
 
+type UpdateRetentionPolicyReq struct {
+	ProjectId int64 `path:"projectId"`
+	PolicyId  int64 `path:"policyId"`
+	KeepLast  int64 `json:"keepLast,optional"`
+	KeepDays  int64 `json:"keepDays,optional"`
+}
+
 type UpdateSoftwareTemplateReq struct {
 	Id            int64  `path:"id"`
 	Name          string `json:"name,optional"`
@@ -877,11 +1429,30 @@ type UpdateSoftwareTemplateReq struct {
 	ArchiveFileId int64  `json:"archiveFileId,optional"`
 }
 
+type UpdateStorageQuotaReq struct {
+	Scope      string `path:"scope"`
+	ScopeId    int64  `path:"scopeId"`
+	LimitBytes int64  `json:"limitBytes"`
+}
+
 type UpdateUserReq struct {
 	Id       int64  `path:"id"`
 	Username string `json:"username"`
 }
 
+type UploadedPartItem struct {
+	PartNumber int64  `json:"partNumber"`
+	ETag       string `json:"etag"`
+	SizeBytes  int64  `json:"sizeBytes,optional"`
+}
+
+type UploadedPartListResp struct {
+	Status    string             `json:"status"` // uploading | completed | aborted
+	PartSize  int64              `json:"partSize"`
+	PartCount int64              `json:"partCount"`
+	Parts     []UploadedPartItem `json:"parts"`
+}
+
 type UserInfoResp struct {
 	Id           int64  `json:"id"`
 	Username     string `json:"username"`
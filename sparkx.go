@@ -11,11 +11,14 @@ import (
 	"strings"
 
 	"github.com/anil-wu/spark-x/internal/config"
+	"github.com/anil-wu/spark-x/internal/errorx"
 	"github.com/anil-wu/spark-x/internal/handler"
+	"github.com/anil-wu/spark-x/internal/jobs"
 	"github.com/anil-wu/spark-x/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
 var configFile = flag.String("f", "etc/sparkx-api.yaml", "the config file")
@@ -83,11 +86,29 @@ func main() {
 		}
 	}
 
+	if rootDir := strings.TrimSpace(os.Getenv("LOCAL_STORAGE_ROOT")); rootDir != "" {
+		c.Local.RootDir = rootDir
+	}
+	if baseURL := strings.TrimSpace(os.Getenv("LOCAL_STORAGE_PUBLIC_BASE_URL")); baseURL != "" {
+		c.Local.PublicBaseURL = baseURL
+	}
+	if signSecret := os.Getenv("LOCAL_STORAGE_SIGN_SECRET"); strings.TrimSpace(signSecret) != "" {
+		c.Local.SignSecret = signSecret
+	}
+	if expireSeconds := strings.TrimSpace(os.Getenv("LOCAL_STORAGE_EXPIRE_SECONDS")); expireSeconds != "" {
+		if v, err := strconv.ParseInt(expireSeconds, 10, 64); err == nil {
+			c.Local.ExpireSeconds = v
+		}
+	}
+
 	server := rest.MustNewServer(c.RestConf, rest.WithCors())
 	defer server.Stop()
 
+	httpx.SetErrorHandlerCtx(errorx.Handler)
+
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
+	jobs.Start(ctx)
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()
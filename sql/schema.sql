@@ -53,17 +53,56 @@ CREATE TABLE IF NOT EXISTS `project_members` (
   KEY `idx_project_members_user_id` (`user_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- project_invitations
+CREATE TABLE IF NOT EXISTS `project_invitations` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `email` VARCHAR(255) NOT NULL COMMENT '被邀请者邮箱，小写',
+  `role` ENUM('owner','admin','developer','viewer') NOT NULL,
+  `token` VARCHAR(128) NOT NULL DEFAULT '' COMMENT '签名的邀请令牌，带过期时间',
+  `invited_by` BIGINT UNSIGNED NOT NULL,
+  `invitee_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '邮箱对应的用户，未注册时为 0，首次登录时关联',
+  `status` ENUM('pending','accepted','declined','revoked') NOT NULL DEFAULT 'pending',
+  `expires_at` TIMESTAMP NOT NULL,
+  `responded_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_project_invitations_project_status` (`project_id`, `status`),
+  KEY `idx_project_invitations_email_status` (`email`, `status`),
+  KEY `idx_project_invitations_invitee_status` (`invitee_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- files
 CREATE TABLE IF NOT EXISTS `files` (
   `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
   `name` VARCHAR(255) NOT NULL,
   `file_category` ENUM('text','image','video','audio','binary','archive') NOT NULL,
   `file_format` VARCHAR(50) NOT NULL COMMENT '文件格式，如 png, jpg, mp4, mp3, txt 等',
+  `folder_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所在目录，0 表示项目根目录',
   `current_version_id` BIGINT UNSIGNED DEFAULT NULL COMMENT '当前版本ID，关联 file_versions.id',
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '移入回收站的时间，保留期过后彻底删除',
+  `deleted_by` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `deleted_path` VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '删除时的项目内路径，恢复时目录已不存在则按此重建',
+  PRIMARY KEY (`id`),
+  KEY `idx_files_current_version_id` (`current_version_id`),
+  KEY `idx_files_folder_id_name` (`folder_id`, `name`),
+  KEY `idx_files_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- folders
+CREATE TABLE IF NOT EXISTS `folders` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `parent_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '上级目录，0 表示项目根目录',
+  `name` VARCHAR(255) NOT NULL,
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
-  KEY `idx_files_current_version_id` (`current_version_id`)
+  UNIQUE KEY `uk_folders_project_parent_name` (`project_id`, `parent_id`, `name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 -- project_files
@@ -89,9 +128,160 @@ CREATE TABLE IF NOT EXISTS `file_versions` (
   `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `created_by` BIGINT UNSIGNED NOT NULL,
+  `status` ENUM('pending','ready','failed') NOT NULL DEFAULT 'ready' COMMENT '上传状态，complete 校验通过后为 ready',
+  `message` VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '版本说明',
+  `changeset_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属变更集，0 表示单独上传',
+  `base_version_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '上传时客户端基于的当前版本，complete 时再次校验，0 表示不校验',
   PRIMARY KEY (`id`),
   UNIQUE KEY `uk_file_version` (`file_id`,`version_number`),
-  KEY `idx_file_versions_file_id` (`file_id`)
+  KEY `idx_file_versions_file_id` (`file_id`),
+  KEY `idx_file_versions_status_created_at` (`status`,`created_at`),
+  KEY `idx_file_versions_changeset_id` (`changeset_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- file_version_contents
+CREATE TABLE IF NOT EXISTS `file_version_contents` (
+  `version_id` BIGINT UNSIGNED NOT NULL,
+  `file_id` BIGINT UNSIGNED NOT NULL,
+  `content` MEDIUMTEXT NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`version_id`),
+  KEY `idx_file_version_contents_file_id` (`file_id`),
+  FULLTEXT KEY `ft_file_version_contents_content` (`content`) WITH PARSER ngram
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- version_retention_policies
+CREATE TABLE IF NOT EXISTS `version_retention_policies` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `path_pattern` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '为空表示项目默认策略',
+  `keep_last` INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '保留最近 N 个版本，0 表示不按数量保留',
+  `keep_days` INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '保留 N 天内的版本，0 表示不按时间保留',
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_version_retention_policies_project_pattern` (`project_id`, `path_pattern`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- file_locks
+CREATE TABLE IF NOT EXISTS `file_locks` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `file_id` BIGINT UNSIGNED NOT NULL,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `locked_by` BIGINT UNSIGNED NOT NULL COMMENT '持有者用户ID',
+  `note` VARCHAR(255) NOT NULL DEFAULT '',
+  `expires_at` TIMESTAMP NOT NULL COMMENT '过期后视为已释放',
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_file_locks_file_id` (`file_id`),
+  KEY `idx_file_locks_project_id` (`project_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- changesets
+CREATE TABLE IF NOT EXISTS `changesets` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `message` VARCHAR(1024) NOT NULL DEFAULT '',
+  `status` ENUM('open','committed','aborted','rolled_back') NOT NULL DEFAULT 'open',
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `committed_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_changesets_project_status` (`project_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- changeset_files
+CREATE TABLE IF NOT EXISTS `changeset_files` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `changeset_id` BIGINT UNSIGNED NOT NULL,
+  `file_id` BIGINT UNSIGNED NOT NULL,
+  `version_id` BIGINT UNSIGNED NOT NULL,
+  `previous_version_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '提交时被替换的当前版本',
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_changeset_files_changeset_file` (`changeset_id`, `file_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- file_version_labels
+CREATE TABLE IF NOT EXISTS `file_version_labels` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `file_id` BIGINT UNSIGNED NOT NULL,
+  `version_id` BIGINT UNSIGNED NOT NULL,
+  `label` VARCHAR(64) NOT NULL COMMENT '如 approved、release-1.2',
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_file_version_labels_version_label` (`version_id`, `label`),
+  KEY `idx_file_version_labels_file_label` (`file_id`, `label`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- file_blobs
+CREATE TABLE IF NOT EXISTS `file_blobs` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `hash` VARCHAR(128) NOT NULL COMMENT '内容 sha256',
+  `storage_key` VARCHAR(512) NOT NULL,
+  `size_bytes` BIGINT UNSIGNED NOT NULL,
+  `ref_count` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '引用该对象的 file_versions 数量',
+  `verified` BOOLEAN NOT NULL DEFAULT FALSE COMMENT '对象内容已通过 hash 校验',
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_file_blobs_hash` (`hash`),
+  KEY `idx_file_blobs_storage_key` (`storage_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- multipart_uploads
+CREATE TABLE IF NOT EXISTS `multipart_uploads` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `upload_id` VARCHAR(255) NOT NULL COMMENT '对象存储返回的分片上传ID',
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `file_id` BIGINT UNSIGNED NOT NULL,
+  `file_version_id` BIGINT UNSIGNED NOT NULL,
+  `storage_key` VARCHAR(512) NOT NULL,
+  `content_type` VARCHAR(255) NOT NULL DEFAULT '',
+  `size_bytes` BIGINT UNSIGNED NOT NULL,
+  `part_size` BIGINT UNSIGNED NOT NULL,
+  `status` ENUM('uploading','completed','aborted') NOT NULL DEFAULT 'uploading',
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_multipart_uploads_project_id` (`project_id`),
+  KEY `idx_multipart_uploads_file_version_id` (`file_version_id`),
+  KEY `idx_multipart_uploads_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- storage_quotas
+CREATE TABLE IF NOT EXISTS `storage_quotas` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `scope` ENUM('project','user') NOT NULL,
+  `scope_id` BIGINT UNSIGNED NOT NULL,
+  `limit_bytes` BIGINT NOT NULL DEFAULT 0 COMMENT '0 表示不限制',
+  `updated_by` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_storage_quotas_scope` (`scope`, `scope_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- preview_uploads
+CREATE TABLE IF NOT EXISTS `preview_uploads` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `project_id` BIGINT UNSIGNED NOT NULL,
+  `build_version_id` BIGINT UNSIGNED NOT NULL,
+  `storage_key` VARCHAR(512) NOT NULL,
+  `size_bytes` BIGINT UNSIGNED NOT NULL,
+  `created_by` BIGINT UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_preview_uploads_storage_key` (`storage_key`),
+  KEY `idx_preview_uploads_project_id` (`project_id`),
+  KEY `idx_preview_uploads_created_by` (`created_by`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 -- admins
@@ -337,6 +337,11 @@ func TestGetFileContent(t *testing.T) {
 
 // uploadFileVersion 辅助函数：上传文件版本
 func uploadFileVersion(t *testing.T, client *tests.TestClient, projectId int64, fileName, category, format string, content []byte) int64 {
+	return uploadFileContent(t, client, projectId, fileName, category, format, content).FileId
+}
+
+// uploadFileContent 辅助函数：预上传、直传并确认完成，返回预上传结果
+func uploadFileContent(t *testing.T, client *tests.TestClient, projectId int64, fileName, category, format string, content []byte) PreUploadResp {
 	sizeBytes := int64(len(content))
 	hash := sha256.Sum256(content)
 	hashStr := hex.EncodeToString(hash[:])
@@ -356,6 +361,10 @@ func uploadFileVersion(t *testing.T, client *tests.TestClient, projectId int64,
 	if preUploadResp.FileId == 0 {
 		t.Fatal("PreUpload failed")
 	}
+	// 相同内容已存在时无需上传
+	if preUploadResp.SkipUpload {
+		return preUploadResp
+	}
 
 	// 上传文件到 OSS
 	uploadReq, err := http.NewRequest("PUT", preUploadResp.UploadUrl, bytes.NewReader(content))
@@ -385,7 +394,14 @@ func uploadFileVersion(t *testing.T, client *tests.TestClient, projectId int64,
 		t.Fatalf("OSS upload failed. Status: %d, Body: %s", uploadResp.StatusCode, string(uploadRespBody))
 	}
 
-	return preUploadResp.FileId
+	// 确认上传完成，版本校验通过后成为当前版本
+	var completeResp FileVersionItem
+	client.Post(fmt.Sprintf("/files/%d/versions/%d/complete", preUploadResp.FileId, preUploadResp.VersionId), nil, &completeResp)
+	if completeResp.Status != "ready" {
+		t.Fatalf("Complete version failed, status: %s", completeResp.Status)
+	}
+
+	return preUploadResp
 }
 
 // TestFileDownloadPermission 测试文件下载权限（项目成员才能下载）
@@ -181,6 +181,13 @@ func testUploadFile(t *testing.T, client *tests.TestClient, projectId int64, fil
 	}
 	t.Logf("OSS upload successful. Status: %d", uploadResp.StatusCode)
 
+	// 确认上传完成
+	var completeResp FileVersionItem
+	client.Post(fmt.Sprintf("/files/%d/versions/%d/complete", preUploadResp.FileId, preUploadResp.VersionId), nil, &completeResp)
+	if completeResp.Status != "ready" {
+		t.Fatalf("Complete version failed, status: %s", completeResp.Status)
+	}
+
 	// 验证文件列表
 	t.Log("Verifying file list...")
 	var fileListResp ProjectFileListResp
@@ -103,6 +103,7 @@ type PreUploadResp struct {
 	VersionId     int64  `json:"versionId"`
 	VersionNumber int64  `json:"versionNumber"`
 	ContentType   string `json:"contentType"`
+	SkipUpload    bool   `json:"skipUpload"`
 }
 
 type ProjectFileItem struct {
@@ -132,6 +133,7 @@ type FileVersionItem struct {
 	SizeBytes     int64  `json:"sizeBytes"`
 	Hash          string `json:"hash"`
 	StorageKey    string `json:"storageKey"`
+	Status        string `json:"status"`
 	CreatedAt     string `json:"createdAt"`
 	UpdatedAt     string `json:"updatedAt"`
 	CreatedBy     int64  `json:"createdBy"`
@@ -325,6 +327,13 @@ func TestWorkflow(t *testing.T) {
 		t.Fatalf("UpdateProject failed: code=%d msg=%s", updateProjectResp.Code, updateProjectResp.Msg)
 	}
 
+	// 归档项目只读，恢复为 active 后继续上传文件
+	var restoreProjectResp BaseResp
+	client.Put(fmt.Sprintf("/projects/%d", projectResp.Id), UpdateProjectReq{Status: "active"}, &restoreProjectResp)
+	if restoreProjectResp.Code != 0 {
+		t.Fatalf("Unarchive project failed: code=%d msg=%s", restoreProjectResp.Code, restoreProjectResp.Msg)
+	}
+
 	// List Projects
 	t.Log("Step 2.4: List Projects")
 	var projectListResp ProjectListResp
@@ -357,19 +366,10 @@ func TestWorkflow(t *testing.T) {
 	// 3. Files
 	// ==========================================
 
-	// PreUpload File
-	t.Log("Step 3.1: PreUpload File")
-	preUploadReq := PreUploadReq{
-		ProjectId:    projectResp.Id,
-		Name:         "test_doc.txt",
-		FileCategory: "text",
-		FileFormat:   "txt",
-		SizeBytes:    2048,
-		Hash:         "testhash123456",
-	}
-	var preUploadResp PreUploadResp
-	client.Post("/files/preupload", preUploadReq, &preUploadResp)
-	t.Logf("PreUpload successful. FileID: %d", preUploadResp.FileId)
+	// Upload File
+	t.Log("Step 3.1: Upload File")
+	preUploadResp := uploadFileContent(t, client, projectResp.Id, "test_doc.txt", "text", "txt", []byte(fmt.Sprintf("integration test doc %d", rand.Int63())))
+	t.Logf("Upload successful. FileID: %d", preUploadResp.FileId)
 
 	// List Project Files
 	t.Log("Step 3.2: List Project Files")
@@ -394,6 +394,11 @@ func TestWorkflow(t *testing.T) {
 	if len(versionListResp.List) == 0 {
 		t.Error("No versions found for file")
 	}
+	for _, v := range versionListResp.List {
+		if v.Id == preUploadResp.VersionId && v.Status != "ready" {
+			t.Errorf("Version %d status: got %s, want ready", v.Id, v.Status)
+		}
+	}
 
 	// ==========================================
 	// 4. Build Versions
@@ -409,19 +414,10 @@ func TestWorkflow(t *testing.T) {
 		t.Fatal("CreateSoftware failed")
 	}
 
-	t.Log("Step 4.2: PreUpload Software Manifest File")
-	preUploadManifestReq := PreUploadReq{
-		ProjectId:    projectResp.Id,
-		Name:         "software.manifest.json",
-		FileCategory: "text",
-		FileFormat:   "json",
-		SizeBytes:    1024,
-		Hash:         "manifesthash123456",
-	}
-	var preUploadManifestResp PreUploadResp
-	client.Post("/files/preupload", preUploadManifestReq, &preUploadManifestResp)
+	t.Log("Step 4.2: Upload Software Manifest File")
+	preUploadManifestResp := uploadFileContent(t, client, projectResp.Id, "software.manifest.json", "text", "json", []byte(fmt.Sprintf(`{"name":"it","nonce":%d}`, rand.Int63())))
 	if preUploadManifestResp.FileId == 0 || preUploadManifestResp.VersionId == 0 {
-		t.Fatal("PreUpload Software Manifest failed")
+		t.Fatal("Upload Software Manifest failed")
 	}
 
 	t.Log("Step 4.3: Create Software Manifest")
@@ -438,19 +434,10 @@ func TestWorkflow(t *testing.T) {
 		t.Fatal("CreateSoftwareManifest failed")
 	}
 
-	t.Log("Step 4.4: PreUpload Build Manifest File")
-	preUploadBuildReq := PreUploadReq{
-		ProjectId:    projectResp.Id,
-		Name:         "build.manifest.json",
-		FileCategory: "text",
-		FileFormat:   "json",
-		SizeBytes:    1024,
-		Hash:         "buildhash123456",
-	}
-	var preUploadBuildResp PreUploadResp
-	client.Post("/files/preupload", preUploadBuildReq, &preUploadBuildResp)
+	t.Log("Step 4.4: Upload Build Manifest File")
+	preUploadBuildResp := uploadFileContent(t, client, projectResp.Id, "build.manifest.json", "text", "json", []byte(fmt.Sprintf(`{"build":"it","nonce":%d}`, rand.Int63())))
 	if preUploadBuildResp.FileId == 0 || preUploadBuildResp.VersionId == 0 {
-		t.Fatal("PreUpload Build Manifest failed")
+		t.Fatal("Upload Build Manifest failed")
 	}
 
 	t.Log("Step 4.5: Create Build Version")
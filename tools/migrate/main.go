@@ -68,19 +68,51 @@ type ProjectMembersTable struct {
 
 func (ProjectMembersTable) TableName() string { return "project_members" }
 
+type ProjectInvitationsTable struct {
+	Id          uint64       `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId   uint64       `gorm:"column:project_id;not null;index:idx_project_invitations_project_status,priority:1"`
+	Email       string       `gorm:"column:email;type:varchar(255);not null;index:idx_project_invitations_email_status,priority:1"`
+	Role        string       `gorm:"column:role;type:enum('owner','admin','developer','viewer');not null"`
+	Token       string       `gorm:"column:token;type:varchar(128);not null;default:''"`
+	InvitedBy   uint64       `gorm:"column:invited_by;not null"`
+	InviteeId   uint64       `gorm:"column:invitee_id;not null;default:0;index:idx_project_invitations_invitee_status,priority:1"`
+	Status      string       `gorm:"column:status;type:enum('pending','accepted','declined','revoked');not null;default:'pending';index:idx_project_invitations_project_status,priority:2;index:idx_project_invitations_email_status,priority:2;index:idx_project_invitations_invitee_status,priority:2"`
+	ExpiresAt   time.Time    `gorm:"column:expires_at;not null"`
+	RespondedAt sql.NullTime `gorm:"column:responded_at"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ProjectInvitationsTable) TableName() string { return "project_invitations" }
+
 type FilesTable struct {
 	Id               uint64         `gorm:"column:id;primaryKey;autoIncrement"`
-	Name             string         `gorm:"column:name;type:varchar(255);not null"`
+	Name             string         `gorm:"column:name;type:varchar(255);not null;index:idx_files_folder_id_name,priority:2"`
 	FileCategory     string         `gorm:"column:file_category;type:enum('text','image','video','audio','binary','archive');not null"`
 	FileFormat       string         `gorm:"column:file_format;type:varchar(50);not null;default:''"`
+	FolderId         uint64         `gorm:"column:folder_id;not null;default:0;index:idx_files_folder_id_name,priority:1"`
 	CurrentVersionId uint64         `gorm:"column:current_version_id;index:idx_files_current_version_id"`
 	CreatedAt        time.Time      `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt        time.Time      `gorm:"column:updated_at;autoUpdateTime"`
 	DeletedAt        gorm.DeletedAt `gorm:"column:deleted_at;index;softDelete"`
+	DeletedBy        uint64         `gorm:"column:deleted_by;not null;default:0"`
+	DeletedPath      string         `gorm:"column:deleted_path;type:varchar(1024);not null;default:''"`
 }
 
 func (FilesTable) TableName() string { return "files" }
 
+type FoldersTable struct {
+	Id        uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId uint64    `gorm:"column:project_id;not null;uniqueIndex:uk_folders_project_parent_name,priority:1"`
+	ParentId  uint64    `gorm:"column:parent_id;not null;default:0;uniqueIndex:uk_folders_project_parent_name,priority:2"`
+	Name      string    `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uk_folders_project_parent_name,priority:3"`
+	CreatedBy uint64    `gorm:"column:created_by;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (FoldersTable) TableName() string { return "folders" }
+
 type ProjectFilesTable struct {
 	Id        uint64    `gorm:"column:id;primaryKey;autoIncrement"`
 	ProjectId uint64    `gorm:"column:project_id;not null;uniqueIndex:uk_project_file,priority:1"`
@@ -98,13 +130,144 @@ type FileVersionsTable struct {
 	SizeBytes     uint64    `gorm:"column:size_bytes;not null"`
 	Hash          string    `gorm:"column:hash;type:varchar(128);not null"`
 	StorageKey    string    `gorm:"column:storage_key;type:varchar(512);not null"`
-	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime;index:idx_file_versions_status_created_at,priority:2"`
 	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
 	CreatedBy     uint64    `gorm:"column:created_by;not null"`
+	Status        string    `gorm:"column:status;type:enum('pending','ready','failed');not null;default:'ready';index:idx_file_versions_status_created_at,priority:1"`
+	Message       string    `gorm:"column:message;type:varchar(1024);not null;default:''"`
+	ChangesetId   uint64    `gorm:"column:changeset_id;not null;default:0;index:idx_file_versions_changeset_id"`
+	BaseVersionId uint64    `gorm:"column:base_version_id;not null;default:0"`
 }
 
 func (FileVersionsTable) TableName() string { return "file_versions" }
 
+type FileVersionContentsTable struct {
+	VersionId uint64    `gorm:"column:version_id;primaryKey;autoIncrement:false"`
+	FileId    uint64    `gorm:"column:file_id;not null;index:idx_file_version_contents_file_id"`
+	Content   string    `gorm:"column:content;type:mediumtext;not null;index:ft_file_version_contents_content,class:FULLTEXT,option:WITH PARSER ngram"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (FileVersionContentsTable) TableName() string { return "file_version_contents" }
+
+type VersionRetentionPoliciesTable struct {
+	Id          uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId   uint64    `gorm:"column:project_id;not null;uniqueIndex:uk_version_retention_policies_project_pattern,priority:1"`
+	PathPattern string    `gorm:"column:path_pattern;type:varchar(255);not null;default:'';uniqueIndex:uk_version_retention_policies_project_pattern,priority:2"`
+	KeepLast    uint64    `gorm:"column:keep_last;type:int unsigned;not null;default:0"`
+	KeepDays    uint64    `gorm:"column:keep_days;type:int unsigned;not null;default:0"`
+	CreatedBy   uint64    `gorm:"column:created_by;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (VersionRetentionPoliciesTable) TableName() string { return "version_retention_policies" }
+
+type FileLocksTable struct {
+	Id        uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	FileId    uint64    `gorm:"column:file_id;not null;uniqueIndex:uk_file_locks_file_id"`
+	ProjectId uint64    `gorm:"column:project_id;not null;index:idx_file_locks_project_id"`
+	LockedBy  uint64    `gorm:"column:locked_by;not null"`
+	Note      string    `gorm:"column:note;type:varchar(255);not null;default:''"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (FileLocksTable) TableName() string { return "file_locks" }
+
+type ChangesetsTable struct {
+	Id          uint64       `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId   uint64       `gorm:"column:project_id;not null;index:idx_changesets_project_status,priority:1"`
+	Message     string       `gorm:"column:message;type:varchar(1024);not null;default:''"`
+	Status      string       `gorm:"column:status;type:enum('open','committed','aborted','rolled_back');not null;default:'open';index:idx_changesets_project_status,priority:2"`
+	CreatedBy   uint64       `gorm:"column:created_by;not null"`
+	CommittedAt sql.NullTime `gorm:"column:committed_at"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ChangesetsTable) TableName() string { return "changesets" }
+
+type ChangesetFilesTable struct {
+	Id                uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ChangesetId       uint64    `gorm:"column:changeset_id;not null;uniqueIndex:uk_changeset_files_changeset_file,priority:1"`
+	FileId            uint64    `gorm:"column:file_id;not null;uniqueIndex:uk_changeset_files_changeset_file,priority:2"`
+	VersionId         uint64    `gorm:"column:version_id;not null"`
+	PreviousVersionId uint64    `gorm:"column:previous_version_id;not null;default:0"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ChangesetFilesTable) TableName() string { return "changeset_files" }
+
+type FileVersionLabelsTable struct {
+	Id        uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	FileId    uint64    `gorm:"column:file_id;not null;index:idx_file_version_labels_file_label,priority:1"`
+	VersionId uint64    `gorm:"column:version_id;not null;uniqueIndex:uk_file_version_labels_version_label,priority:1"`
+	Label     string    `gorm:"column:label;type:varchar(64);not null;uniqueIndex:uk_file_version_labels_version_label,priority:2;index:idx_file_version_labels_file_label,priority:2"`
+	CreatedBy uint64    `gorm:"column:created_by;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (FileVersionLabelsTable) TableName() string { return "file_version_labels" }
+
+type FileBlobsTable struct {
+	Id         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	Hash       string    `gorm:"column:hash;type:varchar(128);not null;uniqueIndex:uk_file_blobs_hash"`
+	StorageKey string    `gorm:"column:storage_key;type:varchar(512);not null;index:idx_file_blobs_storage_key"`
+	SizeBytes  uint64    `gorm:"column:size_bytes;not null"`
+	RefCount   uint64    `gorm:"column:ref_count;not null;default:0"`
+	Verified   bool      `gorm:"column:verified;not null;default:false"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (FileBlobsTable) TableName() string { return "file_blobs" }
+
+type MultipartUploadsTable struct {
+	Id            uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	UploadId      string    `gorm:"column:upload_id;type:varchar(255);not null"`
+	ProjectId     uint64    `gorm:"column:project_id;not null;index:idx_multipart_uploads_project_id"`
+	FileId        uint64    `gorm:"column:file_id;not null"`
+	FileVersionId uint64    `gorm:"column:file_version_id;not null;index:idx_multipart_uploads_file_version_id"`
+	StorageKey    string    `gorm:"column:storage_key;type:varchar(512);not null"`
+	ContentType   string    `gorm:"column:content_type;type:varchar(255);not null;default:''"`
+	SizeBytes     uint64    `gorm:"column:size_bytes;not null"`
+	PartSize      uint64    `gorm:"column:part_size;not null"`
+	Status        string    `gorm:"column:status;type:enum('uploading','completed','aborted');not null;default:'uploading';index:idx_multipart_uploads_status"`
+	CreatedBy     uint64    `gorm:"column:created_by;not null"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (MultipartUploadsTable) TableName() string { return "multipart_uploads" }
+
+type StorageQuotasTable struct {
+	Id         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	Scope      string    `gorm:"column:scope;type:enum('project','user');not null;uniqueIndex:uk_storage_quotas_scope,priority:1"`
+	ScopeId    uint64    `gorm:"column:scope_id;not null;uniqueIndex:uk_storage_quotas_scope,priority:2"`
+	LimitBytes int64     `gorm:"column:limit_bytes;not null;default:0"`
+	UpdatedBy  uint64    `gorm:"column:updated_by;not null;default:0"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (StorageQuotasTable) TableName() string { return "storage_quotas" }
+
+type PreviewUploadsTable struct {
+	Id             uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ProjectId      uint64    `gorm:"column:project_id;not null;index:idx_preview_uploads_project_id"`
+	BuildVersionId uint64    `gorm:"column:build_version_id;not null"`
+	StorageKey     string    `gorm:"column:storage_key;type:varchar(512);not null;uniqueIndex:uk_preview_uploads_storage_key"`
+	SizeBytes      uint64    `gorm:"column:size_bytes;not null"`
+	CreatedBy      uint64    `gorm:"column:created_by;not null;index:idx_preview_uploads_created_by"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (PreviewUploadsTable) TableName() string { return "preview_uploads" }
+
 type AdminsTable struct {
 	Id           uint64       `gorm:"column:id;primaryKey;autoIncrement"`
 	Username     string       `gorm:"column:username;type:varchar(64);not null;uniqueIndex:uk_admins_username"`
@@ -441,9 +604,21 @@ func migrateWithRetry(targetDSN string) error {
 				&UserIdentitiesTable{},
 				&ProjectsTable{},
 				&ProjectMembersTable{},
+				&ProjectInvitationsTable{},
 				&FilesTable{},
+				&FoldersTable{},
 				&ProjectFilesTable{},
 				&FileVersionsTable{},
+				&FileVersionLabelsTable{},
+				&FileVersionContentsTable{},
+				&VersionRetentionPoliciesTable{},
+				&FileLocksTable{},
+				&ChangesetsTable{},
+				&ChangesetFilesTable{},
+				&FileBlobsTable{},
+				&MultipartUploadsTable{},
+				&StorageQuotasTable{},
+				&PreviewUploadsTable{},
 				&AdminsTable{},
 				&SoftwareTemplatesTable{},
 				&SoftwaresTable{},
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/anil-wu/spark-x/internal/config"
+	"github.com/anil-wu/spark-x/internal/jobs"
+	"github.com/anil-wu/spark-x/internal/svc"
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+var (
+	configFile = flag.String("f", "etc/sparkx-api.yaml", "the config file")
+	deleteFlag = flag.Bool("delete", false, "delete orphan objects older than the grace period")
+	grace      = flag.Duration("grace", 0, "grace period before orphans are deleted (default from config)")
+)
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c, conf.UseEnv())
+
+	svcCtx := svc.NewServiceContext(c)
+	opts := jobs.StorageGCOptions{
+		Grace:  svcCtx.StorageGCGrace(),
+		Delete: *deleteFlag,
+	}
+	if *grace > 0 {
+		opts.Grace = *grace
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Hour)
+	defer cancel()
+	report, err := jobs.RunStorageGC(ctx, svcCtx, opts)
+	if err != nil {
+		log.Fatalf("storage gc failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+}